@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"reflect"
+
+	"github.com/NVIDIA/mig-parted/internal/jsonschema"
+	"github.com/NVIDIA/mig-parted/internal/nvlib/mig"
+)
+
+// Schema returns the JSON Schema of a checkpoint file, generated from the 'State' type.
+func Schema() *jsonschema.Schema {
+	r := jsonschema.Reflector{
+		Fields: map[string]*jsonschema.Schema{
+			"State.Version": {Type: jsonschema.Types{"string"}, Const: Version},
+		},
+	}
+
+	schema := r.Reflect(reflect.TypeFor[State]())
+	schema.Title = "nvidia-mig-parted checkpoint file"
+
+	r.Definition("Mode").Enum = []interface{}{mig.Disabled, mig.Enabled}
+
+	return schema
+}
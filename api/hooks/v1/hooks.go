@@ -18,25 +18,122 @@ package v1
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
 )
 
 // Version indicates the version of the 'Spec' struct used to hold 'Hooks' information.
 const Version = "v1"
 
+// Constants representing the set of named hooks run by 'nvidia-mig-parted'.
+const (
+	ApplyStartHook     = "apply-start"
+	PreApplyModeHook   = "pre-apply-mode"
+	PreApplyConfigHook = "pre-apply-config"
+	ApplyExitHook      = "apply-exit"
+)
+
+// KnownHooks lists the set of named hooks run by 'nvidia-mig-parted' in the order they are run.
+var KnownHooks = []string{
+	ApplyStartHook,
+	PreApplyModeHook,
+	PreApplyConfigHook,
+	ApplyExitHook,
+}
+
+// Constants representing the set of supported hook types.
+// A HookSpec without an explicit type is treated as a CommandHookType.
+const (
+	CommandHookType            = "command"
+	SystemdStopHookType        = "systemd-stop"
+	SystemdStartHookType       = "systemd-start"
+	WaitNoGPUProcessesHookType = "wait-no-gpu-processes"
+	HTTPHookType               = "http"
+)
+
+// Constants representing the set of supported failure policies for a hook.
+// A HookSpec without an explicit failure policy is treated as FailurePolicyFail.
+const (
+	FailurePolicyFail   = "fail"
+	FailurePolicyIgnore = "ignore"
+)
+
+// DefaultWaitNoGPUProcessesTimeout is the timeout used by a 'wait-no-gpu-processes' hook when none is specified.
+const DefaultWaitNoGPUProcessesTimeout = 60 * time.Second
+
+// MaxErrorOutputLines is the number of trailing lines of output from a failed 'command' hook attached to its error.
+const MaxErrorOutputLines = 20
+
+var log = logrus.StandardLogger()
+
+// SetLogger sets the logger used to report the output of hooks and any ignored failures.
+// It defaults to the standard logrus logger.
+func SetLogger(logger *logrus.Logger) {
+	log = logger
+}
+
 // Spec is a versioned struct used to hold 'Hooks' information.
 type Spec struct {
 	Version string   `json:"version"`
-	Hooks   HooksMap `json:"hooks"`
+	Hooks   HooksMap `json:"hooks,omitempty"`
 }
 
 // HookSpec holds the actual data associated with a runnable Hook.
+// The 'Command', 'Args', 'Envs', and 'Workdir' fields apply to hooks of type 'command'.
+// The 'Services' and 'NoBlock' fields apply to hooks of type 'systemd-stop' and 'systemd-start'.
+// The 'URL', 'Method', 'Headers', 'Body', 'Retries', 'RetryInterval', and 'CAFile' fields apply to hooks of type 'http'.
+// The 'Timeout' field applies to hooks of type 'wait-no-gpu-processes' and 'http'.
+// The 'When' and 'OnFailure' fields apply to hooks of all types.
 type HookSpec struct {
-	Command string   `json:"command"`
-	Args    []string `json:"args"`
-	Envs    EnvsMap  `json:"envs"`
-	Workdir string   `json:"workdir"`
+	Type          string            `json:"type,omitempty"`
+	Command       string            `json:"command,omitempty"`
+	Args          []string          `json:"args,omitempty"`
+	Envs          EnvsMap           `json:"envs,omitempty"`
+	Workdir       string            `json:"workdir,omitempty"`
+	Services      []string          `json:"services,omitempty"`
+	NoBlock       bool              `json:"no-block,omitempty"`
+	URL           string            `json:"url,omitempty"`
+	Method        string            `json:"method,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          string            `json:"body,omitempty"`
+	Retries       int               `json:"retries,omitempty"`
+	RetryInterval string            `json:"retry-interval,omitempty"`
+	CAFile        string            `json:"ca-file,omitempty"`
+	Timeout       string            `json:"timeout,omitempty"`
+	When          string            `json:"when,omitempty"`
+	OnFailure     string            `json:"on-failure,omitempty"`
+}
+
+// Actions implements the parts of the built-in hook types that depend on the environment the hooks are run in.
+// Implementations are expected to track which services they actually stopped so that
+// a subsequent call to StartSystemdServices only restarts those.
+type Actions interface {
+	StopSystemdServices(services []string) error
+	StartSystemdServices(services []string, noBlock bool) error
+	WaitForNoGPUProcesses(timeout time.Duration) error
+	GetHookContext() (*HookContext, error)
+	GetApplyContext() (*ApplyContext, error)
+}
+
+// HookContext holds information about the operation in progress that is made available to hooks.
+type HookContext struct {
+	Hostname       string       `json:"hostname"`
+	SelectedConfig string       `json:"selected-config,omitempty"`
+	GPUs           []GPUSummary `json:"gpus,omitempty"`
+}
+
+// GPUSummary holds a summary of the state of a single GPU on the node.
+type GPUSummary struct {
+	Index      int             `json:"index"`
+	DeviceID   string          `json:"device-id"`
+	MigCapable bool            `json:"mig-capable"`
+	MigEnabled bool            `json:"mig-enabled"`
+	MigDevices types.MigConfig `json:"mig-devices,omitempty"`
 }
 
 // EnvsMap holds the (key, value) pairs associated with a set of environment variables.
@@ -47,14 +144,20 @@ type HooksMap map[string][]HookSpec
 
 // Run executes all of the hooks associated with a given name in the HooksMap.
 // It injects the environment variables associated with the provided EnvMap,
-// and optionally prints the output for each hook to stdout and stderr.
+// and logs the output of each hook at info level if 'output' is set (and at debug level otherwise).
 func (h HooksMap) Run(name string, envs EnvsMap, output bool) error {
+	return h.RunWithActions(name, envs, output, nil)
+}
+
+// RunWithActions executes all of the hooks associated with a given name in the HooksMap.
+// Hooks of a built-in type are dispatched to the provided Actions.
+func (h HooksMap) RunWithActions(name string, envs EnvsMap, output bool, actions Actions) error {
 	hooks, exists := h[name]
 	if !exists {
 		return nil
 	}
-	for _, hook := range hooks {
-		err := hook.Run(envs, output)
+	for i, hook := range hooks {
+		err := hook.run(name, i, envs, output, actions)
 		if err != nil {
 			return err
 		}
@@ -64,16 +167,185 @@ func (h HooksMap) Run(name string, envs EnvsMap, output bool) error {
 
 // Run executes a specific hook from a HookSpec.
 // It injects the environment variables associated with the provided EnvMap,
-// and optionally prints the output for each hook to stdout and stderr.
+// and logs the output of the hook at info level if 'output' is set (and at debug level otherwise).
 func (h *HookSpec) Run(envs EnvsMap, output bool) error {
+	return h.RunWithActions("", envs, output, nil)
+}
+
+// RunWithActions executes a specific hook from a HookSpec as part of the named hook.
+// Hooks of type 'command' are executed as an external command,
+// and hooks of a built-in type are dispatched to the provided Actions.
+// Errors are only returned if the failure policy of the hook is 'fail'.
+func (h *HookSpec) RunWithActions(name string, envs EnvsMap, output bool, actions Actions) error {
+	return h.run(name, 0, envs, output, actions)
+}
+
+// run executes a HookSpec found at 'index' in the list of hooks for the named hook and applies its failure policy.
+// The hook is skipped if it has a 'when' clause that does not hold.
+func (h *HookSpec) run(name string, index int, envs EnvsMap, output bool, actions Actions) error {
+	err := h.runIfNeeded(name, index, envs, output, actions)
+	if err == nil {
+		return nil
+	}
+
+	switch h.GetOnFailure() {
+	case FailurePolicyFail:
+		return err
+	case FailurePolicyIgnore:
+		log.WithFields(logrus.Fields{"hook": name, "index": index}).Warnf("Ignoring failure of '%v' hook of type '%v': %v", name, h.GetType(), err)
+		return nil
+	}
+
+	return fmt.Errorf("unknown failure policy '%v'", h.OnFailure)
+}
+
+func (h *HookSpec) runIfNeeded(name string, index int, envs EnvsMap, output bool, actions Actions) error {
+	run, err := h.shouldRun(actions)
+	if err != nil {
+		return err
+	}
+	if !run {
+		log.WithFields(logrus.Fields{"hook": name, "index": index}).Debugf("Skipping '%v' hook of type '%v' (condition not met: %v)", name, h.GetType(), h.When)
+		return nil
+	}
+	return h.runByType(name, index, envs, output, actions)
+}
+
+func (h *HookSpec) runByType(name string, index int, envs EnvsMap, output bool, actions Actions) error {
+	hookType := h.GetType()
+	switch hookType {
+	case CommandHookType:
+		return h.runCommand(name, index, envs, output)
+	case HTTPHookType:
+		return h.runHTTP(name, actions)
+	}
+
+	if actions == nil {
+		return fmt.Errorf("no actions available to run hook of type '%v'", hookType)
+	}
+
+	switch hookType {
+	case SystemdStopHookType:
+		return actions.StopSystemdServices(h.Services)
+	case SystemdStartHookType:
+		return actions.StartSystemdServices(h.Services, h.NoBlock)
+	case WaitNoGPUProcessesHookType:
+		timeout, err := h.GetTimeout(DefaultWaitNoGPUProcessesTimeout)
+		if err != nil {
+			return err
+		}
+		return actions.WaitForNoGPUProcesses(timeout)
+	}
+
+	return fmt.Errorf("unknown hook type '%v'", hookType)
+}
+
+// shouldRun evaluates the 'when' clause of a HookSpec against the ApplyContext provided by 'actions'.
+// A HookSpec without a 'when' clause is always run.
+func (h *HookSpec) shouldRun(actions Actions) (bool, error) {
+	if h.When == "" {
+		return true, nil
+	}
+
+	condition, err := ParseCondition(h.When)
+	if err != nil {
+		return false, fmt.Errorf("invalid when '%v': %v", h.When, err)
+	}
+
+	if actions == nil {
+		return false, fmt.Errorf("no actions available to evaluate when '%v'", h.When)
+	}
+
+	applyContext, err := actions.GetApplyContext()
+	if err != nil {
+		return false, fmt.Errorf("error getting apply context to evaluate when '%v': %v", h.When, err)
+	}
+
+	return condition.Evaluate(applyContext), nil
+}
+
+// HasConditions returns true if any HookSpec in the HooksMap has a 'when' clause.
+func (h HooksMap) HasConditions() bool {
+	for _, hooks := range h {
+		for _, hook := range hooks {
+			if hook.When != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetType returns the type of a HookSpec, defaulting to 'command' if none is set.
+func (h *HookSpec) GetType() string {
+	if h.Type == "" {
+		return CommandHookType
+	}
+	return h.Type
+}
+
+// GetOnFailure returns the failure policy of a HookSpec, defaulting to 'fail' if none is set.
+func (h *HookSpec) GetOnFailure() string {
+	if h.OnFailure == "" {
+		return FailurePolicyFail
+	}
+	return h.OnFailure
+}
+
+// GetTimeout parses the 'Timeout' of a HookSpec, returning 'defaultTimeout' if none is set.
+func (h *HookSpec) GetTimeout(defaultTimeout time.Duration) (time.Duration, error) {
+	if h.Timeout == "" {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(h.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout '%v': %v", h.Timeout, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout '%v': must be positive", h.Timeout)
+	}
+	return timeout, nil
+}
+
+// runCommand executes a 'command' hook, logging each line of its output with the
+// 'hook', 'index', 'command', and 'stream' fields set. The last MaxErrorOutputLines
+// lines of output are attached to the error returned if the command fails.
+func (h *HookSpec) runCommand(name string, index int, envs EnvsMap, output bool) error {
+	entry := log.WithFields(logrus.Fields{
+		"hook":    name,
+		"index":   index,
+		"command": h.Command,
+	})
+
+	level := logrus.DebugLevel
+	if output {
+		level = logrus.InfoLevel
+	}
+
+	tail := newOutputTail(MaxErrorOutputLines)
+	stdout := newLineLogger(entry.WithField("stream", "stdout"), level, tail)
+	stderr := newLineLogger(entry.WithField("stream", "stderr"), level, tail)
+
 	cmd := exec.Command(h.Command, h.Args...) //nolint:gosec
 	cmd.Env = h.Envs.Combine(envs).Format()
 	cmd.Dir = h.Workdir
-	if output {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	stdout.Flush()
+	stderr.Flush()
+
+	if err == nil {
+		return nil
 	}
-	return cmd.Run()
+
+	lines := tail.Lines()
+	if len(lines) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w (last %d line(s) of output):\n%v", err, len(lines), strings.Join(lines, "\n"))
 }
 
 // Combine merges to EnvMaps together
@@ -18,53 +18,48 @@ package v1
 
 import (
 	"bytes"
-	"io"
-	"os"
-	"sync"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/yaml"
 )
 
-func captureOutput(f func() error) (string, error) {
-	reader, writer, err := os.Pipe()
-	if err != nil {
-		return "", err
+// captureLogs runs 'f' with the hooks logger replaced by one that records all entries at or above 'level'.
+func captureLogs(level logrus.Level, f func() error) ([]map[string]interface{}, error) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetLevel(level)
+
+	SetLogger(logger)
+	defer SetLogger(logrus.StandardLogger())
+
+	err := f()
+
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var entry map[string]interface{}
+		if derr := decoder.Decode(&entry); derr != nil {
+			return nil, derr
+		}
+		entries = append(entries, entry)
 	}
 
-	stdout := os.Stdout
-	stderr := os.Stderr
-	defer func() {
-		os.Stdout = stdout
-		os.Stderr = stderr
-	}()
-
-	os.Stdout = writer
-	os.Stderr = writer
-
-	out := make(chan string)
-	wg := new(sync.WaitGroup)
-	wg.Add(1)
-	go func() {
-		var buf bytes.Buffer
-		wg.Done()
-		_, _ = io.Copy(&buf, reader)
-		out <- buf.String()
-	}()
-
-	wg.Wait()
-	err = f()
-	writer.Close()
-
-	return <-out, err
+	return entries, err
 }
 
 func TestMarshallUnmarshall(t *testing.T) {
 	spec := Spec{
 		Version: "v1",
 		Hooks: HooksMap{
-			"hook0": []HookSpec{
+			ApplyStartHook: []HookSpec{
 				{
 					Workdir: "/wherever0",
 					Command: "whatever0",
@@ -84,7 +79,7 @@ func TestMarshallUnmarshall(t *testing.T) {
 					},
 				},
 			},
-			"hook1": []HookSpec{
+			ApplyExitHook: []HookSpec{
 				{
 					Workdir: "/wherever0",
 					Command: "whatever0",
@@ -116,11 +111,214 @@ func TestMarshallUnmarshall(t *testing.T) {
 	require.Equal(t, spec, s)
 }
 
+func TestUnmarshalSpec(t *testing.T) {
+	testCases := []struct {
+		Description     string
+		Yaml            string
+		expectedError   string
+		expectedFailure bool
+	}{
+		{
+			"Valid",
+			`
+version: v1
+hooks:
+  apply-start:
+  - command: /bin/true
+  pre-apply-mode:
+  - type: systemd-stop
+    services: ["kubelet.service"]
+    when: mode-change
+  - type: wait-no-gpu-processes
+    timeout: 30s
+  apply-exit:
+  - type: systemd-start
+    no-block: true
+  - type: http
+    url: http://localhost
+    retries: 2
+    on-failure: ignore
+`,
+			"",
+			false,
+		},
+		{
+			"Empty",
+			``,
+			"",
+			false,
+		},
+		{
+			"Missing Version",
+			`
+hooks:
+  apply-start:
+  - command: /bin/true
+`,
+			"missing 'version' field",
+			true,
+		},
+		{
+			"Unknown Version",
+			`
+version: v2
+`,
+			"unknown version: v2",
+			true,
+		},
+		{
+			"Unknown Top Level Field",
+			`
+version: v1
+hook:
+  apply-start:
+  - command: /bin/true
+`,
+			"unexpected field: hook (did you mean 'hooks'?)",
+			true,
+		},
+		{
+			"Several Unknown Top Level Fields",
+			`
+version: v1
+hook:
+  apply-start:
+  - command: /bin/true
+extra: true
+`,
+			"unexpected field: extra\nunexpected field: hook (did you mean 'hooks'?)",
+			true,
+		},
+		{
+			"Misspelled Hook Name",
+			`
+version: v1
+hooks:
+  pre-apply-confg:
+  - command: /bin/true
+`,
+			"unknown hook 'pre-apply-confg' (did you mean 'pre-apply-config'?)",
+			true,
+		},
+		{
+			"Unknown Hook Name",
+			`
+version: v1
+hooks:
+  whenever:
+  - command: /bin/true
+`,
+			"unknown hook 'whenever'",
+			true,
+		},
+		{
+			"Missing Command",
+			`
+version: v1
+hooks:
+  apply-start:
+  - workdir: /tmp
+`,
+			"apply-start[0]: missing required field 'command'",
+			true,
+		},
+		{
+			"Misspelled Field",
+			`
+version: v1
+hooks:
+  apply-exit:
+  - command: /bin/true
+    arg: ["-x"]
+`,
+			"apply-exit[0]: unexpected field: arg (did you mean 'args'?)",
+			true,
+		},
+		{
+			"Field Invalid For Type",
+			`
+version: v1
+hooks:
+  apply-exit:
+  - type: systemd-start
+    command: /bin/true
+`,
+			"field 'command' is not valid for hook of type 'systemd-start'",
+			true,
+		},
+		{
+			"Misspelled Type",
+			`
+version: v1
+hooks:
+  apply-exit:
+  - type: systemd-strat
+`,
+			"unknown hook type 'systemd-strat' (did you mean 'systemd-start'?)",
+			true,
+		},
+		{
+			"Invalid Timeout",
+			`
+version: v1
+hooks:
+  pre-apply-mode:
+  - type: wait-no-gpu-processes
+    timeout: soon
+`,
+			"invalid timeout 'soon'",
+			true,
+		},
+		{
+			"Invalid When",
+			`
+version: v1
+hooks:
+  apply-start:
+  - type: systemd-stop
+    services: ["kubelet.service"]
+    when: mode-chnage or config-change
+`,
+			"apply-start[0]: invalid when 'mode-chnage or config-change': unknown condition 'mode-chnage' (did you mean 'mode-change'?)",
+			true,
+		},
+		{
+			"Multiple Errors",
+			`
+version: v1
+hooks:
+  apply-start:
+  - workdir: /tmp
+  apply-exit:
+  - type: http
+`,
+			"apply-exit[0]: missing required field 'url' for hook of type 'http'\napply-start[0]: missing required field 'command'",
+			true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			s := Spec{}
+			err := yaml.Unmarshal([]byte(tc.Yaml), &s)
+			if !tc.expectedFailure {
+				require.Nil(t, err, "Unexpected failure yaml.Unmarshal")
+			} else {
+				require.NotNil(t, err, "Unexpected success yaml.Unmarshal")
+				require.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
+
 func TestRunHooks(t *testing.T) {
 	testCases := []struct {
 		Description     string
 		Hook            HookSpec
-		expectedOutput  string
+		Output          bool
+		expectedLines   []string
+		expectedStreams []string
+		expectedLevel   string
+		expectedError   string
 		expectedFailure bool
 	}{
 		{
@@ -129,29 +327,260 @@ func TestRunHooks(t *testing.T) {
 				Command: "/bin/sh",
 				Args:    []string{"-c", "echo Hello"},
 			},
-			"Hello\n",
+			true,
+			[]string{"Hello"},
+			[]string{"stdout"},
+			"info",
+			"",
+			false,
+		},
+		{
+			"Echo Hello Without Output",
+			HookSpec{
+				Command: "/bin/sh",
+				Args:    []string{"-c", "echo Hello"},
+			},
+			false,
+			[]string{"Hello"},
+			[]string{"stdout"},
+			"debug",
+			"",
 			false,
 		},
+		{
+			"Stderr And Partial Line",
+			HookSpec{
+				Command: "/bin/sh",
+				Args:    []string{"-c", "echo Hello >&2; printf World"},
+			},
+			true,
+			[]string{"Hello", "World"},
+			[]string{"stderr", "stdout"},
+			"info",
+			"",
+			false,
+		},
+		{
+			"Failure Includes Output",
+			HookSpec{
+				Command: "/bin/sh",
+				Args:    []string{"-c", "echo Stopping >&2; echo Failed to stop >&2; exit 3"},
+			},
+			false,
+			[]string{"Stopping", "Failed to stop"},
+			[]string{"stderr", "stderr"},
+			"debug",
+			"exit status 3 (last 2 line(s) of output):\nStopping\nFailed to stop",
+			true,
+		},
 		{
 			"Nonexistent Command",
 			HookSpec{
 				Command: "/doesnotexist",
 			},
+			true,
+			nil,
+			nil,
+			"",
 			"",
 			true,
 		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.Description, func(t *testing.T) {
-			output, err := captureOutput(func() error {
-				return tc.Hook.Run(EnvsMap{}, true)
+			entries, err := captureLogs(logrus.DebugLevel, func() error {
+				return HooksMap{ApplyExitHook: {{Command: "/bin/true"}, tc.Hook}}.Run(ApplyExitHook, EnvsMap{}, tc.Output)
 			})
 			if !tc.expectedFailure {
 				require.Nil(t, err, "Unexpected failure Hook.Run")
-				require.Equal(t, tc.expectedOutput, output)
 			} else {
 				require.NotNil(t, err, "Unexpected success Hook.Run")
+				require.Contains(t, err.Error(), tc.expectedError)
+			}
+
+			// Lines from different streams may be interleaved in any order.
+			var expected, lines []string
+			for i := range tc.expectedLines {
+				expected = append(expected, tc.expectedStreams[i]+": "+tc.expectedLines[i])
+			}
+			for _, entry := range entries {
+				lines = append(lines, fmt.Sprintf("%v: %v", entry["stream"], entry["msg"]))
+				require.Equal(t, tc.expectedLevel, entry["level"])
+				require.Equal(t, ApplyExitHook, entry["hook"])
+				require.Equal(t, float64(1), entry["index"])
+				require.Equal(t, tc.Hook.Command, entry["command"])
+			}
+			require.ElementsMatch(t, expected, lines)
+		})
+	}
+}
+
+func TestRunHookOutputTail(t *testing.T) {
+	hook := HookSpec{
+		Command: "/bin/sh",
+		Args:    []string{"-c", fmt.Sprintf("seq 1 %d; exit 1", MaxErrorOutputLines+5)},
+	}
+
+	entries, err := captureLogs(logrus.InfoLevel, func() error {
+		return hook.Run(EnvsMap{}, false)
+	})
+	require.NotNil(t, err, "Unexpected success Hook.Run")
+	require.Len(t, entries, 0)
+
+	var expected []string
+	for i := 6; i <= MaxErrorOutputLines+5; i++ {
+		expected = append(expected, fmt.Sprintf("%d", i))
+	}
+	require.True(t, strings.HasSuffix(err.Error(), ":\n"+strings.Join(expected, "\n")), err.Error())
+}
+
+type testActions struct {
+	calls        []string
+	hookContext  *HookContext
+	applyContext *ApplyContext
+}
+
+func (a *testActions) StopSystemdServices(services []string) error {
+	a.calls = append(a.calls, fmt.Sprintf("stop %v", services))
+	return nil
+}
+
+func (a *testActions) StartSystemdServices(services []string, noBlock bool) error {
+	a.calls = append(a.calls, fmt.Sprintf("start %v %v", services, noBlock))
+	return nil
+}
+
+func (a *testActions) WaitForNoGPUProcesses(timeout time.Duration) error {
+	a.calls = append(a.calls, fmt.Sprintf("wait %v", timeout))
+	return nil
+}
+
+func (a *testActions) GetHookContext() (*HookContext, error) {
+	if a.hookContext == nil {
+		return &HookContext{}, nil
+	}
+	return a.hookContext, nil
+}
+
+func (a *testActions) GetApplyContext() (*ApplyContext, error) {
+	if a.applyContext == nil {
+		return nil, fmt.Errorf("no apply context")
+	}
+	return a.applyContext, nil
+}
+
+func TestRunBuiltinHooks(t *testing.T) {
+	testCases := []struct {
+		Description     string
+		Hook            HookSpec
+		NilActions      bool
+		expectedCalls   []string
+		expectedFailure bool
+	}{
+		{
+			"Systemd Stop",
+			HookSpec{
+				Type:     SystemdStopHookType,
+				Services: []string{"a.service", "b.service"},
+			},
+			false,
+			[]string{"stop [a.service b.service]"},
+			false,
+		},
+		{
+			"Systemd Start No Block",
+			HookSpec{
+				Type:    SystemdStartHookType,
+				NoBlock: true,
+			},
+			false,
+			[]string{"start [] true"},
+			false,
+		},
+		{
+			"Wait Default Timeout",
+			HookSpec{
+				Type: WaitNoGPUProcessesHookType,
+			},
+			false,
+			[]string{fmt.Sprintf("wait %v", DefaultWaitNoGPUProcessesTimeout)},
+			false,
+		},
+		{
+			"Wait Explicit Timeout",
+			HookSpec{
+				Type:    WaitNoGPUProcessesHookType,
+				Timeout: "5m",
+			},
+			false,
+			[]string{"wait 5m0s"},
+			false,
+		},
+		{
+			"Wait Invalid Timeout",
+			HookSpec{
+				Type:    WaitNoGPUProcessesHookType,
+				Timeout: "forever",
+			},
+			false,
+			nil,
+			true,
+		},
+		{
+			"Unknown Type",
+			HookSpec{
+				Type: "unknown",
+			},
+			false,
+			nil,
+			true,
+		},
+		{
+			"Unknown Type Ignored",
+			HookSpec{
+				Type:      "unknown",
+				OnFailure: FailurePolicyIgnore,
+			},
+			false,
+			nil,
+			false,
+		},
+		{
+			"Unknown Failure Policy",
+			HookSpec{
+				Type:      "unknown",
+				OnFailure: "retry",
+			},
+			false,
+			nil,
+			true,
+		},
+		{
+			"Builtin Without Actions",
+			HookSpec{
+				Type:     SystemdStopHookType,
+				Services: []string{"a.service"},
+			},
+			true,
+			nil,
+			true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			actions := &testActions{}
+			var err error
+			if tc.NilActions {
+				err = tc.Hook.RunWithActions("hook", EnvsMap{}, false, nil)
+			} else {
+				err = tc.Hook.RunWithActions("hook", EnvsMap{}, false, actions)
+			}
+			if !tc.expectedFailure {
+				require.Nil(t, err, "Unexpected failure Hook.RunWithActions")
+			} else {
+				require.NotNil(t, err, "Unexpected success Hook.RunWithActions")
 			}
+			require.Equal(t, tc.expectedCalls, actions.calls)
 		})
 	}
 }
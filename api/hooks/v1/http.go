@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// DefaultHTTPTimeout is the timeout used for each request of an 'http' hook when none is specified.
+	DefaultHTTPTimeout = 10 * time.Second
+	// DefaultHTTPRetryInterval is the time waited between retries of an 'http' hook when none is specified.
+	DefaultHTTPRetryInterval = 1 * time.Second
+
+	maxHTTPResponseSnippet = 512
+)
+
+// HTTPHookData is the data made available to the 'body' template of an 'http' hook.
+// When no 'body' is specified, this data is sent as the JSON body of the request.
+type HTTPHookData struct {
+	Hook string `json:"hook"`
+	HookContext
+}
+
+func (h *HookSpec) runHTTP(name string, actions Actions) error {
+	if h.URL == "" {
+		return fmt.Errorf("missing url for hook of type '%v'", HTTPHookType)
+	}
+
+	timeout, err := h.GetTimeout(DefaultHTTPTimeout)
+	if err != nil {
+		return err
+	}
+
+	retryInterval := DefaultHTTPRetryInterval
+	if h.RetryInterval != "" {
+		retryInterval, err = time.ParseDuration(h.RetryInterval)
+		if err != nil {
+			return fmt.Errorf("invalid retry-interval '%v': %v", h.RetryInterval, err)
+		}
+	}
+
+	data, err := getHTTPHookData(name, actions)
+	if err != nil {
+		return fmt.Errorf("error getting hook data: %v", err)
+	}
+
+	body, err := h.renderHTTPBody(data)
+	if err != nil {
+		return fmt.Errorf("error rendering body: %v", err)
+	}
+
+	client, err := h.newHTTPClient(timeout)
+	if err != nil {
+		return fmt.Errorf("error creating HTTP client: %v", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := h.doHTTPRequest(client, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= h.Retries {
+			return fmt.Errorf("error sending request to %v after %d attempt(s): %w", h.URL, attempt+1, err)
+		}
+		time.Sleep(retryInterval)
+	}
+}
+
+// doHTTPRequest sends the request of an 'http' hook. On failure, it also
+// returns whether the request may succeed if retried: only network errors,
+// 5xx responses and 429 (Too Many Requests) are retried.
+func (h *HookSpec) doHTTPRequest(client *http.Client, body []byte) (bool, error) {
+	method := h.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(method), h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseSnippet))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("unexpected response status '%v': %v", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+
+	return false, nil
+}
+
+func (h *HookSpec) newHTTPClient(timeout time.Duration) (*http.Client, error) {
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	if h.CAFile == "" {
+		return client, nil
+	}
+
+	ca, err := os.ReadFile(h.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ca-file: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no valid certificates found in ca-file '%v'", h.CAFile)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	client.Transport = transport
+
+	return client, nil
+}
+
+func (h *HookSpec) renderHTTPBody(data *HTTPHookData) ([]byte, error) {
+	if h.Body == "" {
+		return json.Marshal(data)
+	}
+
+	funcs := template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+
+	tmpl, err := template.New("body").Funcs(funcs).Option("missingkey=error").Parse(h.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing template: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, data)
+	if err != nil {
+		return nil, fmt.Errorf("error executing template: %v", err)
+	}
+
+	if !json.Valid(body.Bytes()) {
+		return nil, fmt.Errorf("rendered body is not valid JSON: %v", body.String())
+	}
+
+	return body.Bytes(), nil
+}
+
+func getHTTPHookData(name string, actions Actions) (*HTTPHookData, error) {
+	data := &HTTPHookData{
+		Hook: name,
+	}
+
+	if actions != nil {
+		hookContext, err := actions.GetHookContext()
+		if err != nil {
+			return nil, err
+		}
+		data.HookContext = *hookContext
+	}
+
+	return data, nil
+}
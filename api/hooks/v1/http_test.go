@@ -0,0 +1,264 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+type testRequest struct {
+	Method  string
+	Headers http.Header
+	Body    string
+}
+
+func newTestServer(t *testing.T, failures int, status int) (*httptest.Server, *[]testRequest) {
+	var requests []testRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		requests = append(requests, testRequest{r.Method, r.Header, string(body)})
+		if len(requests) <= failures {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("try again"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestRunHTTPHook(t *testing.T) {
+	hookContext := &HookContext{
+		Hostname:       "node0",
+		SelectedConfig: "all-1g.10gb",
+		GPUs: []GPUSummary{
+			{
+				Index:      0,
+				DeviceID:   "0x233010DE",
+				MigCapable: true,
+				MigEnabled: true,
+				MigDevices: types.MigConfig{"1g.10gb": 7},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Description      string
+		Hook             HookSpec
+		Failures         int
+		Status           int
+		expectedRequests int
+		expectedMethod   string
+		expectedBody     string
+		expectedHeaders  map[string]string
+		expectedFailure  bool
+	}{
+		{
+			"Default Body",
+			HookSpec{
+				Type: HTTPHookType,
+			},
+			0,
+			0,
+			1,
+			http.MethodPost,
+			`{"hook":"apply-start","hostname":"node0","selected-config":"all-1g.10gb","gpus":[{"index":0,"device-id":"0x233010DE","mig-capable":true,"mig-enabled":true,"mig-devices":{"1g.10gb":7}}]}`,
+			map[string]string{"Content-Type": "application/json"},
+			false,
+		},
+		{
+			"Templated Body With Method And Headers",
+			HookSpec{
+				Type:    HTTPHookType,
+				Method:  "put",
+				Headers: map[string]string{"Authorization": "Bearer token"},
+				Body:    `{"node": "{{ .Hostname }}", "event": "{{ .Hook }}", "gpus": {{ json .GPUs }}}`,
+			},
+			0,
+			0,
+			1,
+			http.MethodPut,
+			`{"node": "node0", "event": "apply-start", "gpus": [{"index":0,"device-id":"0x233010DE","mig-capable":true,"mig-enabled":true,"mig-devices":{"1g.10gb":7}}]}`,
+			map[string]string{"Authorization": "Bearer token"},
+			false,
+		},
+		{
+			"Invalid JSON Body",
+			HookSpec{
+				Type: HTTPHookType,
+				Body: `{"node": {{ .Hostname }}}`,
+			},
+			0,
+			0,
+			0,
+			"",
+			"",
+			nil,
+			true,
+		},
+		{
+			"Non-2xx Fails",
+			HookSpec{
+				Type: HTTPHookType,
+			},
+			1,
+			http.StatusServiceUnavailable,
+			1,
+			http.MethodPost,
+			"",
+			nil,
+			true,
+		},
+		{
+			"Non-2xx Ignored",
+			HookSpec{
+				Type:      HTTPHookType,
+				OnFailure: FailurePolicyIgnore,
+			},
+			1,
+			http.StatusServiceUnavailable,
+			1,
+			http.MethodPost,
+			"",
+			nil,
+			false,
+		},
+		{
+			"Retries Succeed",
+			HookSpec{
+				Type:          HTTPHookType,
+				Retries:       2,
+				RetryInterval: "1ms",
+			},
+			2,
+			http.StatusInternalServerError,
+			3,
+			http.MethodPost,
+			"",
+			nil,
+			false,
+		},
+		{
+			"Retries Too Many Requests",
+			HookSpec{
+				Type:          HTTPHookType,
+				Retries:       2,
+				RetryInterval: "1ms",
+			},
+			1,
+			http.StatusTooManyRequests,
+			2,
+			http.MethodPost,
+			"",
+			nil,
+			false,
+		},
+		{
+			"Client Errors Not Retried",
+			HookSpec{
+				Type:          HTTPHookType,
+				Retries:       2,
+				RetryInterval: "1ms",
+			},
+			1,
+			http.StatusNotFound,
+			1,
+			http.MethodPost,
+			"",
+			nil,
+			true,
+		},
+		{
+			"Retries Exhausted",
+			HookSpec{
+				Type:          HTTPHookType,
+				Retries:       1,
+				RetryInterval: "1ms",
+			},
+			2,
+			http.StatusInternalServerError,
+			2,
+			http.MethodPost,
+			"",
+			nil,
+			true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			server, requests := newTestServer(t, tc.Failures, tc.Status)
+
+			tc.Hook.URL = server.URL
+			err := tc.Hook.RunWithActions("apply-start", EnvsMap{}, false, &testActions{hookContext: hookContext})
+			if !tc.expectedFailure {
+				require.Nil(t, err, "Unexpected failure Hook.RunWithActions")
+			} else {
+				require.NotNil(t, err, "Unexpected success Hook.RunWithActions")
+			}
+
+			require.Len(t, *requests, tc.expectedRequests)
+			if tc.expectedRequests == 0 {
+				return
+			}
+
+			last := (*requests)[len(*requests)-1]
+			require.Equal(t, tc.expectedMethod, last.Method)
+			if tc.expectedBody != "" {
+				require.Equal(t, tc.expectedBody, last.Body)
+			}
+			require.True(t, json.Valid([]byte(last.Body)))
+			for k, v := range tc.expectedHeaders {
+				require.Equal(t, v, last.Headers.Get(k))
+			}
+		})
+	}
+}
+
+func TestRunHTTPHookTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.Nil(t, os.WriteFile(caFile, ca, 0600))
+
+	hook := HookSpec{
+		Type: HTTPHookType,
+		URL:  server.URL,
+	}
+	err := hook.RunWithActions("apply-exit", EnvsMap{}, false, nil)
+	require.NotNil(t, err, "Unexpected success without ca-file")
+
+	hook.CAFile = caFile
+	err = hook.RunWithActions("apply-exit", EnvsMap{}, false, nil)
+	require.Nil(t, err, "Unexpected failure with ca-file")
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// outputTail keeps the last 'max' lines written to it by any number of lineLoggers.
+type outputTail struct {
+	sync.Mutex
+	max   int
+	lines []string
+}
+
+// lineLogger is an io.Writer that logs each complete line written to it and records it in an outputTail.
+type lineLogger struct {
+	entry *logrus.Entry
+	level logrus.Level
+	tail  *outputTail
+	buf   bytes.Buffer
+}
+
+func newOutputTail(max int) *outputTail {
+	return &outputTail{max: max}
+}
+
+func (t *outputTail) Add(line string) {
+	t.Lock()
+	defer t.Unlock()
+	t.lines = append(t.lines, line)
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+}
+
+func (t *outputTail) Lines() []string {
+	t.Lock()
+	defer t.Unlock()
+	return append([]string(nil), t.lines...)
+}
+
+func newLineLogger(entry *logrus.Entry, level logrus.Level, tail *outputTail) *lineLogger {
+	return &lineLogger{
+		entry: entry,
+		level: level,
+		tail:  tail,
+	}
+}
+
+// Write buffers 'p' and logs any complete lines it contains.
+func (l *lineLogger) Write(p []byte) (int, error) {
+	l.buf.Write(p)
+	for {
+		i := bytes.IndexByte(l.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		line := string(l.buf.Next(i + 1))
+		l.log(line[:len(line)-1])
+	}
+	return len(p), nil
+}
+
+// Flush logs any trailing partial line that has not been terminated by a newline.
+func (l *lineLogger) Flush() {
+	if l.buf.Len() == 0 {
+		return
+	}
+	l.log(l.buf.String())
+	l.buf.Reset()
+}
+
+func (l *lineLogger) log(line string) {
+	line = strings.TrimRight(line, "\r")
+	l.tail.Add(line)
+	l.entry.Log(l.level, line)
+}
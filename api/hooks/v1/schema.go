@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/NVIDIA/mig-parted/internal/jsonschema"
+)
+
+// durationPattern matches a duration as parsed by 'time.ParseDuration' (e.g. "1m30s").
+const durationPattern = `^[-+]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// Schema returns the JSON Schema of a hooks file, generated from the 'Spec'
+// type. Each hook type only allows the fields that apply to it, and requires
+// those it cannot run without. The 'when' clause of a hook is only checked to
+// be a string.
+func Schema() *jsonschema.Schema {
+	var types []interface{}
+	for _, t := range hookTypes() {
+		types = append(types, t)
+	}
+	var knownHooks []interface{}
+	for _, h := range KnownHooks {
+		knownHooks = append(knownHooks, h)
+	}
+
+	r := jsonschema.Reflector{
+		Fields: map[string]*jsonschema.Schema{
+			"Spec.version": {Type: jsonschema.Types{"string"}, Const: Version},
+			"Spec.hooks": {
+				Type:                 jsonschema.Types{"object"},
+				PropertyNames:        &jsonschema.Schema{Enum: knownHooks},
+				AdditionalProperties: &jsonschema.Schema{Type: jsonschema.Types{"array"}, Items: jsonschema.Ref("HookSpec")},
+			},
+			"HookSpec.type":           {Type: jsonschema.Types{"string"}, Enum: types},
+			"HookSpec.on-failure":     {Type: jsonschema.Types{"string"}, Enum: []interface{}{FailurePolicyFail, FailurePolicyIgnore}},
+			"HookSpec.retries":        {Type: jsonschema.Types{"integer"}, Minimum: jsonschema.Number(0)},
+			"HookSpec.retry-interval": {Type: jsonschema.Types{"string"}, Pattern: durationPattern},
+			"HookSpec.timeout":        {Type: jsonschema.Types{"string"}, Pattern: durationPattern},
+		},
+	}
+
+	r.Schema(reflect.TypeFor[HookSpec]())
+	schema := r.Reflect(reflect.TypeFor[Spec]())
+	schema.Title = "nvidia-mig-parted hooks file"
+
+	// A hook without a type is a 'command' hook.
+	hookSpec := r.Definition("HookSpec")
+	for _, t := range hookTypes() {
+		condition := &jsonschema.Schema{
+			Properties: map[string]*jsonschema.Schema{"type": {Const: t}},
+			Required:   []string{"type"},
+		}
+		if t == CommandHookType {
+			condition = &jsonschema.Schema{
+				Properties: map[string]*jsonschema.Schema{"type": {Const: t}},
+			}
+		}
+
+		var fields []interface{}
+		for _, f := range append(slices.Clone(commonHookFields), hookTypeFields[t]...) {
+			fields = append(fields, f)
+		}
+		rules := &jsonschema.Schema{
+			PropertyNames: &jsonschema.Schema{Enum: fields},
+			Properties:    make(map[string]*jsonschema.Schema),
+		}
+		switch t {
+		case CommandHookType:
+			rules.Required = []string{"command"}
+			rules.Properties["command"] = &jsonschema.Schema{MinLength: jsonschema.Int(1)}
+		case SystemdStopHookType:
+			rules.Required = []string{"services"}
+			rules.Properties["services"] = &jsonschema.Schema{MinItems: jsonschema.Int(1)}
+		case HTTPHookType:
+			rules.Required = []string{"url"}
+			rules.Properties["url"] = &jsonschema.Schema{MinLength: jsonschema.Int(1)}
+		}
+
+		hookSpec.AllOf = append(hookSpec.AllOf, &jsonschema.Schema{If: condition, Then: rules})
+	}
+
+	return schema
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestSchema(t *testing.T) {
+	testCases := []struct {
+		description string
+		spec        string
+		valid       bool
+	}{
+		{
+			"Hooks of every type",
+			`
+version: v1
+hooks:
+  apply-start:
+  - command: /bin/sh
+    args: ["-c", "true"]
+    envs: {FOO: bar}
+    workdir: /tmp
+  - type: http
+    url: http://localhost:8080/drain
+    method: POST
+    headers: {Content-Type: application/json}
+    retries: 3
+    retry-interval: 500ms
+    timeout: 1m30s
+  pre-apply-mode:
+  - type: systemd-stop
+    services: [kubelet.service]
+    on-failure: ignore
+  - type: wait-no-gpu-processes
+    timeout: 2m
+    when: mode-change
+  apply-exit:
+  - type: systemd-start
+    services: [kubelet.service]
+    no-block: true
+`,
+			true,
+		},
+		{
+			"No hooks",
+			`
+version: v1
+`,
+			true,
+		},
+		{
+			"Missing version",
+			`
+hooks:
+  apply-start:
+  - command: "true"
+`,
+			false,
+		},
+		{
+			"Unknown version",
+			`
+version: v2
+`,
+			false,
+		},
+		{
+			"Unknown top-level field",
+			`
+version: v1
+hook: {}
+`,
+			false,
+		},
+		{
+			"Unknown hook",
+			`
+version: v1
+hooks:
+  apply-begin:
+  - command: "true"
+`,
+			false,
+		},
+		{
+			"Unknown hook type",
+			`
+version: v1
+hooks:
+  apply-start:
+  - type: script
+    command: "true"
+`,
+			false,
+		},
+		{
+			"Unknown failure policy",
+			`
+version: v1
+hooks:
+  apply-start:
+  - command: "true"
+    on-failure: retry
+`,
+			false,
+		},
+		{
+			"Unknown field",
+			`
+version: v1
+hooks:
+  apply-start:
+  - command: "true"
+    arguments: ["-c"]
+`,
+			false,
+		},
+		{
+			"Field of another hook type",
+			`
+version: v1
+hooks:
+  apply-start:
+  - command: "true"
+    services: [kubelet.service]
+`,
+			false,
+		},
+		{
+			"Missing command",
+			`
+version: v1
+hooks:
+  apply-start:
+  - args: ["-c", "true"]
+`,
+			false,
+		},
+		{
+			"Empty command",
+			`
+version: v1
+hooks:
+  apply-start:
+  - command: ""
+`,
+			false,
+		},
+		{
+			"Missing services",
+			`
+version: v1
+hooks:
+  pre-apply-mode:
+  - type: systemd-stop
+`,
+			false,
+		},
+		{
+			"Missing URL",
+			`
+version: v1
+hooks:
+  apply-start:
+  - type: http
+    method: POST
+`,
+			false,
+		},
+		{
+			"Negative retries",
+			`
+version: v1
+hooks:
+  apply-start:
+  - type: http
+    url: http://localhost:8080/drain
+    retries: -1
+`,
+			false,
+		},
+		{
+			"Invalid retry interval",
+			`
+version: v1
+hooks:
+  apply-start:
+  - type: http
+    url: http://localhost:8080/drain
+    retry-interval: soon
+`,
+			false,
+		},
+		{
+			"Invalid timeout",
+			`
+version: v1
+hooks:
+  pre-apply-mode:
+  - type: wait-no-gpu-processes
+    timeout: 60
+`,
+			false,
+		},
+	}
+
+	schema := Schema()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			data, err := yaml.YAMLToJSON([]byte(tc.spec))
+			require.Nil(t, err)
+
+			err = schema.Validate(data)
+			if tc.valid {
+				require.Nil(t, err, "schema")
+			} else {
+				require.NotNil(t, err, "schema")
+			}
+
+			var spec Spec
+			err = json.Unmarshal(data, &spec)
+			if tc.valid {
+				require.Nil(t, err, "UnmarshalJSON")
+			} else {
+				require.NotNil(t, err, "UnmarshalJSON")
+			}
+		})
+	}
+}
+
+func TestDurationPattern(t *testing.T) {
+	testCases := []string{
+		"0",
+		"1s",
+		"1.5h",
+		".5m",
+		"1h30m",
+		"-2ms",
+		"100us",
+		"3µs",
+		"",
+		"1",
+		"1d",
+		"s",
+		"1.s",
+		"1 s",
+	}
+
+	pattern := regexp.MustCompile(durationPattern)
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			_, err := time.ParseDuration(tc)
+			require.Equal(t, err == nil, pattern.MatchString(tc))
+		})
+	}
+}
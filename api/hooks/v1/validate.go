@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/mig-parted/internal/suggest"
+)
+
+// commonHookFields lists the fields valid for a HookSpec of any type.
+var commonHookFields = []string{"type", "when", "on-failure"}
+
+// hookTypeFields maps each supported hook type to the fields valid for a HookSpec of that type.
+var hookTypeFields = map[string][]string{
+	CommandHookType:            {"command", "args", "envs", "workdir"},
+	SystemdStopHookType:        {"services"},
+	SystemdStartHookType:       {"services", "no-block"},
+	WaitNoGPUProcessesHookType: {"timeout"},
+	HTTPHookType:               {"url", "method", "headers", "body", "retries", "retry-interval", "ca-file", "timeout"},
+}
+
+// UnmarshalJSON unmarshals raw bytes into a versioned 'Spec'.
+// All hooks are validated and any errors found are returned together.
+func (s *Spec) UnmarshalJSON(b []byte) error {
+	spec := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &spec)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := spec["version"]; !exists && len(spec) > 0 {
+		return fmt.Errorf("unable to parse with missing 'version' field")
+	}
+
+	result := Spec{}
+	if v, exists := spec["version"]; exists {
+		err := json.Unmarshal(v, &result.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	if result.Version != Version {
+		return fmt.Errorf("unknown version: %v", result.Version)
+	}
+
+	delete(spec, "version")
+	var errs []error
+	for _, k := range sortedKeys(spec) {
+		switch k {
+		case "hooks":
+			hooks, err := unmarshalHooksMap(spec[k])
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			result.Hooks = hooks
+		default:
+			errs = append(errs, fmt.Errorf("unexpected field: %v%v", k, suggest.DidYouMean(k, []string{"version", "hooks"})))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	*s = result
+	return nil
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'HookSpec', rejecting unknown fields and validating the result.
+func (h *HookSpec) UnmarshalJSON(b []byte) error {
+	fields := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &fields)
+	if err != nil {
+		return err
+	}
+
+	// Decode through an alias type to avoid recursing back into this function.
+	type hookSpec HookSpec
+	var result hookSpec
+	err = json.Unmarshal(b, &result)
+	if err != nil {
+		return err
+	}
+
+	hook := HookSpec(result)
+	err = hook.validateFields(sortedKeys(fields))
+	if err != nil {
+		return err
+	}
+
+	err = hook.Validate()
+	if err != nil {
+		return err
+	}
+
+	*h = hook
+	return nil
+}
+
+// Validate checks that a HookSpec has a known type and failure policy, and that all fields required by its type are set.
+func (h *HookSpec) Validate() error {
+	hookType := h.GetType()
+	if _, exists := hookTypeFields[hookType]; !exists {
+		return fmt.Errorf("unknown hook type '%v'%v", hookType, suggest.DidYouMean(hookType, hookTypes()))
+	}
+
+	onFailure := h.GetOnFailure()
+	if onFailure != FailurePolicyFail && onFailure != FailurePolicyIgnore {
+		return fmt.Errorf("unknown failure policy '%v'%v", onFailure, suggest.DidYouMean(onFailure, []string{FailurePolicyFail, FailurePolicyIgnore}))
+	}
+
+	switch hookType {
+	case CommandHookType:
+		if h.Command == "" {
+			return fmt.Errorf("missing required field 'command' for hook of type '%v'", hookType)
+		}
+	case SystemdStopHookType:
+		if len(h.Services) == 0 {
+			return fmt.Errorf("missing required field 'services' for hook of type '%v'", hookType)
+		}
+	case HTTPHookType:
+		if h.URL == "" {
+			return fmt.Errorf("missing required field 'url' for hook of type '%v'", hookType)
+		}
+		if h.Retries < 0 {
+			return fmt.Errorf("invalid retries '%v': must not be negative", h.Retries)
+		}
+		if h.RetryInterval != "" {
+			if _, err := time.ParseDuration(h.RetryInterval); err != nil {
+				return fmt.Errorf("invalid retry-interval '%v': %v", h.RetryInterval, err)
+			}
+		}
+	}
+
+	if _, err := h.GetTimeout(0); err != nil {
+		return err
+	}
+
+	if h.When != "" {
+		if _, err := ParseCondition(h.When); err != nil {
+			return fmt.Errorf("invalid when '%v': %v", h.When, err)
+		}
+	}
+
+	return nil
+}
+
+// validateFields checks that each of the fields set on a HookSpec is valid for its type.
+func (h *HookSpec) validateFields(fields []string) error {
+	hookType := h.GetType()
+	allowed := append(slices.Clone(commonHookFields), hookTypeFields[hookType]...)
+
+	var known []string
+	known = append(known, commonHookFields...)
+	for _, t := range hookTypes() {
+		known = append(known, hookTypeFields[t]...)
+	}
+
+	for _, f := range fields {
+		if !slices.Contains(known, f) {
+			return fmt.Errorf("unexpected field: %v%v", f, suggest.DidYouMean(f, known))
+		}
+		if _, exists := hookTypeFields[hookType]; exists && !slices.Contains(allowed, f) {
+			return fmt.Errorf("field '%v' is not valid for hook of type '%v'", f, hookType)
+		}
+	}
+
+	return nil
+}
+
+// unmarshalHooksMap unmarshals and validates each named list of hooks.
+// Errors are annotated with the name and index of the offending hook.
+func unmarshalHooksMap(b []byte) (HooksMap, error) {
+	raw := make(map[string][]json.RawMessage)
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	hooks := make(HooksMap)
+	for _, name := range sortedKeys(raw) {
+		if !slices.Contains(KnownHooks, name) {
+			errs = append(errs, fmt.Errorf("unknown hook '%v'%v", name, suggest.DidYouMean(name, KnownHooks)))
+			continue
+		}
+		for i, r := range raw[name] {
+			var hook HookSpec
+			err := json.Unmarshal(r, &hook)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%v[%d]: %v", name, i, err))
+				continue
+			}
+			hooks[name] = append(hooks[name], hook)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return hooks, nil
+}
+
+func hookTypes() []string {
+	return sortedKeys(hookTypeFields)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,406 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/NVIDIA/mig-parted/internal/suggest"
+)
+
+// Constants representing the set of conditions that can be referenced by the 'when' clause of a hook.
+const (
+	ModeChangeCondition     = "mode-change"
+	ConfigChangeCondition   = "config-change"
+	GPUCountChangeCondition = "gpu-count-changed"
+	SelectedConfigCondition = "selected-config"
+	DeviceIDCondition       = "device-id"
+)
+
+// ApplyContext holds the information about an in-progress apply that the 'when' clause of a hook is evaluated against.
+type ApplyContext struct {
+	// SelectedConfig is the name of the MIG config being applied (if any).
+	SelectedConfig string
+	// DeviceIDs holds the device ID of each GPU on the node.
+	DeviceIDs []string
+	// ModeChange is set if the apply changes the MIG mode of at least one GPU.
+	ModeChange bool
+	// ConfigChange is set if the apply changes the MIG devices of at least one GPU.
+	ConfigChange bool
+	// GPUCountChange is set if the number of GPUs visible to workloads (full GPUs plus MIG devices)
+	// has changed since the apply started.
+	GPUCountChange bool
+}
+
+// Condition is a parsed 'when' clause.
+type Condition interface {
+	Evaluate(c *ApplyContext) bool
+	String() string
+}
+
+// ParseCondition parses the 'when' clause of a hook.
+//
+// A clause is a boolean expression made up of the following terms:
+//
+//	mode-change
+//	config-change
+//	gpu-count-changed
+//	selected-config in [<name>, ...]
+//	device-id matches <regexp>
+//
+// Terms can be combined with 'and', 'or', 'not' and parentheses. Values containing
+// spaces, commas, brackets or parentheses must be quoted with single or double quotes.
+// A 'device-id matches' term holds if the device ID of any GPU on the node matches the
+// (case-insensitive) regular expression in full.
+func ParseCondition(s string) (Condition, error) {
+	tokens, err := tokenizeCondition(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+
+	p := &conditionParser{tokens: tokens}
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected '%v' after condition '%v'", p.peek(), c)
+	}
+
+	return c, nil
+}
+
+type andCondition struct{ terms []Condition }
+type orCondition struct{ terms []Condition }
+type notCondition struct{ term Condition }
+type flagCondition struct{ name string }
+type selectedConfigCondition struct{ names []string }
+type deviceIDCondition struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func (c *andCondition) Evaluate(ctx *ApplyContext) bool {
+	for _, t := range c.terms {
+		if !t.Evaluate(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *andCondition) String() string {
+	return joinConditions(c.terms, " and ")
+}
+
+func (c *orCondition) Evaluate(ctx *ApplyContext) bool {
+	for _, t := range c.terms {
+		if t.Evaluate(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *orCondition) String() string {
+	return joinConditions(c.terms, " or ")
+}
+
+func (c *notCondition) Evaluate(ctx *ApplyContext) bool {
+	return !c.term.Evaluate(ctx)
+}
+
+func (c *notCondition) String() string {
+	return "not " + joinConditions([]Condition{c.term}, "")
+}
+
+func (c *flagCondition) Evaluate(ctx *ApplyContext) bool {
+	switch c.name {
+	case ModeChangeCondition:
+		return ctx.ModeChange
+	case ConfigChangeCondition:
+		return ctx.ConfigChange
+	case GPUCountChangeCondition:
+		return ctx.GPUCountChange
+	}
+	return false
+}
+
+func (c *flagCondition) String() string {
+	return c.name
+}
+
+func (c *selectedConfigCondition) Evaluate(ctx *ApplyContext) bool {
+	return slices.Contains(c.names, ctx.SelectedConfig)
+}
+
+func (c *selectedConfigCondition) String() string {
+	var names []string
+	for _, n := range c.names {
+		names = append(names, quoteConditionValue(n))
+	}
+	return fmt.Sprintf("%v in [%v]", SelectedConfigCondition, strings.Join(names, ", "))
+}
+
+func (c *deviceIDCondition) Evaluate(ctx *ApplyContext) bool {
+	return slices.ContainsFunc(ctx.DeviceIDs, c.re.MatchString)
+}
+
+func (c *deviceIDCondition) String() string {
+	return fmt.Sprintf("%v matches %v", DeviceIDCondition, quoteConditionValue(c.pattern))
+}
+
+// quoteConditionValue quotes a value if it would otherwise not be parsed back as a single value.
+func quoteConditionValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n\r()[],'\"") {
+		return v
+	}
+	if strings.Contains(v, "'") {
+		return `"` + v + `"`
+	}
+	return "'" + v + "'"
+}
+
+func joinConditions(terms []Condition, sep string) string {
+	var s []string
+	for _, t := range terms {
+		switch t.(type) {
+		case *andCondition, *orCondition:
+			s = append(s, fmt.Sprintf("(%v)", t))
+		default:
+			s = append(s, t.String())
+		}
+	}
+	return strings.Join(s, sep)
+}
+
+// conditionParser is a recursive descent parser for the grammar:
+//
+//	or   := and ('or' and)*
+//	and  := not ('and' not)*
+//	not  := 'not' not | term
+//	term := '(' or ')' | flag | 'selected-config' 'in' list | 'device-id' 'matches' value
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+type conditionToken struct {
+	value  string
+	quoted bool
+}
+
+func (p *conditionParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *conditionParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos].value
+}
+
+// accept consumes the next token if it is the (unquoted) keyword provided.
+func (p *conditionParser) accept(keyword string) bool {
+	if p.done() || p.tokens[p.pos].quoted || p.tokens[p.pos].value != keyword {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *conditionParser) expect(keyword string) error {
+	if p.accept(keyword) {
+		return nil
+	}
+	if p.done() {
+		return fmt.Errorf("expected '%v' but reached end of condition", keyword)
+	}
+	return fmt.Errorf("expected '%v' but found '%v'", keyword, p.peek())
+}
+
+func (p *conditionParser) value() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("expected a value but reached end of condition")
+	}
+	t := p.tokens[p.pos]
+	if !t.quoted && strings.ContainsAny(t.value, "()[],") {
+		return "", fmt.Errorf("expected a value but found '%v'", t.value)
+	}
+	p.pos++
+	return t.value, nil
+}
+
+func (p *conditionParser) parseOr() (Condition, error) {
+	var terms []Condition
+	for {
+		t, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+		if !p.accept("or") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &orCondition{terms}, nil
+}
+
+func (p *conditionParser) parseAnd() (Condition, error) {
+	var terms []Condition
+	for {
+		t, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t)
+		if !p.accept("and") {
+			break
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &andCondition{terms}, nil
+}
+
+func (p *conditionParser) parseNot() (Condition, error) {
+	if p.accept("not") {
+		t, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notCondition{t}, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *conditionParser) parseTerm() (Condition, error) {
+	if p.accept("(") {
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
+	known := []string{ModeChangeCondition, ConfigChangeCondition, GPUCountChangeCondition, SelectedConfigCondition, DeviceIDCondition}
+	switch {
+	case p.accept(ModeChangeCondition):
+		return &flagCondition{ModeChangeCondition}, nil
+	case p.accept(ConfigChangeCondition):
+		return &flagCondition{ConfigChangeCondition}, nil
+	case p.accept(GPUCountChangeCondition):
+		return &flagCondition{GPUCountChangeCondition}, nil
+	case p.accept(SelectedConfigCondition):
+		return p.parseSelectedConfig()
+	case p.accept(DeviceIDCondition):
+		return p.parseDeviceID()
+	case p.done():
+		return nil, fmt.Errorf("expected a condition but reached end of condition")
+	}
+
+	return nil, fmt.Errorf("unknown condition '%v'%v", p.peek(), suggest.DidYouMean(p.peek(), known))
+}
+
+func (p *conditionParser) parseSelectedConfig() (Condition, error) {
+	if err := p.expect("in"); err != nil {
+		return nil, fmt.Errorf("%v: %v", SelectedConfigCondition, err)
+	}
+	if err := p.expect("["); err != nil {
+		return nil, fmt.Errorf("%v: %v", SelectedConfigCondition, err)
+	}
+
+	var names []string
+	for !p.accept("]") {
+		if len(names) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, fmt.Errorf("%v: %v", SelectedConfigCondition, err)
+			}
+		}
+		name, err := p.value()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", SelectedConfigCondition, err)
+		}
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%v: empty list of configs", SelectedConfigCondition)
+	}
+
+	return &selectedConfigCondition{names}, nil
+}
+
+func (p *conditionParser) parseDeviceID() (Condition, error) {
+	if err := p.expect("matches"); err != nil {
+		return nil, fmt.Errorf("%v: %v", DeviceIDCondition, err)
+	}
+
+	pattern, err := p.value()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", DeviceIDCondition, err)
+	}
+
+	re, err := regexp.Compile("(?i)^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%v: invalid regular expression '%v': %v", DeviceIDCondition, pattern, err)
+	}
+
+	return &deviceIDCondition{pattern, re}, nil
+}
+
+// tokenizeCondition splits a 'when' clause into words, quoted values and the punctuation '(', ')', '[', ']' and ','.
+func tokenizeCondition(s string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.IndexByte("()[],", c) >= 0:
+			tokens = append(tokens, conditionToken{value: string(c)})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quoted value starting at offset %d", i)
+			}
+			tokens = append(tokens, conditionToken{value: s[i+1 : i+1+end], quoted: true})
+			i += end + 2
+		default:
+			start := i
+			for i < len(s) && strings.IndexByte(" \t\n\r()[],'\"", s[i]) < 0 {
+				i++
+			}
+			tokens = append(tokens, conditionToken{value: s[start:i]})
+		}
+	}
+	return tokens, nil
+}
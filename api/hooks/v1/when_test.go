@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	applyContext := &ApplyContext{
+		SelectedConfig: "all-1g.10gb",
+		DeviceIDs:      []string{"0x233010DE", "0x233010DE"},
+		ModeChange:     false,
+		ConfigChange:   true,
+		GPUCountChange: true,
+	}
+
+	testCases := []struct {
+		Description string
+		When        string
+		expected    bool
+	}{
+		{"Mode Change", "mode-change", false},
+		{"Config Change", "config-change", true},
+		{"GPU Count Changed", "gpu-count-changed", true},
+		{"Not", "not mode-change", true},
+		{"Double Not", "not not mode-change", false},
+		{"And", "config-change and mode-change", false},
+		{"Or", "mode-change or config-change", true},
+		{"And Binds Tighter Than Or", "config-change or mode-change and not config-change", true},
+		{"Parentheses", "(config-change or mode-change) and not config-change", false},
+		{"Not Parentheses", "not (mode-change or config-change)", false},
+		{"Selected Config In", "selected-config in [all-disabled, all-1g.10gb]", true},
+		{"Selected Config Not In", "selected-config in [all-disabled]", false},
+		{"Selected Config Quoted", `selected-config in ["all-1g.10gb"]`, true},
+		{"Device ID Matches", "device-id matches 0x2330.*", true},
+		{"Device ID Matches Case Insensitive", "device-id matches 0x233010de", true},
+		{"Device ID Matches Whole ID", "device-id matches 0x2330", false},
+		{"Device ID Matches Quoted", `device-id matches '0x23[23]0\w{2}DE'`, true},
+		{"Device ID No Match", "device-id matches 0x20B010DE", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			c, err := ParseCondition(tc.When)
+			require.Nil(t, err, "Unexpected failure ParseCondition")
+			require.Equal(t, tc.expected, c.Evaluate(applyContext))
+
+			// The string representation of a condition must parse back to an equivalent condition.
+			c2, err := ParseCondition(c.String())
+			require.Nil(t, err, "Unexpected failure ParseCondition of '%v'", c)
+			require.Equal(t, tc.expected, c2.Evaluate(applyContext))
+		})
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	testCases := []struct {
+		Description   string
+		When          string
+		expectedError string
+	}{
+		{"Empty", "  ", "empty condition"},
+		{"Misspelled", "config-chnage", "unknown condition 'config-chnage' (did you mean 'config-change'?)"},
+		{"Dangling Operator", "mode-change and", "expected a condition but reached end of condition"},
+		{"Trailing Term", "mode-change config-change", "unexpected 'config-change' after condition 'mode-change'"},
+		{"Unbalanced Parentheses", "(mode-change or config-change", "expected ')' but reached end of condition"},
+		{"Missing In", "selected-config [a]", "selected-config: expected 'in' but found '['"},
+		{"Empty List", "selected-config in []", "selected-config: empty list of configs"},
+		{"Missing Comma", "selected-config in [a b]", "selected-config: expected ',' but found 'b'"},
+		{"Missing Matches", "device-id 0x2330", "device-id: expected 'matches' but found '0x2330'"},
+		{"Invalid Regexp", "device-id matches '0x2330('", "device-id: invalid regular expression '0x2330('"},
+		{"Unterminated Quote", "selected-config in ['a]", "unterminated quoted value"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			_, err := ParseCondition(tc.When)
+			require.NotNil(t, err, "Unexpected success ParseCondition")
+			require.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestRunConditionalHooks(t *testing.T) {
+	hooksMap := HooksMap{
+		PreApplyModeHook: []HookSpec{
+			{Type: SystemdStopHookType, Services: []string{"kubelet.service"}, When: "mode-change"},
+			{Type: WaitNoGPUProcessesHookType, When: "config-change"},
+		},
+		ApplyExitHook: []HookSpec{
+			{Type: SystemdStartHookType, When: "mode-change or gpu-count-changed"},
+		},
+	}
+
+	actions := &testActions{
+		applyContext: &ApplyContext{
+			ConfigChange: true,
+		},
+	}
+
+	err := hooksMap.RunWithActions(PreApplyModeHook, EnvsMap{}, false, actions)
+	require.Nil(t, err, "Unexpected failure HooksMap.RunWithActions")
+	err = hooksMap.RunWithActions(ApplyExitHook, EnvsMap{}, false, actions)
+	require.Nil(t, err, "Unexpected failure HooksMap.RunWithActions")
+	require.Equal(t, []string{"wait 1m0s"}, actions.calls)
+
+	actions.applyContext = nil
+	err = hooksMap.RunWithActions(ApplyExitHook, EnvsMap{}, false, actions)
+	require.NotNil(t, err, "Unexpected success HooksMap.RunWithActions without an apply context")
+
+	err = hooksMap.RunWithActions(ApplyExitHook, EnvsMap{}, false, nil)
+	require.NotNil(t, err, "Unexpected success HooksMap.RunWithActions without actions")
+}
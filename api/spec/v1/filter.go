@@ -0,0 +1,286 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/mig-parted/internal/suggest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// DeviceFilterExpression matches GPUs by their attributes rather than their
+// PCI device ID. A GPU matches if all of the fields set match it.
+type DeviceFilterExpression struct {
+	// Name is a glob matched case-insensitively against the product name of
+	// a GPU, with or without its 'NVIDIA ' prefix (e.g. "H100*80GB*").
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Architecture is matched case-insensitively against the architecture of a GPU (e.g. "hopper").
+	Architecture string `json:"architecture,omitempty" yaml:"architecture,omitempty"`
+	// MemoryGB compares the memory of a GPU, in GB, to a number (e.g. ">=80").
+	MemoryGB string `json:"memory-gb,omitempty" yaml:"memory-gb,omitempty"`
+	// Not is a device filter that a GPU must not match.
+	Not interface{} `json:"not,omitempty" yaml:"not,omitempty"`
+}
+
+// deviceFilterFields are the fields of a 'DeviceFilterExpression'.
+var deviceFilterFields = []string{"name", "architecture", "memory-gb", "not"}
+
+// comparisonOperators are the operators allowed in a comparison with a number (e.g. in 'memory-gb'), longest first.
+var comparisonOperators = []string{">=", "<=", "==", ">", "<", "="}
+
+// parseDeviceFilter parses a device filter: a PCI device ID, an expression,
+// or a list of them that matches if any of its entries does. Lists of PCI
+// device IDs only are kept as a '[]string'.
+func parseDeviceFilter(b json.RawMessage) (interface{}, error) {
+	switch strings.TrimSpace(string(b))[:1] {
+	case "{":
+		var expression DeviceFilterExpression
+		err := json.Unmarshal(b, &expression)
+		if err != nil {
+			return nil, err
+		}
+		return expression, nil
+	case "[":
+		var strslice []string
+		if err := json.Unmarshal(b, &strslice); err == nil {
+			return strslice, nil
+		}
+		var rawslice []json.RawMessage
+		err := json.Unmarshal(b, &rawslice)
+		if err != nil {
+			return nil, err
+		}
+		var filters []interface{}
+		for i, raw := range rawslice {
+			filter, err := parseDeviceFilter(raw)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %v", i, err)
+			}
+			filters = append(filters, filter)
+		}
+		return filters, nil
+	}
+
+	var str string
+	err := json.Unmarshal(b, &str)
+	if err != nil {
+		return nil, err
+	}
+	return str, nil
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'DeviceFilterExpression'.
+func (e *DeviceFilterExpression) UnmarshalJSON(b []byte) error {
+	expression := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &expression)
+	if err != nil {
+		return err
+	}
+
+	if len(expression) == 0 {
+		return fmt.Errorf("empty device filter expression")
+	}
+
+	result := DeviceFilterExpression{}
+	for k, v := range expression {
+		switch k {
+		case "name":
+			err := json.Unmarshal(v, &result.Name)
+			if err != nil {
+				return err
+			}
+			if _, err := path.Match(result.Name, ""); err != nil {
+				return fmt.Errorf("invalid pattern for '%v': %v", k, result.Name)
+			}
+		case "architecture":
+			err := json.Unmarshal(v, &result.Architecture)
+			if err != nil {
+				return err
+			}
+			if _, found := types.LookupArchitecture(result.Architecture); !found {
+				return fmt.Errorf("invalid value for '%v': unknown architecture '%v'%v", k, result.Architecture, suggest.DidYouMean(strings.ToLower(result.Architecture), architectureNames()))
+			}
+		case "memory-gb":
+			memoryGB, err := unmarshalComparison(v)
+			if err != nil {
+				return fmt.Errorf("invalid value for '%v': %v", k, err)
+			}
+			result.MemoryGB = memoryGB
+		case "not":
+			not, err := parseDeviceFilter(v)
+			if err != nil {
+				return fmt.Errorf("error parsing '%v' field: %v", k, err)
+			}
+			result.Not = not
+		default:
+			return fmt.Errorf("unexpected field: %v%v", k, suggest.DidYouMean(k, deviceFilterFields))
+		}
+	}
+
+	*e = result
+	return nil
+}
+
+// architectureNames returns the names of the GPU architectures, in lower case
+// as they are usually written in a device filter.
+func architectureNames() []string {
+	var names []string
+	for _, a := range types.Architectures {
+		names = append(names, strings.ToLower(a))
+	}
+	return names
+}
+
+// parseComparison splits a comparison with a number into its operator and number.
+func parseComparison(s string) (string, uint64, error) {
+	s = strings.TrimSpace(s)
+	operator := "=="
+	for _, op := range comparisonOperators {
+		if strings.HasPrefix(s, op) {
+			operator = op
+			s = strings.TrimSpace(strings.TrimPrefix(s, op))
+			break
+		}
+	}
+	if operator == "=" {
+		operator = "=="
+	}
+
+	number, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("expected '[>=|<=|==|>|<]<number>': %v", err)
+	}
+	return operator, number, nil
+}
+
+// matchesComparison checks whether a value satisfies a comparison with a number.
+func matchesComparison(comparison string, value uint64) bool {
+	operator, number, err := parseComparison(comparison)
+	if err != nil {
+		return false
+	}
+	switch operator {
+	case ">=":
+		return value >= number
+	case "<=":
+		return value <= number
+	case ">":
+		return value > number
+	case "<":
+		return value < number
+	}
+	return value == number
+}
+
+// unmarshalComparison unmarshals a comparison with a number, given as a string or a bare number.
+func unmarshalComparison(b json.RawMessage) (string, error) {
+	var number json.Number
+	err1 := json.Unmarshal(b, &number)
+	if err1 == nil {
+		return number.String(), nil
+	}
+	var comparison string
+	err2 := json.Unmarshal(b, &comparison)
+	if err2 != nil {
+		return "", fmt.Errorf("(%v, %v)", err1, err2)
+	}
+	if _, _, err := parseComparison(comparison); err != nil {
+		return "", err
+	}
+	return comparison, nil
+}
+
+// matchesDeviceFilter checks whether a device filter matches a GPU. A GPU
+// never matches an expression on attributes that could not be resolved for it.
+func matchesDeviceFilter(filter interface{}, device types.DeviceInfo) bool {
+	switch df := filter.(type) {
+	case nil:
+		return true
+	case string:
+		if df == "" {
+			return true
+		}
+		deviceID, _ := types.NewDeviceIDFromString(df)
+		return deviceID.Matches(device.ID)
+	case []string:
+		if len(df) == 0 {
+			return true
+		}
+		for _, f := range df {
+			if matchesDeviceFilter(f, device) {
+				return true
+			}
+		}
+	case []interface{}:
+		if len(df) == 0 {
+			return true
+		}
+		for _, f := range df {
+			if matchesDeviceFilter(f, device) {
+				return true
+			}
+		}
+	case DeviceFilterExpression:
+		return df.Matches(device)
+	case *DeviceFilterExpression:
+		return df.Matches(device)
+	}
+	return false
+}
+
+// Matches checks whether a 'DeviceFilterExpression' matches a GPU.
+func (e *DeviceFilterExpression) Matches(device types.DeviceInfo) bool {
+	if e.Name != "" && !matchesName(e.Name, device.Name) {
+		return false
+	}
+	if e.Architecture != "" && (device.Architecture == "" || !strings.EqualFold(e.Architecture, device.Architecture)) {
+		return false
+	}
+	if e.MemoryGB != "" && !matchesMemory(e.MemoryGB, device) {
+		return false
+	}
+	if e.Not != nil && matchesDeviceFilter(e.Not, device) {
+		return false
+	}
+	return true
+}
+
+func matchesName(pattern, name string) bool {
+	if name == "" {
+		return false
+	}
+	pattern = strings.ToLower(pattern)
+	name = strings.ToLower(name)
+	for _, n := range []string{name, strings.TrimPrefix(name, "nvidia ")} {
+		if matched, _ := path.Match(pattern, n); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMemory(comparison string, device types.DeviceInfo) bool {
+	if device.MemoryMB == 0 {
+		return false
+	}
+	return matchesComparison(comparison, device.MemoryGB())
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestParseDeviceFilter(t *testing.T) {
+	testCases := []struct {
+		description    string
+		filter         string
+		expectedFilter interface{}
+		expectedError  string
+	}{
+		{
+			description:    "PCI device ID",
+			filter:         `"0x233010DE"`,
+			expectedFilter: "0x233010DE",
+		},
+		{
+			description:    "PCI device IDs",
+			filter:         `["0x233010DE", "0x233110DE"]`,
+			expectedFilter: []string{"0x233010DE", "0x233110DE"},
+		},
+		{
+			description:    "Expression",
+			filter:         `{"name": "H100*80GB*", "architecture": "hopper", "memory-gb": ">= 80"}`,
+			expectedFilter: DeviceFilterExpression{Name: "H100*80GB*", Architecture: "hopper", MemoryGB: ">= 80"},
+		},
+		{
+			description:    "Memory as number",
+			filter:         `{"memory-gb": 80}`,
+			expectedFilter: DeviceFilterExpression{MemoryGB: "80"},
+		},
+		{
+			description: "Mixed list with not",
+			filter:      `["0x20B710DE", {"architecture": "hopper", "not": {"name": "H100 NVL"}}]`,
+			expectedFilter: []interface{}{
+				"0x20B710DE",
+				DeviceFilterExpression{Architecture: "hopper", Not: DeviceFilterExpression{Name: "H100 NVL"}},
+			},
+		},
+		{
+			description:   "Empty expression",
+			filter:        `{}`,
+			expectedError: "empty device filter expression",
+		},
+		{
+			description:   "Erroneous field",
+			filter:        `{"bogus": "field"}`,
+			expectedError: "unexpected field: bogus",
+		},
+		{
+			description:   "Misspelled field",
+			filter:        `{"memory": 80}`,
+			expectedError: "unexpected field: memory (did you mean 'memory-gb'?)",
+		},
+		{
+			description:   "Invalid memory comparison",
+			filter:        `[{"memory-gb": "~80"}]`,
+			expectedError: "[0]: invalid value for 'memory-gb'",
+		},
+		{
+			description:   "Unknown architecture",
+			filter:        `{"architecture": "hoppper"}`,
+			expectedError: "invalid value for 'architecture': unknown architecture 'hoppper' (did you mean 'hopper'?)",
+		},
+		{
+			description:    "Architecture in any case",
+			filter:         `{"architecture": "Ada Lovelace"}`,
+			expectedFilter: DeviceFilterExpression{Architecture: "Ada Lovelace"},
+		},
+		{
+			description:   "Invalid name pattern",
+			filter:        `{"name": "H100["}`,
+			expectedError: "invalid pattern for 'name'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			filter, err := parseDeviceFilter([]byte(tc.filter))
+			if tc.expectedError != "" {
+				require.NotNil(t, err, "Unexpected success from parseDeviceFilter")
+				require.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.Nil(t, err, "Unexpected failure from parseDeviceFilter")
+			require.Equal(t, tc.expectedFilter, filter)
+		})
+	}
+}
+
+func TestMigConfigSpecMatchesDevice(t *testing.T) {
+	h100 := types.DeviceInfo{
+		ID:           types.NewDeviceIDWithSubsystem(0x2330, 0x10DE, 0x16C0, 0x10DE),
+		Name:         "NVIDIA H100 80GB HBM3",
+		Architecture: "Hopper",
+		MemoryMB:     81559,
+	}
+	unknown := types.DeviceInfo{
+		ID: types.NewDeviceID(0x2330, 0x10DE),
+	}
+
+	testCases := []struct {
+		description     string
+		filter          string
+		expectedH100    bool
+		expectedUnknown bool
+	}{
+		{
+			description:     "PCI device ID",
+			filter:          `"0x233010DE"`,
+			expectedH100:    true,
+			expectedUnknown: true,
+		},
+		{
+			description:  "Name without prefix",
+			filter:       `{name: "h100*80GB*"}`,
+			expectedH100: true,
+		},
+		{
+			description:  "Name with prefix",
+			filter:       `{name: "NVIDIA H100*"}`,
+			expectedH100: true,
+		},
+		{
+			description: "Other name",
+			filter:      `{name: "H100 NVL"}`,
+		},
+		{
+			description:  "Architecture",
+			filter:       `{architecture: hopper}`,
+			expectedH100: true,
+		},
+		{
+			description:  "Rounded memory",
+			filter:       `{memory-gb: 80}`,
+			expectedH100: true,
+		},
+		{
+			description:  "Memory comparison",
+			filter:       `{memory-gb: ">=80"}`,
+			expectedH100: true,
+		},
+		{
+			description: "Failed memory comparison",
+			filter:      `{memory-gb: "<80"}`,
+		},
+		{
+			description: "All fields must match",
+			filter:      `{architecture: hopper, memory-gb: ">80"}`,
+		},
+		{
+			description:     "Not",
+			filter:          `{not: {architecture: ampere}}`,
+			expectedH100:    true,
+			expectedUnknown: true,
+		},
+		{
+			description:     "Any entry of a list",
+			filter:          `[{architecture: ampere}, "0x233010DE"]`,
+			expectedH100:    true,
+			expectedUnknown: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var s MigConfigSpec
+			err := yaml.Unmarshal([]byte("{devices: all, mig-enabled: false, device-filter: "+tc.filter+"}"), &s)
+			require.Nil(t, err, "Unexpected failure yaml.Unmarshal")
+			require.Equal(t, tc.expectedH100, s.MatchesDevice(h100))
+			require.Equal(t, tc.expectedUnknown, s.MatchesDevice(unknown))
+			require.Equal(t, tc.expectedUnknown, s.MatchesDeviceFilter(unknown.ID))
+		})
+	}
+}
@@ -21,29 +21,22 @@ import (
 )
 
 // MatchesDeviceFilter checks a 'MigConfigSpec' to see if its device filter matches the provided 'deviceID'.
+// Only the ID of the device is known, so device filter expressions on its other attributes never match.
 func (ms *MigConfigSpec) MatchesDeviceFilter(deviceID types.DeviceID) bool {
-	var deviceFilter []string
-	switch df := ms.DeviceFilter.(type) {
-	case string:
-		if df != "" {
-			deviceFilter = append(deviceFilter, df)
-		}
-	case []string:
-		deviceFilter = df
-	}
+	return ms.MatchesDevice(types.DeviceInfo{ID: deviceID})
+}
 
-	if len(deviceFilter) == 0 {
-		return true
-	}
+// MatchesDevice checks a 'MigConfigSpec' to see if its device filter matches the provided 'device'.
+func (ms *MigConfigSpec) MatchesDevice(device types.DeviceInfo) bool {
+	return matchesDeviceFilter(ms.DeviceFilter, device)
+}
 
-	for _, df := range deviceFilter {
-		newDeviceID, _ := types.NewDeviceIDFromString(df)
-		if newDeviceID.Matches(deviceID) {
-			return true
-		}
+// MatchesNode checks a 'MigConfigSpec' to see if its node selector matches the provided 'node'.
+func (ms *MigConfigSpec) MatchesNode(node types.NodeInfo) bool {
+	if ms.NodeSelector == nil {
+		return true
 	}
-
-	return false
+	return ms.NodeSelector.Matches(node)
 }
 
 // MatchesAllDevices checks a 'MigConfigSpec' to see if it matches on 'all' devices.
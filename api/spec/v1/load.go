@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// configFileExtensions are the extensions of the files read from a config directory.
+var configFileExtensions = []string{".yaml", ".yml", ".json"}
+
+// LoadConfigFile reads a 'Spec' from a file, or from every file of a directory
+// (conf.d style), following the 'include' globs of each spec read. The
+// 'mig-configs' of all of them are merged into a single 'Spec', and a MIG
+// config defined more than once is reported as an error with the file and line
+// of each definition.
+//
+// The files of a directory are read in lexical order, skipping hidden files
+// (e.g. the '..data' link of a mounted ConfigMap) and those without a .yaml,
+// .yml or .json extension. Include globs are relative to the including file and
+// only match hidden files if their last element starts with a '.'.
+func LoadConfigFile(path string) (*Spec, error) {
+	l := newLoader()
+	if err := l.loadPath(path); err != nil {
+		return nil, err
+	}
+	return l.spec()
+}
+
+// LoadConfig does the same as LoadConfigFile for a spec read from elsewhere
+// (e.g. stdin), named by source in errors, with its includes relative to dir.
+func LoadConfig(data []byte, source, dir string) (*Spec, error) {
+	l := newLoader()
+	if err := l.load(data, source, dir); err != nil {
+		return nil, err
+	}
+	return l.spec()
+}
+
+type loader struct {
+	configs     map[string]MigConfigSpecSlice
+	definitions map[string][]string
+	loaded      map[string]bool
+}
+
+func newLoader() *loader {
+	return &loader{
+		configs:     make(map[string]MigConfigSpecSlice),
+		definitions: make(map[string][]string),
+		loaded:      make(map[string]bool),
+	}
+}
+
+// loadPath loads a file or directory, unless it was already loaded (e.g. by
+// overlapping includes).
+func (l *loader) loadPath(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	if l.loaded[abs] {
+		return nil
+	}
+	l.loaded[abs] = true
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("read error: %v", err)
+	}
+	if info.IsDir() {
+		return l.loadDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read error: %v", err)
+	}
+	return l.load(data, path, filepath.Dir(path))
+}
+
+func (l *loader) loadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read error: %v", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if isHidden(name) || !slices.Contains(configFileExtensions, filepath.Ext(name)) {
+			continue
+		}
+		if err := l.loadPath(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loader) load(data []byte, source, dir string) error {
+	if errs := Validate(data, source); len(errs) > 0 {
+		return fmt.Errorf("%s: unmarshal error: %w", source, errs)
+	}
+
+	var spec Spec
+	err := yaml.Unmarshal(data, &spec)
+	if err != nil {
+		return fmt.Errorf("%s: unmarshal error: %v", source, err)
+	}
+
+	lines := migConfigLines(data)
+	for _, name := range slices.Sorted(maps.Keys(spec.MigConfigs)) {
+		definition := source
+		if line, found := lines[name]; found {
+			definition = fmt.Sprintf("%s:%d", source, line)
+		}
+		l.definitions[name] = append(l.definitions[name], definition)
+		l.configs[name] = spec.MigConfigs[name]
+	}
+
+	for _, pattern := range spec.Include {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid include '%s': %v", source, pattern, err)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			return fmt.Errorf("%s: included file '%s' not found", source, pattern)
+		}
+		for _, match := range matches {
+			if isHidden(match) && !isHidden(pattern) {
+				continue
+			}
+			if err := l.loadPath(match); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *loader) spec() (*Spec, error) {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(l.definitions)) {
+		if definitions := l.definitions[name]; len(definitions) > 1 {
+			errs = append(errs, fmt.Errorf("duplicate MIG config '%s' defined at %s", name, strings.Join(definitions, " and ")))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	spec := &Spec{Version: Version}
+	if len(l.configs) > 0 {
+		spec.MigConfigs = l.configs
+	}
+	return spec, nil
+}
+
+// isHidden checks whether the base name of a path starts with a '.'.
+func isHidden(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), ".")
+}
+
+// migConfigLines returns the line of each MIG config defined in a YAML (or JSON) spec.
+func migConfigLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return lines
+	}
+	root := doc.Content[0]
+	if root.Kind != yamlv3.MappingNode {
+		return lines
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "mig-configs" || root.Content[i+1].Kind != yamlv3.MappingNode {
+			continue
+		}
+		configs := root.Content[i+1].Content
+		for j := 0; j+1 < len(configs); j += 2 {
+			lines[configs[j].Value] = configs[j].Line
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFiles writes files (by path relative to a new directory) and returns the directory.
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+	return dir
+}
+
+func configFile(names ...string) string {
+	contents := "version: v1\nmig-configs:\n"
+	for _, name := range names {
+		contents += fmt.Sprintf("  %s:\n  - devices: all\n    mig-enabled: false\n", name)
+	}
+	return contents
+}
+
+func configNames(spec *Spec) []string {
+	return slices.Sorted(maps.Keys(spec.MigConfigs))
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"main.yaml":              "version: v1\ninclude: [teams/*.yaml, models/a100.yaml]\n" + configFile("all-disabled")[len("version: v1\n"):],
+		"teams/inference.yaml":   configFile("inference"),
+		"teams/training.yaml":    "version: v1\ninclude: [../shared/*.yaml]\n" + configFile("training")[len("version: v1\n"):],
+		"teams/notes.txt":        "not a config",
+		"models/a100.yaml":       configFile("a100-balanced"),
+		"models/h100.yaml":       configFile("h100-balanced"),
+		"shared/common.yaml":     configFile("common"),
+		"shared/.hidden.yaml":    "not a config",
+		"conf.d/10-first.yaml":   configFile("first"),
+		"conf.d/20-second.yml":   configFile("second"),
+		"conf.d/30-third.json":   `{"version": "v1", "mig-configs": {"third": [{"devices": "all", "mig-enabled": false}]}}`,
+		"conf.d/..data/ignored":  "not a config",
+		"conf.d/README.md":       "not a config",
+		"conf.d/.hidden.yaml":    "not a config",
+		"cycle/a.yaml":           "version: v1\ninclude: [b.yaml]\n" + configFile("a")[len("version: v1\n"):],
+		"cycle/b.yaml":           "version: v1\ninclude: [a.yaml]\n" + configFile("b")[len("version: v1\n"):],
+		"missing/main.yaml":      "version: v1\ninclude: [missing.yaml]\n",
+		"optional/main.yaml":     "version: v1\ninclude: [extra/*.yaml]\n",
+		"invalid/main.yaml":      "version: v1\ninclude: [bad.yaml]\n",
+		"invalid/bad.yaml":       "version: v2\n",
+		"duplicate/a.yaml":       configFile("one", "shared"),
+		"duplicate/b.yaml":       configFile("two", "shared", "one"),
+		"duplicate/c/main.yaml":  "version: v1\ninclude: [../a.yaml]\n",
+		"duplicate/c/other.yaml": configFile("one"),
+	})
+
+	testCases := []struct {
+		description   string
+		path          string
+		expectedNames []string
+		expectedError []string
+	}{
+		{
+			description:   "Includes",
+			path:          "main.yaml",
+			expectedNames: []string{"a100-balanced", "all-disabled", "common", "inference", "training"},
+		},
+		{
+			description:   "Directory",
+			path:          "conf.d",
+			expectedNames: []string{"first", "second", "third"},
+		},
+		{
+			description:   "Include Cycle",
+			path:          "cycle/a.yaml",
+			expectedNames: []string{"a", "b"},
+		},
+		{
+			description:   "Glob Without Matches",
+			path:          "optional/main.yaml",
+			expectedNames: nil,
+		},
+		{
+			description:   "Missing Include",
+			path:          "missing/main.yaml",
+			expectedError: []string{"included file", "missing.yaml' not found"},
+		},
+		{
+			description:   "Invalid Include",
+			path:          "invalid/main.yaml",
+			expectedError: []string{"bad.yaml: unmarshal error", "unknown version"},
+		},
+		{
+			description: "Duplicates",
+			path:        "duplicate",
+			expectedError: []string{
+				"duplicate MIG config 'one' defined at " + filepath.Join(dir, "duplicate", "a.yaml") + ":3 and " + filepath.Join(dir, "duplicate", "b.yaml") + ":9",
+				"duplicate MIG config 'shared' defined at " + filepath.Join(dir, "duplicate", "a.yaml") + ":6 and " + filepath.Join(dir, "duplicate", "b.yaml") + ":6",
+			},
+		},
+		{
+			description:   "Duplicate Through Include",
+			path:          "duplicate/c",
+			expectedError: []string{"duplicate MIG config 'one' defined at " + filepath.Join(dir, "duplicate", "a.yaml") + ":3 and " + filepath.Join(dir, "duplicate", "c", "other.yaml") + ":3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec, err := LoadConfigFile(filepath.Join(dir, tc.path))
+			if len(tc.expectedError) > 0 {
+				require.NotNil(t, err, "Unexpected success from LoadConfigFile")
+				for _, expected := range tc.expectedError {
+					require.Contains(t, err.Error(), expected)
+				}
+				return
+			}
+			require.Nil(t, err, "Unexpected failure from LoadConfigFile")
+			require.Equal(t, Version, spec.Version)
+			require.Empty(t, spec.Include)
+			require.Equal(t, tc.expectedNames, configNames(spec))
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"teams/inference.yaml": configFile("inference"),
+	})
+
+	spec, err := LoadConfig([]byte("version: v1\ninclude: [teams/*.yaml]\n"+configFile("all-disabled")[len("version: v1\n"):]), "<stdin>", dir)
+	require.Nil(t, err, "Unexpected failure from LoadConfig")
+	require.Equal(t, []string{"all-disabled", "inference"}, configNames(spec))
+
+	_, err = LoadConfig([]byte("version: v1\ninclude: [teams/*.yaml]\n"+configFile("inference")[len("version: v1\n"):]), "<stdin>", dir)
+	require.NotNil(t, err, "Unexpected success from LoadConfig")
+	require.Contains(t, err.Error(), "duplicate MIG config 'inference' defined at <stdin>:4 and ")
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/NVIDIA/mig-parted/internal/suggest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// nodeSelectorFields are the fields of a 'NodeSelector'.
+var nodeSelectorFields = []string{"hostname", "os-release", "kernel-version", "gpu-count", "facts"}
+
+// NodeSelector restricts a 'MigConfigSpec' to the nodes it matches, so that a
+// single MIG config can hold different entries for different kinds of nodes.
+// A node matches if all of the fields set match it.
+type NodeSelector struct {
+	// Hostname is a glob matched case-insensitively against the hostname of the node.
+	Hostname string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	// OSRelease maps fields of /etc/os-release (e.g. 'ID') to globs matching their values.
+	OSRelease map[string]string `json:"os-release,omitempty" yaml:"os-release,omitempty"`
+	// KernelVersion is a glob matched against the version of the running kernel.
+	KernelVersion string `json:"kernel-version,omitempty" yaml:"kernel-version,omitempty"`
+	// GPUCount compares the number of GPUs of the node to a number (e.g. ">=4").
+	GPUCount string `json:"gpu-count,omitempty" yaml:"gpu-count,omitempty"`
+	// Facts maps keys of the facts file of the node to globs matching their values.
+	Facts map[string]string `json:"facts,omitempty" yaml:"facts,omitempty"`
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'NodeSelector'.
+func (ns *NodeSelector) UnmarshalJSON(b []byte) error {
+	selector := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &selector)
+	if err != nil {
+		return err
+	}
+
+	if len(selector) == 0 {
+		return fmt.Errorf("empty node selector")
+	}
+
+	result := NodeSelector{}
+	for k, v := range selector {
+		switch k {
+		case "hostname", "kernel-version":
+			var glob string
+			err := json.Unmarshal(v, &glob)
+			if err != nil {
+				return err
+			}
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("invalid pattern for '%v': %v", k, glob)
+			}
+			if k == "hostname" {
+				result.Hostname = glob
+			} else {
+				result.KernelVersion = glob
+			}
+		case "os-release", "facts":
+			globs, err := unmarshalGlobs(v)
+			if err != nil {
+				return fmt.Errorf("error parsing '%v' field: %v", k, err)
+			}
+			if k == "os-release" {
+				result.OSRelease = globs
+			} else {
+				result.Facts = globs
+			}
+		case "gpu-count":
+			gpuCount, err := unmarshalComparison(v)
+			if err != nil {
+				return fmt.Errorf("invalid value for '%v': %v", k, err)
+			}
+			result.GPUCount = gpuCount
+		default:
+			return fmt.Errorf("unexpected field: %v%v", k, suggest.DidYouMean(k, nodeSelectorFields))
+		}
+	}
+
+	*ns = result
+	return nil
+}
+
+// unmarshalGlobs unmarshals a map of keys to globs. Numbers and booleans are
+// taken as strings, as YAML turns unquoted values like 'VERSION_ID: 22.04' into them.
+func unmarshalGlobs(b json.RawMessage) (map[string]string, error) {
+	raw := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	globs := make(map[string]string)
+	for k, v := range raw {
+		var glob string
+		if err := json.Unmarshal(v, &glob); err != nil {
+			var scalar interface{}
+			if err := json.Unmarshal(v, &scalar); err != nil {
+				return nil, err
+			}
+			switch scalar.(type) {
+			case float64, bool:
+				glob = strings.TrimSpace(string(v))
+			default:
+				return nil, fmt.Errorf("invalid value for '%v': expected a string", k)
+			}
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern for '%v': %v", k, glob)
+		}
+		globs[k] = glob
+	}
+	return globs, nil
+}
+
+// String returns the fields set in a 'NodeSelector' as a comma-separated list of 'field=glob'.
+func (ns *NodeSelector) String() string {
+	var fields []string
+	if ns.Hostname != "" {
+		fields = append(fields, "hostname="+ns.Hostname)
+	}
+	for _, k := range slices.Sorted(maps.Keys(ns.OSRelease)) {
+		fields = append(fields, "os-release."+k+"="+ns.OSRelease[k])
+	}
+	if ns.KernelVersion != "" {
+		fields = append(fields, "kernel-version="+ns.KernelVersion)
+	}
+	if ns.GPUCount != "" {
+		fields = append(fields, "gpu-count="+ns.GPUCount)
+	}
+	for _, k := range slices.Sorted(maps.Keys(ns.Facts)) {
+		fields = append(fields, "facts."+k+"="+ns.Facts[k])
+	}
+	return strings.Join(fields, ", ")
+}
+
+// Matches checks whether a 'NodeSelector' matches a node.
+func (ns *NodeSelector) Matches(node types.NodeInfo) bool {
+	if ns.Hostname != "" && !matchesGlob(strings.ToLower(ns.Hostname), strings.ToLower(node.Hostname)) {
+		return false
+	}
+	if ns.KernelVersion != "" && !matchesGlob(ns.KernelVersion, node.KernelVersion) {
+		return false
+	}
+	if ns.GPUCount != "" && !matchesComparison(ns.GPUCount, uint64(node.GPUCount)) {
+		return false
+	}
+	return matchesGlobs(ns.OSRelease, node.OSRelease) && matchesGlobs(ns.Facts, node.Facts)
+}
+
+func matchesGlob(glob, value string) bool {
+	matched, _ := path.Match(glob, value)
+	return matched
+}
+
+// matchesGlobs checks that every key of globs exists in values with a matching value.
+func matchesGlobs(globs, values map[string]string) bool {
+	for k, glob := range globs {
+		value, exists := values[k]
+		if !exists || !matchesGlob(glob, value) {
+			return false
+		}
+	}
+	return true
+}
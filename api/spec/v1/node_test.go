@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestParseNodeSelector(t *testing.T) {
+	testCases := []struct {
+		description      string
+		selector         string
+		expectedSelector *NodeSelector
+		expectedError    string
+	}{
+		{
+			description: "All fields",
+			selector:    `{hostname: "login-*", os-release: {ID: ubuntu, VERSION_ID: 22.04}, kernel-version: "5.15.*", gpu-count: 4, facts: {role: login}}`,
+			expectedSelector: &NodeSelector{
+				Hostname:      "login-*",
+				OSRelease:     map[string]string{"ID": "ubuntu", "VERSION_ID": "22.04"},
+				KernelVersion: "5.15.*",
+				GPUCount:      "4",
+				Facts:         map[string]string{"role": "login"},
+			},
+		},
+		{
+			description:      "GPU count comparison",
+			selector:         `{gpu-count: ">= 8"}`,
+			expectedSelector: &NodeSelector{GPUCount: ">= 8"},
+		},
+		{
+			description:   "Empty selector",
+			selector:      `{}`,
+			expectedError: "empty node selector",
+		},
+		{
+			description:   "Erroneous field",
+			selector:      `{bogus: field}`,
+			expectedError: "unexpected field: bogus",
+		},
+		{
+			description:   "Invalid GPU count",
+			selector:      `{gpu-count: "a few"}`,
+			expectedError: "invalid value for 'gpu-count'",
+		},
+		{
+			description:   "Invalid hostname pattern",
+			selector:      `{hostname: "login-["}`,
+			expectedError: "invalid pattern for 'hostname'",
+		},
+		{
+			description:   "Invalid fact value",
+			selector:      `{facts: {role: [login]}}`,
+			expectedError: "invalid value for 'role'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var s MigConfigSpec
+			err := yaml.Unmarshal([]byte("{devices: all, mig-enabled: false, node-selector: "+tc.selector+"}"), &s)
+			if tc.expectedError != "" {
+				require.NotNil(t, err, "Unexpected success yaml.Unmarshal")
+				require.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.Nil(t, err, "Unexpected failure yaml.Unmarshal")
+			require.Equal(t, tc.expectedSelector, s.NodeSelector)
+		})
+	}
+}
+
+func TestMigConfigSpecMatchesNode(t *testing.T) {
+	node := types.NodeInfo{
+		Hostname:      "Login-03",
+		OSRelease:     map[string]string{"ID": "ubuntu", "VERSION_ID": "22.04"},
+		KernelVersion: "5.15.0-91-generic",
+		GPUCount:      4,
+		Facts:         map[string]string{"role": "login"},
+	}
+
+	testCases := []struct {
+		description string
+		selector    *NodeSelector
+		expected    bool
+	}{
+		{
+			description: "No selector",
+			expected:    true,
+		},
+		{
+			description: "Hostname",
+			selector:    &NodeSelector{Hostname: "login-*"},
+			expected:    true,
+		},
+		{
+			description: "Other hostname",
+			selector:    &NodeSelector{Hostname: "compute-*"},
+		},
+		{
+			description: "OS release",
+			selector:    &NodeSelector{OSRelease: map[string]string{"ID": "ubuntu", "VERSION_ID": "22.*"}},
+			expected:    true,
+		},
+		{
+			description: "Missing OS release field",
+			selector:    &NodeSelector{OSRelease: map[string]string{"VARIANT_ID": "*"}},
+		},
+		{
+			description: "Kernel version",
+			selector:    &NodeSelector{KernelVersion: "5.15.*"},
+			expected:    true,
+		},
+		{
+			description: "GPU count",
+			selector:    &NodeSelector{GPUCount: "<8"},
+			expected:    true,
+		},
+		{
+			description: "Other GPU count",
+			selector:    &NodeSelector{GPUCount: "8"},
+		},
+		{
+			description: "Facts",
+			selector:    &NodeSelector{Facts: map[string]string{"role": "login"}},
+			expected:    true,
+		},
+		{
+			description: "All fields must match",
+			selector:    &NodeSelector{Hostname: "login-*", Facts: map[string]string{"role": "compute"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec := MigConfigSpec{NodeSelector: tc.selector, Devices: "all"}
+			require.Equal(t, tc.expected, spec.MatchesNode(node))
+		})
+	}
+}
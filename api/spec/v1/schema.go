@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"maps"
+	"reflect"
+
+	"github.com/NVIDIA/mig-parted/internal/jsonschema"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// MigProfilePattern matches the names of MIG profiles (e.g. "1g.5gb",
+// "1c.2g.10gb" or "1g.10gb+me").
+const MigProfilePattern = `^([0-9]+c\.)?[0-9]+g\.[0-9]+gb` + migProfileAttributesPattern + `$`
+
+// GpuInstanceProfilePattern matches the names of GPU instance profiles, which
+// do not name a compute instance (e.g. "3g.20gb").
+const GpuInstanceProfilePattern = `^[0-9]+g\.[0-9]+gb` + migProfileAttributesPattern + `$`
+
+const migProfileAttributesPattern = `([+-][A-Za-z]([A-Za-z0-9.]*[A-Za-z0-9])?(,[A-Za-z]([A-Za-z0-9.]*[A-Za-z0-9])?)*)?`
+
+// comparisonPattern matches a comparison with a number given as a string (e.g. ">=80").
+const comparisonPattern = `^\s*(>=|<=|==|>|<|=)?\s*[0-9]+\s*$`
+
+// migDeviceCountPattern matches a count of a MIG profile given as a string:
+// a number, a percentage, or one of the fill counts.
+const migDeviceCountPattern = `^\s*([0-9]+|0*([1-9][0-9]?|100)\s*%|\*|[Ff][Ii][Ll][Ll]|[Mm][Aa][Xx])\s*$`
+
+// Schema returns the JSON Schema of a config file, generated from the 'Spec'
+// type. The fields parsed by a custom 'UnmarshalJSON' (e.g. 'devices' or
+// 'device-filter') are described by the forms they accept. Constraints
+// spanning several values (e.g. that not all counts of 'mig-devices' are 0)
+// are left to the validation done when the file is loaded.
+func Schema() *jsonschema.Schema {
+	r := jsonschema.Reflector{
+		Fields: map[string]*jsonschema.Schema{
+			"Spec.version":                     {Type: jsonschema.Types{"string"}, Const: Version},
+			"MigConfigSpec.device-filter":      jsonschema.Ref("DeviceFilter"),
+			"MigConfigSpec.devices":            devicesSchema(),
+			"MigConfigSpec.mig-devices":        jsonschema.Ref("MigDevices"),
+			"DeviceFilterExpression.not":       jsonschema.Ref("DeviceFilter"),
+			"DeviceFilterExpression.name":      {Type: jsonschema.Types{"string"}},
+			"DeviceFilterExpression.memory-gb": jsonschema.Ref("Comparison"),
+			"NodeSelector.gpu-count":           jsonschema.Ref("Comparison"),
+			"NodeSelector.os-release":          globsSchema(),
+			"NodeSelector.facts":               globsSchema(),
+			"GpuInstanceConfig.profile":        {Type: jsonschema.Types{"string"}, Pattern: GpuInstanceProfilePattern},
+			"GpuInstanceConfig.placement":      {Type: jsonschema.Types{"integer"}, Minimum: jsonschema.Number(0)},
+			"GpuInstanceConfig.compute-instances": {
+				Type:  jsonschema.Types{"array"},
+				Items: jsonschema.Ref("MigProfile"),
+			},
+		},
+	}
+
+	r.Define("MigProfile", &jsonschema.Schema{Type: jsonschema.Types{"string"}, Pattern: MigProfilePattern})
+	r.Define("Comparison", &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: jsonschema.Types{"number"}},
+			{Type: jsonschema.Types{"string"}, Pattern: comparisonPattern},
+		},
+	})
+	r.Define("DeviceFilter", &jsonschema.Schema{
+		Description: "A PCI device ID (e.g. \"0x20B010DE\"), an expression on the attributes of a GPU, or a list of them matching if any of its entries does",
+		AnyOf: []*jsonschema.Schema{
+			{Type: jsonschema.Types{"string"}},
+			{Type: jsonschema.Types{"array"}, Items: jsonschema.Ref("DeviceFilter")},
+			r.Schema(reflect.TypeFor[DeviceFilterExpression]()),
+		},
+	})
+	r.Define("MigDevices", &jsonschema.Schema{
+		Description:   "The count of each MIG profile: a number, a percentage (e.g. \"50%\"), one of \"*\", \"fill\" or \"max\", or a range (e.g. {min: 2, max: 4})",
+		Type:          jsonschema.Types{"object"},
+		PropertyNames: jsonschema.Ref("MigProfile"),
+		AdditionalProperties: &jsonschema.Schema{
+			AnyOf: []*jsonschema.Schema{
+				{Type: jsonschema.Types{"integer"}, Minimum: jsonschema.Number(0)},
+				{Type: jsonschema.Types{"string"}, Pattern: migDeviceCountPattern},
+				{
+					Type: jsonschema.Types{"object"},
+					Properties: map[string]*jsonschema.Schema{
+						"min": {Type: jsonschema.Types{"integer"}, Minimum: jsonschema.Number(0)},
+						"max": {Type: jsonschema.Types{"integer"}},
+					},
+					AdditionalProperties: jsonschema.False(),
+				},
+			},
+		},
+	})
+
+	schema := r.Reflect(reflect.TypeFor[Spec]())
+	schema.Title = "nvidia-mig-parted config file"
+
+	// Templates and the entries extending them hold any of the fields of a
+	// 'MigConfigSpec', along with the templates they extend.
+	spec := r.Definition("Spec")
+	spec.Properties["templates"] = &jsonschema.Schema{
+		Type:                 jsonschema.Types{"object"},
+		AdditionalProperties: jsonschema.Ref("Template"),
+	}
+	spec.Properties["mig-configs"].MinProperties = jsonschema.Int(1)
+	r.Definition("MigConfigSpecSlice").MinItems = jsonschema.Int(1)
+	r.Definition("MigConfigSpecSlice").Items = &jsonschema.Schema{
+		If:   &jsonschema.Schema{Required: []string{extendsField}},
+		Then: jsonschema.Ref("Template"),
+		Else: jsonschema.Ref("MigConfigSpec"),
+	}
+
+	migConfigSpec := r.Definition("MigConfigSpec")
+	migConfigSpec.Properties["gpu-instances"] = &jsonschema.Schema{
+		Type:     jsonschema.Types{"array"},
+		MinItems: jsonschema.Int(1),
+		Items:    r.Schema(reflect.TypeFor[types.GpuInstanceConfig]()),
+	}
+	migConfigSpec.Required = []string{"devices", "mig-enabled"}
+
+	template := &jsonschema.Schema{
+		Type:                 jsonschema.Types{"object"},
+		Properties:           maps.Clone(migConfigSpec.Properties),
+		AdditionalProperties: jsonschema.False(),
+		Not:                  &jsonschema.Schema{Required: migDevicesFields},
+	}
+	template.Properties[extendsField] = &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: jsonschema.Types{"string"}},
+			{Type: jsonschema.Types{"array"}, MinItems: jsonschema.Int(1), Items: &jsonschema.Schema{Type: jsonschema.Types{"string"}}},
+		},
+	}
+	r.Define("Template", template)
+
+	// MIG devices are declared in one of their two forms, and only when MIG
+	// is enabled.
+	migConfigSpec.Not = &jsonschema.Schema{Required: migDevicesFields}
+	migConfigSpec.If = &jsonschema.Schema{
+		Properties: map[string]*jsonschema.Schema{"mig-enabled": {Const: true}},
+	}
+	migConfigSpec.Then = &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Required: []string{"mig-devices"}},
+			{Required: []string{"gpu-instances"}},
+		},
+	}
+	migConfigSpec.Else = &jsonschema.Schema{
+		Properties: map[string]*jsonschema.Schema{"mig-devices": {MaxProperties: jsonschema.Int(0)}},
+		Not:        &jsonschema.Schema{Required: []string{"gpu-instances"}},
+	}
+
+	for _, name := range []string{"NodeSelector", "DeviceFilterExpression"} {
+		r.Definition(name).MinProperties = jsonschema.Int(1)
+	}
+
+	return schema
+}
+
+// devicesSchema describes the 'devices' field: "all" or a list of GPU indices.
+func devicesSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Const: "all"},
+			{Type: jsonschema.Types{"array"}, Items: &jsonschema.Schema{Type: jsonschema.Types{"integer"}}},
+		},
+	}
+}
+
+// globsSchema describes a map of keys to globs, whose values YAML may turn
+// into numbers or booleans.
+func globsSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:                 jsonschema.Types{"object"},
+		AdditionalProperties: &jsonschema.Schema{Type: jsonschema.Types{"string", "number", "boolean"}},
+	}
+}
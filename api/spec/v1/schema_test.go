@@ -0,0 +1,530 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestSchema(t *testing.T) {
+	testCases := []struct {
+		description string
+		spec        string
+		valid       bool
+	}{
+		{
+			"Flat MIG devices",
+			`
+version: v1
+mig-configs:
+  all-disabled:
+  - devices: all
+    mig-enabled: false
+    mig-devices: {}
+  mixed:
+  - devices: [0, 1]
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": 2
+      "1c.2g.10gb": 1
+      "1g.10gb+me": 1
+`,
+			true,
+		},
+		{
+			"MIG device requests",
+			`
+version: v1
+mig-configs:
+  fill:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      "3g.20gb": "50%"
+      "2g.10gb": {min: 1, max: 2}
+      "1g.5gb": fill
+`,
+			true,
+		},
+		{
+			"GPU instances",
+			`
+version: v1
+mig-configs:
+  shared:
+  - devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.20gb
+      placement: 4
+      compute-instances: [1c.3g.20gb, 2c.3g.20gb]
+    - profile: 4g.20gb
+`,
+			true,
+		},
+		{
+			"Device filters and node selectors",
+			`
+version: v1
+mig-configs:
+  filtered:
+  - node-selector:
+      hostname: "node-*"
+      os-release:
+        VERSION_ID: 22.04
+      gpu-count: ">=4"
+    device-filter:
+    - "0x20B010DE"
+    - name: "H100*"
+      memory-gb: 80
+      not: {architecture: ampere}
+    devices: all
+    mig-enabled: false
+`,
+			true,
+		},
+		{
+			"Templates",
+			`
+version: v1
+templates:
+  base:
+    devices: all
+    mig-enabled: true
+  a100:
+    extends: base
+    device-filter: "0x20B010DE"
+mig-configs:
+  all-1g:
+  - extends: [a100]
+    mig-devices:
+      "1g.5gb": 7
+`,
+			true,
+		},
+		{
+			"Missing version",
+			`
+mig-configs:
+  all-disabled:
+  - devices: all
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Unknown version",
+			`
+version: v2
+mig-configs:
+  all-disabled:
+  - devices: all
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Unknown top-level field",
+			`
+version: v1
+mig-config: {}
+`,
+			false,
+		},
+		{
+			"No MIG configs",
+			`
+version: v1
+mig-configs: {}
+`,
+			false,
+		},
+		{
+			"Empty MIG config",
+			`
+version: v1
+mig-configs:
+  empty: []
+`,
+			false,
+		},
+		{
+			"Unknown entry field",
+			`
+version: v1
+mig-configs:
+  all-disabled:
+  - devices: all
+    mig-enable: false
+`,
+			false,
+		},
+		{
+			"Missing devices",
+			`
+version: v1
+mig-configs:
+  all-disabled:
+  - mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Invalid devices string",
+			`
+version: v1
+mig-configs:
+  all-disabled:
+  - devices: some
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Invalid device index",
+			`
+version: v1
+mig-configs:
+  all-disabled:
+  - devices: [first]
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Missing MIG devices",
+			`
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enabled: true
+`,
+			false,
+		},
+		{
+			"MIG devices with MIG disabled",
+			`
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enabled: false
+    mig-devices:
+      "1g.5gb": 7
+`,
+			false,
+		},
+		{
+			"Invalid profile name",
+			`
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      "1x.5gb": 7
+`,
+			false,
+		},
+		{
+			"Negative count",
+			`
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": -1
+`,
+			false,
+		},
+		{
+			"Invalid count string",
+			`
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": lots
+`,
+			false,
+		},
+		{
+			"Percentage out of range",
+			`
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": "150%"
+`,
+			false,
+		},
+		{
+			"Unknown range field",
+			`
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": {min: 1, most: 2}
+`,
+			false,
+		},
+		{
+			"Both MIG devices and GPU instances",
+			`
+version: v1
+mig-configs:
+  shared:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": 1
+    gpu-instances:
+    - profile: 3g.20gb
+`,
+			false,
+		},
+		{
+			"Compute instance profile as GPU instance",
+			`
+version: v1
+mig-configs:
+  shared:
+  - devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 1c.3g.20gb
+`,
+			false,
+		},
+		{
+			"Negative placement",
+			`
+version: v1
+mig-configs:
+  shared:
+  - devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.20gb
+      placement: -1
+`,
+			false,
+		},
+		{
+			"Unknown GPU instance field",
+			`
+version: v1
+mig-configs:
+  shared:
+  - devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.20gb
+      compute-instance: [1c.3g.20gb]
+`,
+			false,
+		},
+		{
+			"Empty device filter expression",
+			`
+version: v1
+mig-configs:
+  filtered:
+  - device-filter: {}
+    devices: all
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Unknown device filter field",
+			`
+version: v1
+mig-configs:
+  filtered:
+  - device-filter: {arch: hopper}
+    devices: all
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Invalid memory comparison",
+			`
+version: v1
+mig-configs:
+  filtered:
+  - device-filter: {memory-gb: "lots"}
+    devices: all
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Empty node selector",
+			`
+version: v1
+mig-configs:
+  selected:
+  - node-selector: {}
+    devices: all
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Invalid GPU count comparison",
+			`
+version: v1
+mig-configs:
+  selected:
+  - node-selector: {gpu-count: "~4"}
+    devices: all
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Unknown template field",
+			`
+version: v1
+templates:
+  base:
+    device: all
+mig-configs:
+  all-disabled:
+  - extends: base
+    mig-enabled: false
+`,
+			false,
+		},
+		{
+			"Empty extends",
+			`
+version: v1
+templates:
+  base:
+    devices: all
+mig-configs:
+  all-disabled:
+  - extends: []
+    mig-enabled: false
+`,
+			false,
+		},
+	}
+
+	schema := Schema()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			data, err := yaml.YAMLToJSON([]byte(tc.spec))
+			require.Nil(t, err)
+
+			err = schema.Validate(data)
+			if tc.valid {
+				require.Nil(t, err, "schema")
+			} else {
+				require.NotNil(t, err, "schema")
+			}
+
+			var spec Spec
+			err = json.Unmarshal(data, &spec)
+			if tc.valid {
+				require.Nil(t, err, "UnmarshalJSON")
+			} else {
+				require.NotNil(t, err, "UnmarshalJSON")
+			}
+		})
+	}
+}
+
+func TestSchemaExamples(t *testing.T) {
+	files, err := filepath.Glob("../../../examples/*.yaml")
+	require.Nil(t, err)
+
+	schema := Schema()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.Nil(t, err)
+
+		var fields map[string]json.RawMessage
+		require.Nil(t, yaml.Unmarshal(data, &fields))
+		if _, exists := fields["mig-configs"]; !exists {
+			continue
+		}
+
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := yaml.YAMLToJSON(data)
+			require.Nil(t, err)
+			require.Nil(t, schema.Validate(data))
+		})
+	}
+}
+
+func TestMigProfilePattern(t *testing.T) {
+	testCases := []struct {
+		profile string
+		valid   bool
+	}{
+		{"1g.5gb", true},
+		{"1c.2g.10gb", true},
+		{"1g.10gb+me", true},
+		{"1g.10gb+me.all", true},
+		{"7g.80gb-me", true},
+		{"1g.10gb+me,gfx", true},
+		{"", false},
+		{"1g", false},
+		{"1g.5", false},
+		{"1x.5gb", false},
+		{"1c.1g.2g.5gb", false},
+		{"1g.10gb+", false},
+		{"1g.10gb+1me", false},
+		{"1g.10gb+me.", false},
+		{"1g.10gb+me,", false},
+		{"1g.10gb+me-gfx", false},
+	}
+
+	pattern := regexp.MustCompile(MigProfilePattern)
+	for _, tc := range testCases {
+		t.Run(tc.profile, func(t *testing.T) {
+			require.Equal(t, tc.valid, pattern.MatchString(tc.profile), "pattern")
+			err := types.AssertValidMigProfileFormat(tc.profile)
+			require.Equal(t, tc.valid, err == nil, "AssertValidMigProfileFormat")
+		})
+	}
+}
@@ -17,33 +17,67 @@
 package v1
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
+	"github.com/NVIDIA/mig-parted/internal/suggest"
 	"github.com/NVIDIA/mig-parted/pkg/types"
 )
 
 // Version indicates the version of the 'Spec' struct used to hold information on 'MigConfigs'.
 const Version = "v1"
 
+// specFields are the fields of a 'Spec', as written in a config file.
+var specFields = []string{"version", "include", "templates", "mig-configs"}
+
+// migConfigSpecFields are the fields of a 'MigConfigSpec'.
+var migConfigSpecFields = []string{"node-selector", "device-filter", "devices", "mig-enabled", "mig-devices", "gpu-instances"}
+
+// expectedDevices describes the values allowed in the 'devices' field.
+const expectedDevices = "expected 'all' or a list of GPU indices"
+
 // Spec is a versioned struct used to hold information on 'MigConfigs'.
 type Spec struct {
 	Version    string                        `json:"version"               yaml:"version"`
+	Include    []string                      `json:"include,omitempty"     yaml:"include,omitempty"`
 	MigConfigs map[string]MigConfigSpecSlice `json:"mig-configs,omitempty" yaml:"mig-configs,omitempty"`
 }
 
 // MigConfigSpec defines the spec to declare the desired MIG configuration for a set of GPUs.
 type MigConfigSpec struct {
+	NodeSelector *NodeSelector   `json:"node-selector,omitempty" yaml:"node-selector,flow,omitempty"`
 	DeviceFilter interface{}     `json:"device-filter,omitempty" yaml:"device-filter,flow,omitempty"`
 	Devices      interface{}     `json:"devices"                 yaml:"devices,flow"`
 	MigEnabled   bool            `json:"mig-enabled"             yaml:"mig-enabled"`
 	MigDevices   types.MigConfig `json:"mig-devices"             yaml:"mig-devices"`
+	// MigDeviceRequests holds the 'mig-devices' of an entry whose counts are
+	// resolved against each GPU at apply time (e.g. "fill"), in which case
+	// 'MigDevices' is nil.
+	MigDeviceRequests types.MigDeviceRequests `json:"-" yaml:"-"`
+	// GpuInstances holds the 'gpu-instances' of an entry declaring which compute
+	// instances share a GPU instance, in which case 'MigDevices' holds the
+	// number of compute instances of each profile.
+	GpuInstances types.GpuInstancesConfig `json:"-" yaml:"-"`
+}
+
+// migConfigSpecOutput is the serialized form of a 'MigConfigSpec' whose MIG
+// devices are not a plain 'MigConfig'.
+type migConfigSpecOutput struct {
+	NodeSelector *NodeSelector            `json:"node-selector,omitempty" yaml:"node-selector,flow,omitempty"`
+	DeviceFilter interface{}              `json:"device-filter,omitempty" yaml:"device-filter,flow,omitempty"`
+	Devices      interface{}              `json:"devices"                 yaml:"devices,flow"`
+	MigEnabled   bool                     `json:"mig-enabled"             yaml:"mig-enabled"`
+	MigDevices   types.MigDeviceRequests  `json:"mig-devices,omitempty"   yaml:"mig-devices,omitempty"`
+	GpuInstances types.GpuInstancesConfig `json:"gpu-instances,omitempty" yaml:"gpu-instances,omitempty"`
 }
 
 // MigConfigSpecSlice represents a slice of 'MigConfigSpec'.
 type MigConfigSpecSlice []MigConfigSpec
 
-// UnmarshalJSON unmarshals raw bytes into a versioned 'Spec'.
+// UnmarshalJSON unmarshals raw bytes into a versioned 'Spec'. The entries of
+// 'mig-configs' that extend the spec's 'templates' are expanded in place, so the
+// templates themselves are not part of the resulting 'Spec'.
 func (s *Spec) UnmarshalJSON(b []byte) error {
 	spec := make(map[string]json.RawMessage)
 	err := json.Unmarshal(b, &spec)
@@ -72,11 +106,20 @@ func (s *Spec) UnmarshalJSON(b []byte) error {
 	}
 
 	delete(spec, "version")
+
+	templates := make(templates)
+	if v, exists := spec["templates"]; exists {
+		templates, err = parseTemplates(v)
+		if err != nil {
+			return err
+		}
+		delete(spec, "templates")
+	}
+
 	for k, v := range spec {
 		switch k {
 		case "mig-configs":
-			configs := map[string]MigConfigSpecSlice{}
-			err := json.Unmarshal(v, &configs)
+			configs, err := expandMigConfigs(v, templates)
 			if err != nil {
 				return err
 			}
@@ -89,8 +132,15 @@ func (s *Spec) UnmarshalJSON(b []byte) error {
 				}
 			}
 			result.MigConfigs = configs
+		case "include":
+			var include []string
+			err := json.Unmarshal(v, &include)
+			if err != nil {
+				return err
+			}
+			result.Include = include
 		default:
-			return fmt.Errorf("unexpected field: %v", k)
+			return fmt.Errorf("unexpected field: %v%v", k, suggest.DidYouMean(k, specFields))
 		}
 	}
 
@@ -115,71 +165,146 @@ func (s *MigConfigSpec) UnmarshalJSON(b []byte) error {
 
 	result := MigConfigSpec{}
 	for k, v := range spec {
-		switch k {
-		case "device-filter":
-			var str string
-			err1 := json.Unmarshal(v, &str)
-			if err1 == nil {
-				result.DeviceFilter = str
-				break
-			}
-			var strslice []string
-			err2 := json.Unmarshal(v, &strslice)
-			if err2 == nil {
-				result.DeviceFilter = strslice
-				break
-			}
-			return fmt.Errorf("(%v, %v)", err1, err2)
-		case "devices":
-			var str string
-			err1 := json.Unmarshal(v, &str)
-			if err1 == nil {
-				if str != "all" {
-					return fmt.Errorf("invalid string input for '%v': %v", k, str)
-				}
-				result.Devices = str
-				break
-			}
-			var intslice []int
-			err2 := json.Unmarshal(v, &intslice)
-			if err2 == nil {
-				result.Devices = intslice
-				break
+		err := result.setField(k, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	if result.GpuInstances != nil {
+		if containsKey(spec, "mig-devices") {
+			return fmt.Errorf("only one of 'mig-devices' and 'gpu-instances' may be set")
+		}
+		result.MigDevices = result.GpuInstances.MigConfig()
+	}
+
+	if result.MigEnabled && result.MigDevices == nil && result.MigDeviceRequests == nil {
+		return fmt.Errorf("missing required field 'mig-devices' when 'mig-enabled' is true")
+	}
+
+	if !result.MigEnabled && (len(result.MigDevices) != 0 || len(result.MigDeviceRequests) != 0) {
+		return fmt.Errorf("MIG devices included when 'mig-enabled' is false")
+	}
+
+	*s = result
+	return nil
+}
+
+// setField parses the field k of a 'MigConfigSpec', checking its value is
+// valid on its own.
+func (s *MigConfigSpec) setField(k string, v json.RawMessage) error {
+	switch k {
+	case "node-selector":
+		var selector NodeSelector
+		err := json.Unmarshal(v, &selector)
+		if err != nil {
+			return fmt.Errorf("error parsing '%v' field: %v", k, err)
+		}
+		s.NodeSelector = &selector
+	case "device-filter":
+		filter, err := parseDeviceFilter(v)
+		if err != nil {
+			return fmt.Errorf("error parsing '%v' field: %v", k, err)
+		}
+		s.DeviceFilter = filter
+	case "devices":
+		var str string
+		if err := json.Unmarshal(v, &str); err == nil {
+			if str != "all" {
+				return fmt.Errorf("invalid string input for '%v': %v (%v)", k, str, expectedDevices)
 			}
-			return fmt.Errorf("(%v, %v)", err1, err2)
-		case "mig-enabled":
-			var enabled bool
-			err := json.Unmarshal(v, &enabled)
-			if err != nil {
-				return err
+			s.Devices = str
+			break
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(v, &elements); err != nil {
+			return fmt.Errorf("invalid value for '%v': %v", k, expectedDevices)
+		}
+		var intslice []int
+		for i, element := range elements {
+			var index int
+			if err := json.Unmarshal(element, &index); err != nil {
+				return fmt.Errorf("invalid GPU index in '%v'[%d]: %v (%v)", k, i, string(element), expectedDevices)
 			}
-			result.MigEnabled = enabled
-		case "mig-devices":
-			devices := make(types.MigConfig)
-			err := json.Unmarshal(v, &devices)
+			intslice = append(intslice, index)
+		}
+		s.Devices = intslice
+	case "gpu-instances":
+		var gpuInstances types.GpuInstancesConfig
+		decoder := json.NewDecoder(bytes.NewReader(v))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&gpuInstances)
+		if err != nil {
+			return fmt.Errorf("error parsing '%v' field: %v", k, err)
+		}
+		err = gpuInstances.AssertValidFormat()
+		if err != nil {
+			return fmt.Errorf("error validating values in '%v' field: %v", k, err)
+		}
+		s.GpuInstances = gpuInstances
+	case "mig-enabled":
+		var enabled bool
+		err := json.Unmarshal(v, &enabled)
+		if err != nil {
+			return err
+		}
+		s.MigEnabled = enabled
+	case "mig-devices":
+		devices := make(types.MigConfig)
+		err := json.Unmarshal(v, &devices)
+		if err != nil {
+			requests := make(types.MigDeviceRequests)
+			err := json.Unmarshal(v, &requests)
 			if err != nil {
-				return err
+				return fmt.Errorf("error parsing '%v' field: %v", k, err)
 			}
-			err = devices.AssertValidFormat()
+			err = requests.AssertValidFormat()
 			if err != nil {
 				return fmt.Errorf("error validating values in '%v' field: %v", k, err)
 			}
-			result.MigDevices = devices
-		default:
-			return fmt.Errorf("unexpected field: %v", k)
+			s.MigDeviceRequests = requests
+			break
+		}
+		err = devices.AssertValidFormat()
+		if err != nil {
+			return fmt.Errorf("error validating values in '%v' field: %v", k, err)
 		}
+		s.MigDevices = devices
+	default:
+		return fmt.Errorf("unexpected field: %v%v", k, suggest.DidYouMean(k, migConfigSpecFields))
 	}
+	return nil
+}
 
-	if result.MigEnabled && result.MigDevices == nil {
-		return fmt.Errorf("missing required field 'mig-devices' when 'mig-enabled' is true")
+// MarshalJSON marshals a 'MigConfigSpec', including its MIG device requests
+// or GPU instances (if any).
+func (s MigConfigSpec) MarshalJSON() ([]byte, error) {
+	type plain MigConfigSpec
+	if s.MigDeviceRequests == nil && s.GpuInstances == nil {
+		return json.Marshal(plain(s))
 	}
+	return json.Marshal(s.output())
+}
 
-	if !result.MigEnabled && len(result.MigDevices) != 0 {
-		return fmt.Errorf("MIG devices included when 'mig-enabled' is false")
+// MarshalYAML marshals a 'MigConfigSpec', including its MIG device requests
+// or GPU instances (if any).
+func (s MigConfigSpec) MarshalYAML() (interface{}, error) {
+	type plain MigConfigSpec
+	if s.MigDeviceRequests == nil && s.GpuInstances == nil {
+		return plain(s), nil
 	}
+	return s.output(), nil
+}
 
-	*s = result
-	return nil
+func (s MigConfigSpec) output() migConfigSpecOutput {
+	return migConfigSpecOutput{
+		NodeSelector: s.NodeSelector,
+		DeviceFilter: s.DeviceFilter,
+		Devices:      s.Devices,
+		MigEnabled:   s.MigEnabled,
+		MigDevices:   s.MigDeviceRequests,
+		GpuInstances: s.GpuInstances,
+	}
 }
 
 func containsKey(m map[string]json.RawMessage, s string) bool {
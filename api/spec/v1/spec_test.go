@@ -26,6 +26,12 @@ import (
 )
 
 func TestMarshallUnmarshall(t *testing.T) {
+	placement := 4
+	sharedGpuInstances := types.GpuInstancesConfig{
+		{Profile: "3g.20gb", Placement: &placement, ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+		{Profile: "2g.10gb"},
+	}
+
 	spec := Spec{
 		Version: "v1",
 		MigConfigs: map[string]MigConfigSpecSlice{
@@ -104,6 +110,26 @@ func TestMarshallUnmarshall(t *testing.T) {
 					},
 				},
 			},
+			"one-3-slice-fill-1-slice": []MigConfigSpec{
+				{
+					Devices:    "all",
+					MigEnabled: true,
+					MigDeviceRequests: types.MigDeviceRequests{
+						"3g.20gb": {Min: 1, Max: 1},
+						"2g.10gb": {Min: 1, Max: types.MigDeviceCountUnbounded},
+						"1g.5gb":  {Min: 0, Max: types.MigDeviceCountUnbounded},
+						"1g.10gb": {Percent: 50},
+					},
+				},
+			},
+			"shared-gpu-instance": []MigConfigSpec{
+				{
+					Devices:      "all",
+					MigEnabled:   true,
+					MigDevices:   sharedGpuInstances.MigConfig(),
+					GpuInstances: sharedGpuInstances,
+				},
+			},
 			"multi-device-filter": []MigConfigSpec{
 				{
 					DeviceFilter: []string{"A100-SXM4-40GB", "A100-PCIE-40GB"},
@@ -338,6 +364,101 @@ func TestMigConfigSpec(t *testing.T) {
 			}`,
 			true,
 		},
+		{
+			"'mig-devices' with fill and range counts",
+			`{
+				"devices": "all",
+				"mig-enabled": true,
+				"mig-devices": {
+					"3g.20gb": 1,
+					"2g.10gb": {"min": 1, "max": 2},
+					"1g.5gb": "fill",
+					"1g.10gb": "50%"
+				}
+			}`,
+			false,
+		},
+		{
+			"'mig-devices' with an invalid count",
+			`{
+				"devices": "all",
+				"mig-enabled": true,
+				"mig-devices": {
+					"1g.5gb": "lots"
+				}
+			}`,
+			true,
+		},
+		{
+			"'mig-devices' with an invalid range",
+			`{
+				"devices": "all",
+				"mig-enabled": true,
+				"mig-devices": {
+					"1g.5gb": {"min": 3, "max": 2}
+				}
+			}`,
+			true,
+		},
+		{
+			"'mig-devices' with fill counts, enabled: false",
+			`{
+				"devices": "all",
+				"mig-enabled": false,
+				"mig-devices": {
+					"1g.5gb": "fill"
+				}
+			}`,
+			true,
+		},
+		{
+			"'gpu-instances' formatted correctly",
+			`{
+				"devices": "all",
+				"mig-enabled": true,
+				"gpu-instances": [
+					{"profile": "3g.20gb", "placement": 4, "compute-instances": ["1c.3g.20gb", "2c.3g.20gb"]},
+					{"profile": "2g.10gb"}
+				]
+			}`,
+			false,
+		},
+		{
+			"'gpu-instances' with an invalid compute instance",
+			`{
+				"devices": "all",
+				"mig-enabled": true,
+				"gpu-instances": [
+					{"profile": "3g.20gb", "compute-instances": ["1c.2g.10gb"]}
+				]
+			}`,
+			true,
+		},
+		{
+			"'gpu-instances' and 'mig-devices'",
+			`{
+				"devices": "all",
+				"mig-enabled": true,
+				"mig-devices": {
+					"1g.5gb": 2
+				},
+				"gpu-instances": [
+					{"profile": "2g.10gb"}
+				]
+			}`,
+			true,
+		},
+		{
+			"'gpu-instances', enabled: false",
+			`{
+				"devices": "all",
+				"mig-enabled": false,
+				"gpu-instances": [
+					{"profile": "2g.10gb"}
+				]
+			}`,
+			true,
+		},
 		{
 			"Erroneous field",
 			`{
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NVIDIA/mig-parted/internal/suggest"
+)
+
+// extendsField is the field of a 'MigConfigSpec' (or of a template) naming
+// the templates it is built from.
+const extendsField = "extends"
+
+// templateFields are the fields allowed in a template.
+var templateFields = append(slices.Clone(migConfigSpecFields), extendsField)
+
+// migDevicesFields are the fields declaring the MIG devices of a 'MigConfigSpec'.
+var migDevicesFields = []string{"mig-devices", "gpu-instances"}
+
+// templates holds the named 'MigConfigSpec' fragments of a spec, as raw fields.
+type templates map[string]map[string]json.RawMessage
+
+// parseTemplates parses the 'templates' field of a spec. Each template is a
+// possibly partial 'MigConfigSpec', which may itself extend other templates.
+func parseTemplates(b json.RawMessage) (templates, error) {
+	result := make(templates)
+	err := json.Unmarshal(b, &result)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(result)) {
+		for k := range result[name] {
+			if !slices.Contains(templateFields, k) {
+				return nil, fmt.Errorf("template '%v': unexpected field: %v%v", name, k, suggest.DidYouMean(k, templateFields))
+			}
+		}
+		if _, err := result.expand(result[name], []string{name}); err != nil {
+			return nil, fmt.Errorf("template '%v': %v", name, err)
+		}
+	}
+	return result, nil
+}
+
+// expandMigConfigs unmarshals the 'mig-configs' field of a spec, expanding
+// every entry that extends one or more templates.
+func expandMigConfigs(b json.RawMessage, t templates) (map[string]MigConfigSpecSlice, error) {
+	raw := make(map[string][]map[string]json.RawMessage)
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]MigConfigSpecSlice)
+	for name, entries := range raw {
+		slice := MigConfigSpecSlice{}
+		for i, entry := range entries {
+			fields, err := t.expand(entry, nil)
+			if err != nil {
+				return nil, fmt.Errorf("'%v'[%d]: %v", name, i, err)
+			}
+			expanded, err := json.Marshal(fields)
+			if err != nil {
+				return nil, err
+			}
+			var spec MigConfigSpec
+			err = json.Unmarshal(expanded, &spec)
+			if err != nil {
+				return nil, err
+			}
+			slice = append(slice, spec)
+		}
+		configs[name] = slice
+	}
+
+	return configs, nil
+}
+
+// expand returns the fields of the templates extended by an entry, in order,
+// overridden by the entry's own fields. The templates being expanded are
+// tracked in chain to detect cycles.
+func (t templates) expand(fields map[string]json.RawMessage, chain []string) (map[string]json.RawMessage, error) {
+	v, exists := fields[extendsField]
+	if !exists {
+		return fields, nil
+	}
+
+	names, err := parseExtends(v)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]json.RawMessage)
+	for _, name := range names {
+		template, exists := t[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown template '%v'", name)
+		}
+		if slices.Contains(chain, name) {
+			return nil, fmt.Errorf("template '%v' extends itself", name)
+		}
+		expanded, err := t.expand(template, append(slices.Clone(chain), name))
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(result, expanded)
+	}
+
+	// The MIG devices of an entry replace those of its templates, whichever
+	// of their two forms each one uses.
+	for _, k := range migDevicesFields {
+		if _, exists := fields[k]; exists {
+			for _, other := range migDevicesFields {
+				delete(result, other)
+			}
+		}
+	}
+
+	maps.Copy(result, fields)
+	delete(result, extendsField)
+	return result, nil
+}
+
+// parseExtends parses the 'extends' field, a template name or a list of them.
+func parseExtends(v json.RawMessage) ([]string, error) {
+	var str string
+	err1 := json.Unmarshal(v, &str)
+	if err1 == nil {
+		return []string{str}, nil
+	}
+	var strslice []string
+	err2 := json.Unmarshal(v, &strslice)
+	if err2 == nil {
+		if len(strslice) == 0 {
+			return nil, fmt.Errorf("at least one entry in '%v' is required", extendsField)
+		}
+		return strslice, nil
+	}
+	return nil, fmt.Errorf("(%v, %v)", err1, err2)
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestTemplates(t *testing.T) {
+	testCases := []struct {
+		description     string
+		spec            string
+		expectedConfigs map[string]MigConfigSpecSlice
+		expectedError   string
+	}{
+		{
+			description: "Extends Templates",
+			spec: `
+version: v1
+templates:
+  a100:
+    device-filter: ["0x20B010DE", "0x20B210DE"]
+    devices: all
+  balanced:
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": 2
+      "2g.10gb": 1
+      "3g.20gb": 1
+mig-configs:
+  a100-balanced:
+  - extends: [a100, balanced]
+  a100-first-balanced:
+  - extends: [a100, balanced]
+    devices: [0]
+  - extends: a100
+    devices: [1]
+    mig-enabled: false
+`,
+			expectedConfigs: map[string]MigConfigSpecSlice{
+				"a100-balanced": {
+					{
+						DeviceFilter: []string{"0x20B010DE", "0x20B210DE"},
+						Devices:      "all",
+						MigEnabled:   true,
+						MigDevices:   types.MigConfig{"1g.5gb": 2, "2g.10gb": 1, "3g.20gb": 1},
+					},
+				},
+				"a100-first-balanced": {
+					{
+						DeviceFilter: []string{"0x20B010DE", "0x20B210DE"},
+						Devices:      []int{0},
+						MigEnabled:   true,
+						MigDevices:   types.MigConfig{"1g.5gb": 2, "2g.10gb": 1, "3g.20gb": 1},
+					},
+					{
+						DeviceFilter: []string{"0x20B010DE", "0x20B210DE"},
+						Devices:      []int{1},
+						MigEnabled:   false,
+					},
+				},
+			},
+		},
+		{
+			description: "Nested Templates",
+			spec: `
+version: v1
+templates:
+  a100:
+    devices: all
+  balanced:
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": 7
+  a100-balanced:
+    extends: [a100, balanced]
+    device-filter: "0x20B010DE"
+mig-configs:
+  a100-balanced:
+  - extends: a100-balanced
+`,
+			expectedConfigs: map[string]MigConfigSpecSlice{
+				"a100-balanced": {
+					{
+						DeviceFilter: "0x20B010DE",
+						Devices:      "all",
+						MigEnabled:   true,
+						MigDevices:   types.MigConfig{"1g.5gb": 7},
+					},
+				},
+			},
+		},
+		{
+			description: "GPU Instances Override MIG Devices",
+			spec: `
+version: v1
+templates:
+  balanced:
+    devices: all
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": 7
+mig-configs:
+  shared:
+  - extends: balanced
+    gpu-instances:
+    - profile: 7g.40gb
+      compute-instances: [3c.7g.40gb, 4c.7g.40gb]
+`,
+			expectedConfigs: map[string]MigConfigSpecSlice{
+				"shared": {
+					{
+						Devices:    "all",
+						MigEnabled: true,
+						MigDevices: types.MigConfig{"3c.7g.40gb": 1, "4c.7g.40gb": 1},
+						GpuInstances: types.GpuInstancesConfig{
+							{Profile: "7g.40gb", ComputeInstances: []string{"3c.7g.40gb", "4c.7g.40gb"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "Unknown Template",
+			spec: `
+version: v1
+mig-configs:
+  a100-balanced:
+  - extends: a100
+`,
+			expectedError: "'a100-balanced'[0]: unknown template 'a100'",
+		},
+		{
+			description: "Template Cycle",
+			spec: `
+version: v1
+templates:
+  loop-a:
+    extends: loop-b
+  loop-b:
+    extends: loop-a
+`,
+			expectedError: "template 'loop-a': template 'loop-a' extends itself",
+		},
+		{
+			description: "Erroneous Template Field",
+			spec: `
+version: v1
+templates:
+  a100:
+    bogus: field
+`,
+			expectedError: "template 'a100': unexpected field: bogus",
+		},
+		{
+			description: "Incomplete Expansion",
+			spec: `
+version: v1
+templates:
+  a100:
+    devices: all
+mig-configs:
+  a100-balanced:
+  - extends: a100
+`,
+			expectedError: "missing required field: mig-enabled",
+		},
+		{
+			description: "Invalid Expansion",
+			spec: `
+version: v1
+templates:
+  balanced:
+    devices: all
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": 7
+mig-configs:
+  all-disabled:
+  - extends: balanced
+    mig-enabled: false
+`,
+			expectedError: "MIG devices included when 'mig-enabled' is false",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			s := Spec{}
+			err := yaml.Unmarshal([]byte(tc.spec), &s)
+			if tc.expectedError != "" {
+				require.NotNil(t, err, "Unexpected success yaml.Unmarshal")
+				require.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.Nil(t, err, "Unexpected failure yaml.Unmarshal")
+			require.Equal(t, tc.expectedConfigs, s.MigConfigs)
+		})
+	}
+}
@@ -0,0 +1,426 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/internal/suggest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// gpuInstanceFields are the fields of an entry of 'gpu-instances'.
+var gpuInstanceFields = []string{"profile", "placement", "compute-instances"}
+
+// ValidationError is an error found in a config file, located by its file,
+// line and column and, within 'mig-configs', by the label of its MIG config
+// and the index of its entry.
+type ValidationError struct {
+	File   string
+	Line   int
+	Column int
+	// Config is the label of the MIG config of the error, if any.
+	Config string
+	// Entry is the index of the entry of the MIG config of the error, or -1.
+	Entry int
+	Err   error
+}
+
+// ValidationErrors holds all of the errors found in a config file.
+type ValidationErrors []*ValidationError
+
+func (e *ValidationError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	}
+	switch {
+	case e.Config != "" && e.Entry >= 0:
+		return fmt.Sprintf("%s: '%v'[%d]: %v", location, e.Config, e.Entry, e.Err)
+	case e.Config != "":
+		return fmt.Sprintf("%s: '%v': %v", location, e.Config, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", location, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (e ValidationErrors) Error() string {
+	var lines []string
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks a config file read from file, walking its YAML (or JSON)
+// document to report every error found rather than only the first one, each
+// located by line and column. Unknown fields come with the closest known
+// field as a suggestion.
+func Validate(data []byte, file string) ValidationErrors {
+	v := &validator{file: file, templates: make(templates)}
+
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		v.add(nil, "", -1, err)
+		return v.errs
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := resolveAlias(doc.Content[0])
+	if root.Kind != yamlv3.MappingNode {
+		v.add(root, "", -1, fmt.Errorf("expected a mapping of fields"))
+		return v.errs
+	}
+
+	var version, migConfigs *yamlv3.Node
+	v.walkMapping(root, func(key, value *yamlv3.Node) {
+		switch key.Value {
+		case "version":
+			version = value
+			if value.Value != Version {
+				v.add(value, "", -1, fmt.Errorf("unknown version: %v", value.Value))
+			}
+		case "include":
+			var include []string
+			if err := value.Decode(&include); err != nil {
+				v.add(value, "", -1, fmt.Errorf("error parsing 'include' field: expected a list of file globs"))
+			}
+		case "templates":
+			v.validateTemplates(value)
+		case "mig-configs":
+			migConfigs = value
+		default:
+			v.add(key, "", -1, fmt.Errorf("unexpected field: %v%v", key.Value, suggest.DidYouMean(key.Value, specFields)))
+		}
+	})
+	if version == nil && len(root.Content) > 0 {
+		v.add(root, "", -1, fmt.Errorf("unable to parse with missing 'version' field"))
+	}
+	if migConfigs != nil {
+		v.validateMigConfigs(migConfigs)
+	}
+
+	// Report anything the walk above does not check for as the spec itself
+	// does, so that a spec passing validation always parses.
+	if len(v.errs) == 0 {
+		var spec Spec
+		if err := yaml.Unmarshal(data, &spec); err != nil {
+			v.add(root, "", -1, err)
+		}
+	}
+
+	slices.SortStableFunc(v.errs, func(a, b *ValidationError) int {
+		return a.Line - b.Line
+	})
+	return v.errs
+}
+
+type validator struct {
+	file string
+	errs ValidationErrors
+	// templates are the templates of the spec, or nil if they are invalid.
+	templates templates
+}
+
+func (v *validator) add(node *yamlv3.Node, config string, entry int, err error) {
+	e := &ValidationError{File: v.file, Config: config, Entry: entry, Err: err}
+	if node != nil {
+		e.Line, e.Column = node.Line, node.Column
+	}
+	v.errs = append(v.errs, e)
+}
+
+// walkMapping calls f with each key and value of a mapping, skipping YAML
+// merge keys ('<<'), whose fields are those of the mapping merged in.
+func (v *validator) walkMapping(node *yamlv3.Node, f func(key, value *yamlv3.Node)) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], resolveAlias(node.Content[i+1])
+		if key.Tag == "!!merge" {
+			if value.Kind == yamlv3.MappingNode {
+				v.walkMapping(value, f)
+			}
+			continue
+		}
+		f(key, value)
+	}
+}
+
+func (v *validator) validateTemplates(node *yamlv3.Node) {
+	v.templates = nil
+	if node.Kind != yamlv3.MappingNode {
+		v.add(node, "", -1, fmt.Errorf("error parsing 'templates' field: expected a mapping of template names to templates"))
+		return
+	}
+
+	before := len(v.errs)
+	v.walkMapping(node, func(key, template *yamlv3.Node) {
+		name := key.Value
+		if template.Kind != yamlv3.MappingNode {
+			v.add(template, "", -1, fmt.Errorf("template '%v': expected a mapping of fields", name))
+			return
+		}
+		v.walkMapping(template, func(key, value *yamlv3.Node) {
+			if !slices.Contains(templateFields, key.Value) {
+				v.add(key, "", -1, fmt.Errorf("template '%v': unexpected field: %v%v", name, key.Value, suggest.DidYouMean(key.Value, templateFields)))
+				return
+			}
+			for _, err := range v.validateField(key, value) {
+				v.add(err.node, "", -1, fmt.Errorf("template '%v': %v", name, err.err))
+			}
+		})
+	})
+	if len(v.errs) > before {
+		return
+	}
+
+	raw, err := nodeJSON(node)
+	if err == nil {
+		v.templates, err = parseTemplates(raw)
+	}
+	if err != nil {
+		v.add(node, "", -1, err)
+	}
+}
+
+func (v *validator) validateMigConfigs(node *yamlv3.Node) {
+	if node.Kind != yamlv3.MappingNode || len(node.Content) == 0 {
+		v.add(node, "", -1, fmt.Errorf("at least one entry in 'mig-configs' is required"))
+		return
+	}
+
+	v.walkMapping(node, func(key, entries *yamlv3.Node) {
+		config := key.Value
+		if entries.Kind != yamlv3.SequenceNode || len(entries.Content) == 0 {
+			v.add(key, config, -1, fmt.Errorf("at least one entry in '%v' is required", config))
+			return
+		}
+		for i, entry := range entries.Content {
+			v.validateMigConfigSpec(resolveAlias(entry), config, i)
+		}
+	})
+}
+
+// validateMigConfigSpec validates each field of an entry of a MIG config on
+// its own, then the entry as a whole (with the templates it extends).
+func (v *validator) validateMigConfigSpec(node *yamlv3.Node, config string, entry int) {
+	if node.Kind != yamlv3.MappingNode {
+		v.add(node, config, entry, fmt.Errorf("expected a mapping of fields"))
+		return
+	}
+
+	before := len(v.errs)
+	extends := false
+	v.walkMapping(node, func(key, value *yamlv3.Node) {
+		if !slices.Contains(templateFields, key.Value) {
+			v.add(key, config, entry, fmt.Errorf("unexpected field: %v%v", key.Value, suggest.DidYouMean(key.Value, templateFields)))
+			return
+		}
+		extends = extends || key.Value == extendsField
+		for _, err := range v.validateField(key, value) {
+			v.add(err.node, config, entry, err.err)
+		}
+	})
+	if len(v.errs) > before || (extends && v.templates == nil) {
+		return
+	}
+
+	raw, err := nodeJSON(node)
+	if err != nil {
+		v.add(node, config, entry, err)
+		return
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		v.add(node, config, entry, err)
+		return
+	}
+	fields, err = v.templates.expand(fields, nil)
+	if err != nil {
+		v.add(node, config, entry, err)
+		return
+	}
+	expanded, err := json.Marshal(fields)
+	if err == nil {
+		var spec MigConfigSpec
+		err = json.Unmarshal(expanded, &spec)
+	}
+	if err != nil {
+		v.add(node, config, entry, err)
+	}
+}
+
+// fieldError is an error in the value of a field, located at a node.
+type fieldError struct {
+	node *yamlv3.Node
+	err  error
+}
+
+// validateField checks the value of a field of a 'MigConfigSpec' (or a
+// template). The elements of 'mig-devices' and 'gpu-instances' are checked
+// one at a time so that each error points at the element it is about.
+func (v *validator) validateField(key, value *yamlv3.Node) []fieldError {
+	var errs []fieldError
+	switch {
+	case key.Value == extendsField:
+		raw, err := nodeJSON(value)
+		if err == nil {
+			_, err = parseExtends(raw)
+		}
+		if err != nil {
+			return []fieldError{{value, fmt.Errorf("error parsing '%v' field: %v", key.Value, err)}}
+		}
+		if v.templates == nil {
+			return nil
+		}
+		names := []*yamlv3.Node{value}
+		if value.Kind == yamlv3.SequenceNode {
+			names = value.Content
+		}
+		for _, name := range names {
+			if _, exists := v.templates[name.Value]; !exists {
+				errs = append(errs, fieldError{name, fmt.Errorf("unknown template '%v'%v", name.Value, suggest.DidYouMean(name.Value, slices.Sorted(maps.Keys(v.templates))))})
+			}
+		}
+		return errs
+	case key.Value == "node-selector" && value.Kind == yamlv3.MappingNode:
+		v.walkMapping(value, func(k, _ *yamlv3.Node) {
+			if !slices.Contains(nodeSelectorFields, k.Value) {
+				errs = append(errs, fieldError{k, fmt.Errorf("unexpected field in '%v': %v%v", key.Value, k.Value, suggest.DidYouMean(k.Value, nodeSelectorFields))})
+			}
+		})
+	case key.Value == "device-filter":
+		errs = v.validateDeviceFilter(value)
+	case key.Value == "devices" && value.Kind == yamlv3.SequenceNode:
+		for i, node := range value.Content {
+			var index int
+			if err := resolveAlias(node).Decode(&index); err != nil {
+				raw, _ := nodeJSON(node)
+				errs = append(errs, fieldError{node, fmt.Errorf("invalid GPU index in '%v'[%d]: %s (%v)", key.Value, i, raw, expectedDevices)})
+			}
+		}
+	case key.Value == "mig-devices" && value.Kind == yamlv3.MappingNode:
+		v.walkMapping(value, func(profile, count *yamlv3.Node) {
+			if err := types.AssertValidMigProfileFormat(profile.Value); err != nil {
+				errs = append(errs, fieldError{profile, fmt.Errorf("invalid format for '%v': %v", profile.Value, err)})
+				return
+			}
+			var c types.MigDeviceCount
+			raw, err := nodeJSON(count)
+			if err == nil {
+				err = json.Unmarshal(raw, &c)
+			}
+			if err == nil {
+				err = c.AssertValidFormat()
+			}
+			if err != nil {
+				errs = append(errs, fieldError{count, fmt.Errorf("invalid count for '%v': %v", profile.Value, err)})
+			}
+		})
+	case key.Value == "gpu-instances" && value.Kind == yamlv3.SequenceNode:
+		for i, node := range value.Content {
+			node = resolveAlias(node)
+			if node.Kind == yamlv3.MappingNode {
+				unexpected := false
+				v.walkMapping(node, func(k, _ *yamlv3.Node) {
+					if !slices.Contains(gpuInstanceFields, k.Value) {
+						unexpected = true
+						errs = append(errs, fieldError{k, fmt.Errorf("GPU instance %d: unexpected field: %v%v", i, k.Value, suggest.DidYouMean(k.Value, gpuInstanceFields))})
+					}
+				})
+				if unexpected {
+					continue
+				}
+			}
+			var gi types.GpuInstanceConfig
+			raw, err := nodeJSON(node)
+			if err == nil {
+				err = json.Unmarshal(raw, &gi)
+			}
+			if err == nil {
+				err = gi.AssertValidFormat()
+			}
+			if err != nil {
+				errs = append(errs, fieldError{node, fmt.Errorf("GPU instance %d: %v", i, err)})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	raw, err := nodeJSON(value)
+	if err == nil {
+		var spec MigConfigSpec
+		err = spec.setField(key.Value, raw)
+	}
+	if err != nil {
+		return []fieldError{{value, err}}
+	}
+	return nil
+}
+
+// validateDeviceFilter checks the fields of the expressions of a device filter,
+// including those of the lists and 'not' fields it holds.
+func (v *validator) validateDeviceFilter(node *yamlv3.Node) []fieldError {
+	var errs []fieldError
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yamlv3.SequenceNode:
+		for _, element := range node.Content {
+			errs = append(errs, v.validateDeviceFilter(element)...)
+		}
+	case yamlv3.MappingNode:
+		v.walkMapping(node, func(k, value *yamlv3.Node) {
+			if !slices.Contains(deviceFilterFields, k.Value) {
+				errs = append(errs, fieldError{k, fmt.Errorf("unexpected field in 'device-filter': %v%v", k.Value, suggest.DidYouMean(k.Value, deviceFilterFields))})
+				return
+			}
+			if k.Value == "not" {
+				errs = append(errs, v.validateDeviceFilter(value)...)
+			}
+		})
+	}
+	return errs
+}
+
+// nodeJSON converts a YAML node into JSON, as 'sigs.k8s.io/yaml' does for a
+// whole document.
+func nodeJSON(node *yamlv3.Node) (json.RawMessage, error) {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func resolveAlias(node *yamlv3.Node) *yamlv3.Node {
+	for node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		description    string
+		spec           string
+		expectedErrors []string
+	}{
+		{
+			description: "Valid",
+			spec: `
+version: v1
+templates:
+  a100:
+    device-filter: "0x20B010DE"
+    devices: all
+mig-configs:
+  all-1g:
+  - extends: a100
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": 7
+  shared:
+  - devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.20gb
+      compute-instances: [1c.3g.20gb, 2c.3g.20gb]
+`,
+		},
+		{
+			description: "Empty",
+			spec:        "",
+		},
+		{
+			description: "Every error is reported",
+			spec: `
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enable: true
+    mig-devices:
+      "1g.5gb": 7
+  mixed:
+  - devices: [0, 1]
+    mig-enabled: true
+    mig-devices:
+      "1g.5gb": lots
+      "3x.20gb": 1
+  - devices: bogus
+    mig-enabled: false
+`,
+			expectedErrors: []string{
+				"test.yaml:6:5: 'all-1g'[0]: unexpected field: mig-enable (did you mean 'mig-enabled'?)",
+				"test.yaml:13:17: 'mixed'[0]: invalid count for '1g.5gb': invalid count: lots",
+				"test.yaml:14:7: 'mixed'[0]: invalid format for '3x.20gb'",
+				"test.yaml:15:14: 'mixed'[1]: invalid string input for 'devices': bogus (expected 'all' or a list of GPU indices)",
+			},
+		},
+		{
+			description: "Invalid GPU index",
+			spec: `
+version: v1
+mig-configs:
+  all-disabled:
+  - devices: [0, x]
+    mig-enabled: false
+`,
+			expectedErrors: []string{
+				"test.yaml:5:18: 'all-disabled'[0]: invalid GPU index in 'devices'[1]: \"x\" (expected 'all' or a list of GPU indices)",
+			},
+		},
+		{
+			description: "Device filter errors",
+			spec: `
+version: v1
+mig-configs:
+  filtered:
+  - device-filter:
+    - "0x20B010DE"
+    - {name: "H100*", memory: 80}
+    - not: {arch: hopper}
+    devices: all
+    mig-enabled: false
+`,
+			expectedErrors: []string{
+				"test.yaml:7:23: 'filtered'[0]: unexpected field in 'device-filter': memory (did you mean 'memory-gb'?)",
+				"test.yaml:8:13: 'filtered'[0]: unexpected field in 'device-filter': arch",
+			},
+		},
+		{
+			description: "Unknown top-level field",
+			spec: `
+version: v1
+mig-config:
+  all-1g: []
+`,
+			expectedErrors: []string{
+				"test.yaml:3:1: unexpected field: mig-config (did you mean 'mig-configs'?)",
+			},
+		},
+		{
+			description: "Unknown version",
+			spec: `
+version: v2
+`,
+			expectedErrors: []string{
+				"test.yaml:2:10: unknown version: v2",
+			},
+		},
+		{
+			description: "Template errors",
+			spec: `
+version: v1
+templates:
+  a100:
+    devics: all
+mig-configs:
+  all-1g:
+  - extends: a100
+    mig-enabled: false
+`,
+			expectedErrors: []string{
+				"test.yaml:5:5: template 'a100': unexpected field: devics (did you mean 'devices'?)",
+			},
+		},
+		{
+			description: "Unknown template",
+			spec: `
+version: v1
+templates:
+  a100:
+    devices: all
+mig-configs:
+  all-1g:
+  - extends: [a10]
+    mig-enabled: false
+`,
+			expectedErrors: []string{
+				"test.yaml:8:15: 'all-1g'[0]: unknown template 'a10' (did you mean 'a100'?)",
+			},
+		},
+		{
+			description: "GPU instance errors",
+			spec: `
+version: v1
+mig-configs:
+  shared:
+  - devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.20gb
+      placment: 4
+    - profile: 2g.10gb
+      compute-instances: [1c.3g.20gb]
+`,
+			expectedErrors: []string{
+				"test.yaml:9:7: 'shared'[0]: GPU instance 0: unexpected field: placment (did you mean 'placement'?)",
+				"test.yaml:10:7: 'shared'[0]: GPU instance 1: invalid compute instance profile '1c.3g.20gb'",
+			},
+		},
+		{
+			description: "Entry errors",
+			spec: `
+version: v1
+mig-configs:
+  disabled:
+  - devices: all
+    mig-enabled: false
+    mig-devices: {"1g.5gb": 1}
+  missing:
+  - mig-enabled: false
+`,
+			expectedErrors: []string{
+				"test.yaml:5:5: 'disabled'[0]: MIG devices included when 'mig-enabled' is false",
+				"test.yaml:9:5: 'missing'[0]: missing required field: devices",
+			},
+		},
+		{
+			description: "Node selector errors",
+			spec: `
+version: v1
+mig-configs:
+  selected:
+  - node-selector: {hostnme: "gpu-*"}
+    devices: all
+    mig-enabled: false
+`,
+			expectedErrors: []string{
+				"test.yaml:5:21: 'selected'[0]: unexpected field in 'node-selector': hostnme (did you mean 'hostname'?)",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			errs := Validate([]byte(tc.spec), "test.yaml")
+			require.Len(t, errs, len(tc.expectedErrors), "%v", errs)
+			for i, expected := range tc.expectedErrors {
+				require.Contains(t, errs[i].Error(), expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfigValidationErrors(t *testing.T) {
+	spec := `
+version: v1
+mig-configs:
+  all-1g:
+  - devices: all
+    mig-enable: true
+  - devices: bogus
+    mig-enabled: false
+`
+	_, err := LoadConfig([]byte(spec), "<stdin>", ".")
+	require.Error(t, err)
+
+	var validationErrors ValidationErrors
+	require.True(t, errors.As(err, &validationErrors))
+	require.Len(t, validationErrors, 2)
+	require.Equal(t, 6, validationErrors[0].Line)
+	require.Equal(t, "all-1g", validationErrors[0].Config)
+	require.Equal(t, 0, validationErrors[0].Entry)
+	require.Equal(t, 7, validationErrors[1].Line)
+	require.Equal(t, 1, validationErrors[1].Entry)
+}
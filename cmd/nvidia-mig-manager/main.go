@@ -38,6 +38,8 @@ import (
 
 	"sigs.k8s.io/yaml"
 
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
 	"github.com/NVIDIA/mig-parted/internal/info"
 	"github.com/NVIDIA/mig-parted/pkg/mig/builder"
 	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
@@ -64,6 +66,9 @@ var (
 	kubeconfigFlag                 string
 	nodeNameFlag                   string
 	configFileFlag                 string
+	userConfigFilesFlag            []string
+	configPrecedenceFlag           string
+	quirksFileFlag                 string
 	reconfigureScriptFlag          string
 	withRebootFlag                 bool
 	withShutdownHostGPUClientsFlag bool
@@ -73,6 +78,7 @@ var (
 	hostMigManagerStateFileFlag    string
 	hostKubeletSystemdServiceFlag  string
 	defaultGPUClientsNamespaceFlag string
+	backendFlag                    string
 
 	cdiEnabledFlag    bool
 	driverRoot        string
@@ -149,6 +155,25 @@ func main() {
 			Destination: &configFileFlag,
 			Sources:     cli.EnvVars("CONFIG_FILE"),
 		},
+		&cli.StringSliceFlag{
+			Name:        "user-config-file",
+			Usage:       "the path to a file with MIG configs to add to those generated from the hardware (may be repeated, later files take precedence)",
+			Destination: &userConfigFilesFlag,
+			Sources:     cli.EnvVars("USER_CONFIG_FILES"),
+		},
+		&cli.StringFlag{
+			Name:        "config-precedence",
+			Value:       string(builder.PrecedenceUser),
+			Usage:       "which MIG config to keep when a user-defined and a generated one have the same name [user | generated]",
+			Destination: &configPrecedenceFlag,
+			Sources:     cli.EnvVars("CONFIG_PRECEDENCE"),
+		},
+		&cli.StringFlag{
+			Name:        "quirks-file",
+			Usage:       "the path to a file with quirks correcting the MIG profiles discovered on some GPUs, in addition to the built-in ones",
+			Destination: &quirksFileFlag,
+			Sources:     cli.EnvVars("QUIRKS_FILE"),
+		},
 		&cli.StringFlag{
 			Name:        "reconfigure-script",
 			Aliases:     []string{"s"},
@@ -259,6 +284,13 @@ func main() {
 			Destination: &devRootCtrPath,
 			Sources:     cli.EnvVars("DEV_ROOT_CTR_PATH"),
 		},
+		&cli.StringFlag{
+			Name:        "backend",
+			Value:       util.NvmlBackend,
+			Usage:       "the GPUs to operate on: 'nvml' for the GPUs of this node, or 'sim:<file>' for a simulated node described in <file>",
+			Destination: &backendFlag,
+			Sources:     cli.EnvVars(util.BackendEnvVar),
+		},
 		&cli.StringFlag{
 			Name:        "nvidia-cdi-hook-path",
 			Value:       DefaultNvidiaCDIHookPath,
@@ -280,6 +312,40 @@ func validateFlags(ctx context.Context, c *cli.Command) (context.Context, error)
 	if nodeNameFlag == "" {
 		return ctx, fmt.Errorf("invalid -n <node-name> flag: must not be empty string")
 	}
+	if err := util.SetBackend(backendFlag); err != nil {
+		return ctx, err
+	}
+	if configFileFlag != "" && len(userConfigFilesFlag) > 0 {
+		return ctx, fmt.Errorf("invalid --user-config-file flag: user configs are only added to generated configs, not to --config-file")
+	}
+	if _, err := builder.ParsePrecedence(configPrecedenceFlag); err != nil {
+		return ctx, fmt.Errorf("invalid --config-precedence flag: %w", err)
+	}
+	if quirksFileFlag != "" {
+		if configFileFlag != "" {
+			return ctx, fmt.Errorf("invalid --quirks-file flag: quirks only apply to generated configs, not to --config-file")
+		}
+		if err := discovery.LoadQuirksFile(quirksFileFlag); err != nil {
+			return ctx, fmt.Errorf("invalid --quirks-file flag: %w", err)
+		}
+	}
+	if util.IsSimulated() && withShutdownHostGPUClientsFlag {
+		return ctx, fmt.Errorf("invalid --with-shutdown-host-gpu-clients flag: not supported with a simulated backend")
+	}
+	// The nvidia-mig-parted commands run during a reconfiguration must operate on the same GPUs.
+	if err := os.Setenv(util.BackendEnvVar, backendFlag); err != nil {
+		return ctx, fmt.Errorf("error setting %v: %w", util.BackendEnvVar, err)
+	}
+	// They must also match node selectors against the node rather than this container.
+	if err := os.Setenv(util.NodeNameEnvVar, nodeNameFlag); err != nil {
+		return ctx, fmt.Errorf("error setting %v: %w", util.NodeNameEnvVar, err)
+	}
+	// Unless they run chrooted into the host root, where its os-release already is.
+	if !withShutdownHostGPUClientsFlag {
+		if err := os.Setenv(util.HostRootEnvVar, hostRootMountFlag); err != nil {
+			return ctx, fmt.Errorf("error setting %v: %w", util.HostRootEnvVar, err)
+		}
+	}
 	return ctx, nil
 }
 
@@ -342,15 +408,36 @@ func createNodeConfigMap(ctx context.Context, clientset *kubernetes.Clientset, n
 	return nil
 }
 
+// writeGeneratedConfig generates the MIG configs of the GPU hardware, overlaid
+// with those of the user config files, and writes them to DefaultGeneratedConfigFile.
 func writeGeneratedConfig() ([]byte, error) {
-	configYAML, err := builder.GenerateConfigYAML()
+	var opts []builder.Option
+	header := "# DO NOT EDIT: Auto-generated MIG configuration from GPU hardware.\n" +
+		"# Generated by \"nvidia-mig-manager\".\n"
+	if len(userConfigFilesFlag) > 0 {
+		precedence, err := builder.ParsePrecedence(configPrecedenceFlag)
+		if err != nil {
+			return nil, err
+		}
+		var userConfigs []*builder.UserConfigs
+		for _, file := range userConfigFilesFlag {
+			uc, err := builder.ParseUserConfigsFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing user config file %s: %w", file, err)
+			}
+			userConfigs = append(userConfigs, uc)
+		}
+		opts = append(opts, builder.WithUserConfigs(precedence, userConfigs...))
+		header += fmt.Sprintf("# Includes the MIG configs of %s (precedence: %s).\n",
+			strings.Join(userConfigFilesFlag, ", "), precedence)
+	}
+
+	configYAML, err := builder.GenerateConfigYAML(opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	header := []byte("# DO NOT EDIT: Auto-generated MIG configuration from GPU hardware.\n" +
-		"# Generated by \"nvidia-mig-manager\".\n")
-	configYAML = append(header, configYAML...)
+	configYAML = append([]byte(header), configYAML...)
 
 	if err := os.MkdirAll(filepath.Dir(DefaultGeneratedConfigFile), 0755); err != nil {
 		return nil, err
@@ -363,7 +450,8 @@ func writeGeneratedConfig() ([]byte, error) {
 	return configYAML, nil
 }
 
-// setupMigConfig uses custom config if provided, otherwise generates MIG config from hardware.
+// setupMigConfig uses custom config if provided, otherwise generates MIG config from hardware
+// (along with the user configs provided).
 // If dynamic generation fails with ErrNoProfilesDiscovered, falls back to DEFAULT_CONFIG_FILE.
 func setupMigConfig(ctx context.Context, clientset *kubernetes.Clientset) (string, error) {
 	// Use custom config if provided
@@ -386,6 +474,9 @@ func setupMigConfig(ctx context.Context, clientset *kubernetes.Clientset) (strin
 				var statErr error
 				if _, statErr = os.Stat(defaultPath); statErr == nil { //nolint:gosec // path from trusted env var set by operator
 					log.Infof("Using default config: %s", defaultPath)
+					if len(userConfigFilesFlag) > 0 {
+						log.Warnf("Ignoring user config files with the default config: %s", strings.Join(userConfigFilesFlag, ", "))
+					}
 					return defaultPath, nil
 				}
 				log.Warnf("Default config file %s not found: %v", defaultPath, statErr)
@@ -522,6 +613,7 @@ func migReconfigure(ctx context.Context, migConfigValue string, clientset *kuber
 		DefaultGPUClientsNamespace: defaultGPUClientsNamespaceFlag,
 		WithReboot:                 withRebootFlag,
 		WithShutdownHostGPUClients: withShutdownHostGPUClientsFlag,
+		Simulated:                  util.IsSimulated(),
 	}
 
 	if cdiEnabledFlag {
@@ -601,10 +693,10 @@ func copyMigPartedToHost(hostRootMount, hostNvidiaDir, migConfigFile string) (st
 		return "", fmt.Errorf("failed to copy nvidia-mig-parted: %w", err)
 	}
 
-	// Copy the mig config file
+	// Write the mig config file
 	configDst := filepath.Join(dir, "config.yaml")
-	if err := copyFile(migConfigFile, configDst); err != nil {
-		return "", fmt.Errorf("failed to copy config file: %w", err)
+	if err := writeMergedConfigFile(migConfigFile, configDst); err != nil {
+		return "", fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	hostMigPartedBinaryPath := filepath.Join(hostNvidiaDir, "mig-manager", "nvidia-mig-parted")
@@ -613,6 +705,23 @@ func copyMigPartedToHost(hostRootMount, hostNvidiaDir, migConfigFile string) (st
 	return hostMigPartedBinary, nil
 }
 
+// writeMergedConfigFile writes the MIG configs of "src" to the single file "dst".
+// The configs of a config directory, and of the files included by a spec, are
+// merged so that they can be read on the host without the files they came from.
+func writeMergedConfigFile(src, dst string) error {
+	spec, err := migspec.LoadConfigFile(src)
+	if err != nil {
+		return err
+	}
+
+	configYAML, err := yaml.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, configYAML, 0600)
+}
+
 // copyFile is a helper method to perform a copy of file located at the source "src" over to the destination "dst"
 func copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
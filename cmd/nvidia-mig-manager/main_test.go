@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+)
+
+func TestWriteMergedConfigFile(t *testing.T) {
+	configDir := t.TempDir()
+	files := map[string]string{
+		"conf.d/10-team-a.yaml": "version: v1\ninclude: [../shared/*.yaml]\nmig-configs:\n  team-a:\n  - devices: all\n    mig-enabled: false\n",
+		"conf.d/20-team-b.yaml": "version: v1\nmig-configs:\n  team-b:\n  - devices: [0]\n    mig-enabled: true\n    mig-devices:\n      1g.5gb: 7\n",
+		"shared/common.yaml":    "version: v1\nmig-configs:\n  all-disabled:\n  - devices: all\n    mig-enabled: false\n",
+		"single.yaml":           "version: v1\nmig-configs:\n  single:\n  - devices: all\n    mig-enabled: false\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(configDir, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.Nil(t, os.WriteFile(path, []byte(contents), 0600))
+	}
+
+	testCases := []struct {
+		description string
+		src         string
+		expected    []string
+	}{
+		{
+			description: "Single file",
+			src:         "single.yaml",
+			expected:    []string{"single"},
+		},
+		{
+			description: "Directory with included files",
+			src:         "conf.d",
+			expected:    []string{"all-disabled", "team-a", "team-b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			dst := filepath.Join(t.TempDir(), "config.yaml")
+			err := writeMergedConfigFile(filepath.Join(configDir, tc.src), dst)
+			require.Nil(t, err, "Unexpected failure from writeMergedConfigFile")
+
+			// The file written must be readable without the files it was merged from.
+			spec, err := migspec.LoadConfigFile(dst)
+			require.Nil(t, err, "Unexpected failure from LoadConfigFile")
+			require.Empty(t, spec.Include)
+			require.Equal(t, tc.expected, slices.Sorted(maps.Keys(spec.MigConfigs)))
+
+			expected, err := migspec.LoadConfigFile(filepath.Join(configDir, tc.src))
+			require.Nil(t, err, "Unexpected failure from LoadConfigFile")
+			require.Equal(t, expected.MigConfigs, spec.MigConfigs)
+		})
+	}
+
+	err := writeMergedConfigFile(filepath.Join(configDir, "missing.yaml"), filepath.Join(t.TempDir(), "config.yaml"))
+	require.Error(t, err)
+}
@@ -0,0 +1,355 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	hooks "github.com/NVIDIA/mig-parted/api/hooks/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
+	"github.com/NVIDIA/mig-parted/internal/systemd"
+	"github.com/NVIDIA/mig-parted/pkg/mig/config"
+	"github.com/NVIDIA/mig-parted/pkg/mig/mode"
+)
+
+const (
+	waitNoGPUProcessesInterval = 1 * time.Second
+)
+
+// HookActions implements the built-in hook types for the 'apply' subcommand.
+// It tracks the systemd services stopped by 'systemd-stop' hooks so that
+// 'systemd-start' hooks only restart the services that were actually stopped.
+type HookActions struct {
+	ctx             context.Context
+	nvml            nvml.Interface
+	selectedConfig  string
+	systemdManager  *systemd.Manager
+	stoppedServices []string
+	applyContext    *hooks.ApplyContext
+	initialGPUCount int
+}
+
+var _ hooks.Actions = (*HookActions)(nil)
+
+// NewHookActions creates a new HookActions instance.
+// The connection to systemd is only established the first time it is needed.
+func NewHookActions(ctx context.Context, nvmlLib nvml.Interface, selectedConfig string) *HookActions {
+	return &HookActions{
+		ctx:            ctx,
+		nvml:           nvmlLib,
+		selectedConfig: selectedConfig,
+	}
+}
+
+// Close releases the connection to systemd (if one was established).
+func (a *HookActions) Close() error {
+	if a.systemdManager == nil {
+		return nil
+	}
+	return a.systemdManager.Close()
+}
+
+// StopSystemdServices stops the set of services provided and records which of them should be restarted later.
+func (a *HookActions) StopSystemdServices(services []string) error {
+	manager, err := a.getSystemdManager()
+	if err != nil {
+		return err
+	}
+
+	stopped, err := manager.StopSystemdServices(services)
+	// Services are returned in LIFO order, so prepend them to any services stopped previously.
+	for _, service := range slices.Backward(stopped) {
+		if !slices.Contains(a.stoppedServices, service) {
+			a.stoppedServices = slices.Insert(a.stoppedServices, 0, service)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error stopping systemd services: %w", err)
+	}
+
+	return nil
+}
+
+// StartSystemdServices restarts the services previously stopped by StopSystemdServices.
+// If 'services' is non-empty, only those services from the list that were previously stopped are started.
+// If 'noBlock' is set, start jobs are enqueued without waiting for them to complete.
+func (a *HookActions) StartSystemdServices(services []string, noBlock bool) error {
+	var start []string
+	if len(services) == 0 {
+		start = slices.Clone(a.stoppedServices)
+	}
+	for _, service := range services {
+		if !slices.Contains(a.stoppedServices, service) {
+			log.Debugf("Skipping %s (not stopped by a previous hook)", service)
+			continue
+		}
+		start = append(start, service)
+	}
+
+	if len(start) == 0 {
+		return nil
+	}
+
+	manager, err := a.getSystemdManager()
+	if err != nil {
+		return err
+	}
+
+	a.stoppedServices = slices.DeleteFunc(a.stoppedServices, func(s string) bool {
+		return slices.Contains(start, s)
+	})
+
+	if noBlock {
+		err = manager.EnqueueStartSystemdServices(start)
+	} else {
+		err = manager.StartSystemdServices(start)
+	}
+	if err != nil {
+		return fmt.Errorf("error starting systemd services: %w", err)
+	}
+
+	return nil
+}
+
+// WaitForNoGPUProcesses waits until no processes are running on any GPU on the node.
+// If the nvidia kernel module is not loaded, there can be no GPU processes and this returns immediately.
+func (a *HookActions) WaitForNoGPUProcesses(timeout time.Duration) error {
+	nvidiaModuleLoaded, err := util.IsNvidiaModuleLoaded()
+	if err != nil {
+		return fmt.Errorf("error checking if nvidia module loaded: %v", err)
+	}
+	if !nvidiaModuleLoaded {
+		return nil
+	}
+
+	err = util.NvmlInit(a.nvml)
+	if err != nil {
+		return fmt.Errorf("error initializing NVML: %v", err)
+	}
+	defer util.TryNvmlShutdown(a.nvml)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pids, err := a.getGPUProcesses()
+		if err != nil {
+			return err
+		}
+		if len(pids) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout after %v waiting for GPU processes to exit: %v", timeout, pids)
+		}
+		log.Debugf("Waiting for GPU processes to exit: %v", pids)
+		time.Sleep(waitNoGPUProcessesInterval)
+	}
+}
+
+// GetHookContext returns information about the node and the MIG configuration being applied.
+// The summary of each GPU reflects its state at the time this function is called.
+func (a *HookActions) GetHookContext() (*hooks.HookContext, error) {
+	hostname, err := util.GetHostname()
+	if err != nil {
+		return nil, fmt.Errorf("error getting hostname: %v", err)
+	}
+
+	gpus, err := a.getGPUSummaries()
+	if err != nil {
+		return nil, fmt.Errorf("error getting GPU summaries: %v", err)
+	}
+
+	hookContext := &hooks.HookContext{
+		Hostname:       hostname,
+		SelectedConfig: a.selectedConfig,
+		GPUs:           gpus,
+	}
+
+	return hookContext, nil
+}
+
+// Prepare records which changes applying a MIG configuration is about to make,
+// along with the number of GPUs visible to workloads before any changes are made.
+// It must be called before running any hooks with a 'when' clause.
+func (a *HookActions) Prepare(applier MigConfigApplier, modeOnly bool) error {
+	gpus, err := a.getGPUSummaries()
+	if err != nil {
+		return fmt.Errorf("error getting GPU summaries: %v", err)
+	}
+
+	applyContext := &hooks.ApplyContext{
+		SelectedConfig: a.selectedConfig,
+		ModeChange:     applier.AssertMigMode() != nil,
+	}
+	if !modeOnly {
+		applyContext.ConfigChange = applier.AssertMigConfig() != nil
+	}
+	for _, gpu := range gpus {
+		applyContext.DeviceIDs = append(applyContext.DeviceIDs, gpu.DeviceID)
+	}
+
+	a.applyContext = applyContext
+	a.initialGPUCount = countVisibleGPUs(gpus)
+
+	return nil
+}
+
+// GetApplyContext returns the changes recorded by Prepare.
+// Whether the number of GPUs visible to workloads has changed is evaluated at the time this function is called.
+func (a *HookActions) GetApplyContext() (*hooks.ApplyContext, error) {
+	if a.applyContext == nil {
+		return nil, fmt.Errorf("no changes recorded for the MIG configuration being applied")
+	}
+
+	gpus, err := a.getGPUSummaries()
+	if err != nil {
+		return nil, fmt.Errorf("error getting GPU summaries: %v", err)
+	}
+
+	applyContext := *a.applyContext
+	applyContext.GPUCountChange = countVisibleGPUs(gpus) != a.initialGPUCount
+
+	return &applyContext, nil
+}
+
+// countVisibleGPUs counts the GPUs visible to workloads: each MIG device of a GPU with MIG
+// enabled, and each GPU with MIG disabled.
+func countVisibleGPUs(gpus []hooks.GPUSummary) int {
+	count := 0
+	for _, gpu := range gpus {
+		if !gpu.MigEnabled {
+			count++
+			continue
+		}
+		for _, n := range gpu.MigDevices {
+			count += n
+		}
+	}
+	return count
+}
+
+func (a *HookActions) getGPUSummaries() ([]hooks.GPUSummary, error) {
+	deviceIDs, err := util.GetGPUDeviceIDs()
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating GPU device IDs: %v", err)
+	}
+
+	nvidiaModuleLoaded, err := util.IsNvidiaModuleLoaded()
+	if err != nil {
+		return nil, fmt.Errorf("error checking if nvidia module loaded: %v", err)
+	}
+
+	if nvidiaModuleLoaded {
+		err := util.NvmlInit(a.nvml)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing NVML: %v", err)
+		}
+		defer util.TryNvmlShutdown(a.nvml)
+	}
+
+	modeManager, err := util.NewMigModeManager(a.nvml)
+	if err != nil {
+		return nil, fmt.Errorf("error creating MIG mode Manager: %w", err)
+	}
+
+	var configManager config.Manager
+	var summaries []hooks.GPUSummary
+	for i, deviceID := range deviceIDs {
+		summary := hooks.GPUSummary{
+			Index:    i,
+			DeviceID: deviceID.String(),
+		}
+
+		summary.MigCapable, err = modeManager.IsMigCapable(i)
+		if err != nil {
+			return nil, fmt.Errorf("error checking MIG capable for GPU %d: %v", i, err)
+		}
+
+		if summary.MigCapable {
+			m, err := modeManager.GetMigMode(i)
+			if err != nil {
+				return nil, fmt.Errorf("error getting MIG mode for GPU %d: %v", i, err)
+			}
+			summary.MigEnabled = (m == mode.Enabled)
+		}
+
+		if summary.MigEnabled && nvidiaModuleLoaded {
+			if configManager == nil {
+				configManager, err = util.NewMigConfigManager(a.nvml)
+				if err != nil {
+					return nil, fmt.Errorf("error creating MIG config Manager: %w", err)
+				}
+			}
+			summary.MigDevices, err = configManager.GetMigConfig(i)
+			if err != nil {
+				return nil, fmt.Errorf("error getting MIG config for GPU %d: %v", i, err)
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+func (a *HookActions) getSystemdManager() (*systemd.Manager, error) {
+	if a.systemdManager != nil {
+		return a.systemdManager, nil
+	}
+	manager, err := systemd.NewManager(a.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing systemd manager: %w", err)
+	}
+	a.systemdManager = manager
+	return manager, nil
+}
+
+func (a *HookActions) getGPUProcesses() ([]uint32, error) {
+	count, ret := a.nvml.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("error getting device count: %v", ret)
+	}
+
+	var pids []uint32
+	for i := 0; i < count; i++ {
+		device, ret := a.nvml.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("error getting device handle for GPU %d: %v", i, ret)
+		}
+
+		compute, ret := device.GetComputeRunningProcesses()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("error getting compute processes for GPU %d: %v", i, ret)
+		}
+		graphics, ret := device.GetGraphicsRunningProcesses()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("error getting graphics processes for GPU %d: %v", i, ret)
+		}
+
+		for _, p := range append(compute, graphics...) {
+			if !slices.Contains(pids, p.Pid) {
+				pids = append(pids, p.Pid)
+			}
+		}
+	}
+
+	return pids, nil
+}
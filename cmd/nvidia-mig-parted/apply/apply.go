@@ -24,10 +24,9 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 
-	"github.com/NVIDIA/go-nvml/pkg/nvml"
-
 	hooks "github.com/NVIDIA/mig-parted/api/hooks/v1"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/assert"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
 
 	"sigs.k8s.io/yaml"
 )
@@ -43,6 +42,7 @@ func GetLogger() *logrus.Logger {
 type Flags struct {
 	assert.Flags
 	HooksFile string
+	Plan      bool
 }
 
 // Context holds the state we want to pass around between functions associated with the 'apply' subcommand.
@@ -68,8 +68,8 @@ func BuildCommand() *cli.Command {
 	apply := cli.Command{}
 	apply.Name = "apply"
 	apply.Usage = "Apply changes (if necessary) for a specific MIG configuration from a configuration file"
-	apply.Action = func(_ context.Context, c *cli.Command) error {
-		return applyWrapper(c, &applyFlags)
+	apply.Action = func(ctx context.Context, c *cli.Command) error {
+		return applyWrapper(ctx, c, &applyFlags)
 	}
 
 	// Setup the flags for this command
@@ -109,6 +109,23 @@ func BuildCommand() *cli.Command {
 			Destination: &applyFlags.ModeOnly,
 			Sources:     cli.EnvVars("MIG_PARTED_MODE_CHANGE_ONLY"),
 		},
+		&cli.StringFlag{
+			Name:        "facts-file",
+			Usage:       "Path to a file of KEY=value facts about the node for the 'node-selector' of the selected config to match on",
+			Destination: &applyFlags.FactsFile,
+			Sources:     cli.EnvVars("MIG_PARTED_FACTS_FILE"),
+		},
+		&cli.StringFlag{
+			Name:        "host-root",
+			Usage:       "Path at which the root filesystem of the host is mounted, to read its os-release from when running in a container",
+			Destination: &applyFlags.HostRoot,
+			Sources:     cli.EnvVars(util.HostRootEnvVar),
+		},
+		&cli.BoolFlag{
+			Name:        "plan",
+			Usage:       "Print the MIG config that would be applied to each GPU, with any fill or range counts resolved, without applying it",
+			Destination: &applyFlags.Plan,
+		},
 	}
 
 	return &apply
@@ -119,7 +136,8 @@ func CheckFlags(f *Flags) error {
 	return assert.CheckFlags(&f.Flags)
 }
 
-// ParseHooksFile parses a hoosk file and unmarshals it into a 'hooks.Spec'.
+// ParseHooksFile parses a hooks file and unmarshals it into a 'hooks.Spec'.
+// Unmarshalling is strict: unknown fields, unknown hook names, and invalid hooks are all reported as errors.
 func ParseHooksFile(hooksFile string) (*hooks.Spec, error) {
 	var err error
 	var hooksYaml []byte
@@ -185,7 +203,7 @@ func (c *Context) ApplyMigConfig() error {
 	return ApplyMigConfig(c)
 }
 
-func applyWrapper(c *cli.Command, f *Flags) error {
+func applyWrapper(ctx context.Context, c *cli.Command, f *Flags) error {
 	err := CheckFlags(f)
 	if err != nil {
 		_ = cli.ShowSubcommandHelp(c)
@@ -204,6 +222,19 @@ func applyWrapper(c *cli.Command, f *Flags) error {
 		return fmt.Errorf("error selecting MIG config: %v", err)
 	}
 
+	log.Debugf("Selecting the entries of the MIG config for this node...")
+	nodeMigConfig, unmatched, err := assert.SelectNodeMigConfig(&f.Flags, migConfig)
+	if err != nil {
+		return fmt.Errorf("error selecting MIG config entries for this node: %v", err)
+	}
+	for _, i := range unmatched {
+		log.Warnf("Skipping entry %d of the selected configuration, which does not match this node (node-selector: %v)", i, migConfig[i].NodeSelector)
+	}
+
+	if f.Plan {
+		return printPlan(f, nodeMigConfig)
+	}
+
 	hooksSpec := &hooks.Spec{}
 	if f.HooksFile != "" {
 		log.Debugf("Parsing Hooks file...")
@@ -213,18 +244,31 @@ func applyWrapper(c *cli.Command, f *Flags) error {
 		}
 	}
 
-	hooks := NewApplyHooks(hooksSpec.Hooks)
+	nvmlLib := util.NewNvml()
+
+	actions := NewHookActions(ctx, nvmlLib, f.SelectedConfig)
+	defer actions.Close()
+
+	hooks := NewApplyHooks(hooksSpec.Hooks, actions)
 
 	context := Context{
 		Flags: f,
 		Context: assert.Context{
 			Command:   c,
 			Flags:     &f.Flags,
-			MigConfig: migConfig,
-			Nvml:      nvml.New(),
+			MigConfig: nodeMigConfig,
+			Nvml:      nvmlLib,
 		},
 	}
 
+	if hooksSpec.Hooks.HasConditions() {
+		log.Debugf("Checking which changes will be applied...")
+		err = actions.Prepare(&context, f.ModeOnly)
+		if err != nil {
+			return fmt.Errorf("error checking which changes will be applied: %v", err)
+		}
+	}
+
 	err = ApplyMigConfigWithHooks(log, c, f.ModeOnly, hooks, &context)
 	if err != nil {
 		return fmt.Errorf("error applying MIG configuration with hooks: %v", err)
@@ -82,19 +82,63 @@ func ApplyMigConfig(c *Context) error {
 			return nil
 		}
 
+		if mc.GpuInstances != nil {
+			current, err := configManager.GetGpuInstances(i)
+			if err != nil {
+				return fmt.Errorf("error getting GPU instances: %v", err)
+			}
+
+			log.Debugf("    Updating GPU instances: %v", mc.GpuInstances)
+
+			canonicalCurrent, err := configManager.CanonicalizeGpuInstances(i, current)
+			if err != nil {
+				return fmt.Errorf("error canonicalizing current GPU instances: %v", err)
+			}
+			canonical, err := configManager.CanonicalizeGpuInstances(i, mc.GpuInstances)
+			if err != nil {
+				return fmt.Errorf("error canonicalizing GPU instances: %v", err)
+			}
+
+			if canonical.Matches(canonicalCurrent) {
+				log.Debugf("    Skipping -- already set to desired value")
+				return nil
+			}
+
+			err = configManager.SetGpuInstances(i, types.GpuInstancesConfig(canonical))
+			if err != nil {
+				return fmt.Errorf("error setting GPU instances: %v", err)
+			}
+
+			return nil
+		}
+
 		current, err := configManager.GetMigConfig(i)
 		if err != nil {
 			return fmt.Errorf("error getting MIGConfig: %v", err)
 		}
 
-		log.Debugf("    Updating MIG config: %v", mc.MigDevices)
+		migDevices, err := c.ResolveMigDevices(mc, i)
+		if err != nil {
+			return err
+		}
+
+		log.Debugf("    Updating MIG config: %v", migDevices)
+
+		canonicalCurrent, err := configManager.CanonicalizeMigConfig(i, current)
+		if err != nil {
+			return fmt.Errorf("error canonicalizing current MIGConfig: %v", err)
+		}
+		canonical, err := configManager.CanonicalizeMigConfig(i, migDevices)
+		if err != nil {
+			return fmt.Errorf("error canonicalizing MIGConfig: %v", err)
+		}
 
-		if current.Equals(mc.MigDevices) {
+		if canonicalCurrent.Equals(canonical) {
 			log.Debugf("    Skipping -- already set to desired value")
 			return nil
 		}
 
-		err = configManager.SetMigConfig(i, mc.MigDevices)
+		err = configManager.SetMigConfig(i, types.MigConfig(canonical))
 		if err != nil {
 			return fmt.Errorf("error setting MIGConfig: %v", err)
 		}
@@ -20,15 +20,9 @@ import (
 	hooks "github.com/NVIDIA/mig-parted/api/hooks/v1"
 )
 
-const (
-	applyStartHook     = "apply-start"
-	preApplyModeHook   = "pre-apply-mode"
-	preApplyConfigHook = "pre-apply-config"
-	applyExitHook      = "apply-exit"
-)
-
 type applyHooks struct {
 	hooks.HooksMap
+	actions hooks.Actions
 }
 
 type ApplyHooks interface {
@@ -40,22 +34,22 @@ type ApplyHooks interface {
 
 var _ ApplyHooks = (*applyHooks)(nil)
 
-func NewApplyHooks(hooksMap hooks.HooksMap) ApplyHooks {
-	return &applyHooks{hooksMap}
+func NewApplyHooks(hooksMap hooks.HooksMap, actions hooks.Actions) ApplyHooks {
+	return &applyHooks{hooksMap, actions}
 }
 
 func (h *applyHooks) ApplyStart(envs hooks.EnvsMap, output bool) error {
-	return h.Run(applyStartHook, envs, output)
+	return h.RunWithActions(hooks.ApplyStartHook, envs, output, h.actions)
 }
 
 func (h *applyHooks) PreApplyMode(envs hooks.EnvsMap, output bool) error {
-	return h.Run(preApplyModeHook, envs, output)
+	return h.RunWithActions(hooks.PreApplyModeHook, envs, output, h.actions)
 }
 
 func (h *applyHooks) PreApplyConfig(envs hooks.EnvsMap, output bool) error {
-	return h.Run(preApplyConfigHook, envs, output)
+	return h.RunWithActions(hooks.PreApplyConfigHook, envs, output, h.actions)
 }
 
 func (h *applyHooks) ApplyExit(envs hooks.EnvsMap, output bool) error {
-	return h.Run(applyExitHook, envs, output)
+	return h.RunWithActions(hooks.ApplyExitHook, envs, output, h.actions)
 }
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apply
+
+import (
+	"fmt"
+	"os"
+
+	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/assert"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/export"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
+)
+
+// printPlan prints the entries of the selected MIG config that apply to this
+// node, with their MIG device requests resolved against each GPU, without
+// applying them.
+func printPlan(f *Flags, migConfig v1.MigConfigSpecSlice) error {
+	context := assert.Context{
+		Flags:     &f.Flags,
+		MigConfig: migConfig,
+		Nvml:      util.NewNvml(),
+	}
+
+	log.Debugf("Resolving MIG device requests...")
+	resolved, err := context.ResolveMigConfig()
+	if err != nil {
+		return fmt.Errorf("error resolving MIG config: %v", err)
+	}
+
+	spec := &v1.Spec{
+		Version:    v1.Version,
+		MigConfigs: map[string]v1.MigConfigSpecSlice{f.SelectedConfig: resolved},
+	}
+	return export.WriteOutput(os.Stdout, spec, &export.Flags{OutputFormat: export.YAMLFormat})
+}
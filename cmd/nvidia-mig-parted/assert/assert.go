@@ -19,8 +19,10 @@ package assert
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -30,9 +32,8 @@ import (
 
 	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
 	"github.com/NVIDIA/mig-parted/pkg/types"
-
-	"sigs.k8s.io/yaml"
 )
 
 var log = logrus.New()
@@ -47,6 +48,8 @@ type Flags struct {
 	SkipReset      bool
 	ModeOnly       bool
 	ValidConfig    bool
+	FactsFile      string
+	HostRoot       string
 }
 
 type Context struct {
@@ -54,6 +57,9 @@ type Context struct {
 	Flags     *Flags
 	MigConfig v1.MigConfigSpecSlice
 	Nvml      nvml.Interface
+
+	// deviceProfiles caches the MIG profiles discovered to resolve MIG device requests.
+	deviceProfiles discovery.DeviceProfiles
 }
 
 func BuildCommand() *cli.Command {
@@ -98,6 +104,18 @@ func BuildCommand() *cli.Command {
 			Destination: &assertFlags.ValidConfig,
 			Sources:     cli.EnvVars("MIG_PARTED_VALID_CONFIG"),
 		},
+		&cli.StringFlag{
+			Name:        "facts-file",
+			Usage:       "Path to a file of KEY=value facts about the node for the 'node-selector' of the selected config to match on",
+			Destination: &assertFlags.FactsFile,
+			Sources:     cli.EnvVars("MIG_PARTED_FACTS_FILE"),
+		},
+		&cli.StringFlag{
+			Name:        "host-root",
+			Usage:       "Path at which the root filesystem of the host is mounted, to read its os-release from when running in a container",
+			Destination: &assertFlags.HostRoot,
+			Sources:     cli.EnvVars(util.HostRootEnvVar),
+		},
 	}
 
 	return &assert
@@ -127,11 +145,20 @@ func assertWrapper(c *cli.Command, f *Flags) error {
 		return nil
 	}
 
+	log.Debugf("Selecting the entries of the MIG config for this node...")
+	nodeMigConfig, unmatched, err := SelectNodeMigConfig(f, migConfig)
+	if err != nil {
+		return fmt.Errorf("error selecting MIG config entries for this node: %v", err)
+	}
+	for _, i := range unmatched {
+		log.Warnf("Entry %d of the selected configuration does not match this node (node-selector: %v)", i, migConfig[i].NodeSelector)
+	}
+
 	context := Context{
 		Command:   c,
 		Flags:     f,
-		MigConfig: migConfig,
-		Nvml:      nvml.New(),
+		MigConfig: nodeMigConfig,
+		Nvml:      util.NewNvml(),
 	}
 
 	log.Debugf("Asserting MIG mode configuration...")
@@ -168,30 +195,37 @@ func CheckFlags(f *Flags) error {
 	return nil
 }
 
+// ParseConfigFile reads the spec of a file, a directory of files, or stdin,
+// along with the specs they include.
 func ParseConfigFile(f *Flags) (*v1.Spec, error) {
-	var err error
-	var configYaml []byte
-
-	if f.ConfigFile == "-" {
-		scanner := bufio.NewScanner(os.Stdin)
-		for scanner.Scan() {
-			configYaml = append(configYaml, scanner.Bytes()...)
-			configYaml = append(configYaml, '\n')
-		}
-	} else {
-		configYaml, err = os.ReadFile(f.ConfigFile)
-		if err != nil {
-			return nil, fmt.Errorf("read error: %v", err)
-		}
+	if f.ConfigFile != "-" {
+		spec, err := v1.LoadConfigFile(f.ConfigFile)
+		return spec, ReportValidationErrors(err)
 	}
 
-	var spec v1.Spec
-	err = yaml.Unmarshal(configYaml, &spec)
-	if err != nil {
-		return nil, fmt.Errorf("unmarshal error: %v", err)
+	var configYaml []byte
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		configYaml = append(configYaml, scanner.Bytes()...)
+		configYaml = append(configYaml, '\n')
 	}
 
-	return &spec, nil
+	spec, err := v1.LoadConfig(configYaml, "<stdin>", ".")
+	return spec, ReportValidationErrors(err)
+}
+
+// ReportValidationErrors logs each of the errors found validating a config
+// file, returning an error counting them in their place. Other errors are
+// returned as is.
+func ReportValidationErrors(err error) error {
+	var validationErrors v1.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	for _, e := range validationErrors {
+		log.Error(e)
+	}
+	return fmt.Errorf("found %d error(s) in config file", len(validationErrors))
 }
 
 func GetSelectedMigConfig(f *Flags, spec *v1.Spec) (v1.MigConfigSpecSlice, error) {
@@ -212,10 +246,42 @@ func GetSelectedMigConfig(f *Flags, spec *v1.Spec) (v1.MigConfigSpecSlice, error
 	return spec.MigConfigs[f.SelectedConfig], nil
 }
 
+// SelectNodeMigConfig returns the entries of a MIG config whose node selector
+// matches this node, along with the indices of the entries that do not. An
+// error is returned if the MIG config has node selectors but none of its
+// entries matches this node, as it then has nothing to apply to it.
+func SelectNodeMigConfig(f *Flags, migConfig v1.MigConfigSpecSlice) (v1.MigConfigSpecSlice, []int, error) {
+	if !slices.ContainsFunc(migConfig, func(mc v1.MigConfigSpec) bool { return mc.NodeSelector != nil }) {
+		return migConfig, nil, nil
+	}
+
+	node, err := util.GetNodeInfo(f.HostRoot, f.FactsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting node facts: %v", err)
+	}
+	log.Debugf("Node facts: %+v", node)
+
+	var selected v1.MigConfigSpecSlice
+	var unmatched []int
+	for i, mc := range migConfig {
+		if !mc.MatchesNode(node) {
+			unmatched = append(unmatched, i)
+			continue
+		}
+		selected = append(selected, mc)
+	}
+
+	if len(selected) == 0 {
+		return nil, unmatched, fmt.Errorf("no entry of the selected configuration matches this node (hostname: %v, gpu-count: %v)", node.Hostname, node.GPUCount)
+	}
+
+	return selected, unmatched, nil
+}
+
 func WalkSelectedMigConfigForEachGPU(migConfig v1.MigConfigSpecSlice, f func(*v1.MigConfigSpec, int, types.DeviceID) error) error {
-	deviceIDs, err := util.GetGPUDeviceIDs()
+	devices, err := util.GetGPUDevices()
 	if err != nil {
-		return fmt.Errorf("error enumerating GPU device IDs: %v", err)
+		return fmt.Errorf("error enumerating GPUs: %v", err)
 	}
 
 	for _, mc := range migConfig {
@@ -225,8 +291,8 @@ func WalkSelectedMigConfigForEachGPU(migConfig v1.MigConfigSpecSlice, f func(*v1
 			log.Debugf("Walking MigConfig for (device-filter=%v, devices=%v)", mc.DeviceFilter, mc.Devices)
 		}
 
-		for i, deviceID := range deviceIDs {
-			if !mc.MatchesDeviceFilter(deviceID) {
+		for i, device := range devices {
+			if !mc.MatchesDevice(device) {
 				continue
 			}
 
@@ -234,10 +300,10 @@ func WalkSelectedMigConfigForEachGPU(migConfig v1.MigConfigSpecSlice, f func(*v1
 				continue
 			}
 
-			log.Debugf("  GPU %v: %v", i, deviceID)
+			log.Debugf("  GPU %v: %v", i, device.ID)
 
 			migConfigSpec := mc
-			err = f(&migConfigSpec, i, deviceID)
+			err = f(&migConfigSpec, i, device.ID)
 			if err != nil {
 				return err
 			}
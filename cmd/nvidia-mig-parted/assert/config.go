@@ -69,14 +69,49 @@ func AssertMigConfig(c *Context) error {
 			return nil
 		}
 
+		if mc.GpuInstances != nil {
+			current, err := configManager.GetGpuInstances(i)
+			if err != nil {
+				return fmt.Errorf("error getting GPU instances: %v", err)
+			}
+
+			log.Debugf("    Asserting GPU instances: %v", mc.GpuInstances)
+
+			canonicalCurrent, err := configManager.CanonicalizeGpuInstances(i, current)
+			if err != nil {
+				return fmt.Errorf("error canonicalizing current GPU instances: %v", err)
+			}
+			canonical, err := configManager.CanonicalizeGpuInstances(i, mc.GpuInstances)
+			if err != nil {
+				return fmt.Errorf("error canonicalizing GPU instances: %v", err)
+			}
+
+			matched[i] = canonical.Matches(canonicalCurrent)
+			return nil
+		}
+
 		current, err := configManager.GetMigConfig(i)
 		if err != nil {
 			return fmt.Errorf("error getting MIGConfig: %v", err)
 		}
 
-		log.Debugf("    Asserting MIG config: %v", mc.MigDevices)
+		migDevices, err := c.ResolveMigDevices(mc, i)
+		if err != nil {
+			return err
+		}
+
+		log.Debugf("    Asserting MIG config: %v", migDevices)
+
+		canonicalCurrent, err := configManager.CanonicalizeMigConfig(i, current)
+		if err != nil {
+			return fmt.Errorf("error canonicalizing current MIGConfig: %v", err)
+		}
+		canonical, err := configManager.CanonicalizeMigConfig(i, migDevices)
+		if err != nil {
+			return fmt.Errorf("error canonicalizing MIGConfig: %v", err)
+		}
 
-		if current.Equals(mc.MigDevices) {
+		if canonicalCurrent.Equals(canonical) {
 			matched[i] = true
 			return nil
 		}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assert
+
+import (
+	"fmt"
+
+	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
+	"github.com/NVIDIA/mig-parted/pkg/mig/builder"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// ResolveMigDevices returns the MIG devices an entry of the selected MIG
+// config asks for on GPU i. MIG device requests (e.g. "fill") are resolved
+// against the MIG profiles discovered on the GPU, which are only discovered
+// the first time they are needed.
+func (c *Context) ResolveMigDevices(mc *v1.MigConfigSpec, i int) (types.MigConfig, error) {
+	if mc.MigDeviceRequests == nil {
+		return mc.MigDevices, nil
+	}
+
+	if c.deviceProfiles == nil {
+		log.Debugf("Discovering MIG profiles to resolve MIG device requests...")
+		deviceProfiles, err := discovery.DiscoverMIGProfilesFrom(c.Nvml)
+		if err != nil {
+			return nil, fmt.Errorf("error discovering MIG profiles: %w", err)
+		}
+		c.deviceProfiles = deviceProfiles
+	}
+
+	profiles, exists := c.deviceProfiles[i]
+	if !exists {
+		return nil, fmt.Errorf("no MIG profiles discovered for GPU %d", i)
+	}
+
+	migDevices, err := builder.ResolveMigDevices(profiles, mc.MigDeviceRequests)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving MIG devices for GPU %d: %w", i, err)
+	}
+	log.Debugf("    Resolved MIG devices %v to %v", mc.MigDeviceRequests, migDevices)
+
+	return migDevices, nil
+}
+
+// ResolveMigConfig returns the selected MIG config with its MIG device
+// requests resolved: each entry with requests is replaced by one entry per
+// GPU it applies to, holding the concrete MIG devices for that GPU.
+func (c *Context) ResolveMigConfig() (v1.MigConfigSpecSlice, error) {
+	err := util.NvmlInit(c.Nvml)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing NVML: %v", err)
+	}
+	defer util.TryNvmlShutdown(c.Nvml)
+
+	modeManager, err := util.NewMigModeManager(c.Nvml)
+	if err != nil {
+		return nil, fmt.Errorf("error creating MIG Mode Manager: %w", err)
+	}
+
+	var resolved v1.MigConfigSpecSlice
+	for _, mc := range c.MigConfig {
+		if mc.MigDeviceRequests == nil {
+			resolved = append(resolved, mc)
+			continue
+		}
+		err := WalkSelectedMigConfigForEachGPU(v1.MigConfigSpecSlice{mc}, func(mc *v1.MigConfigSpec, i int, _ types.DeviceID) error {
+			capable, err := modeManager.IsMigCapable(i)
+			if err != nil {
+				return fmt.Errorf("error checking MIG capable: %v", err)
+			}
+			if !capable && mc.MatchesAllDevices() {
+				return nil
+			}
+			migDevices, err := c.ResolveMigDevices(mc, i)
+			if err != nil {
+				return err
+			}
+			resolved = append(resolved, v1.MigConfigSpec{
+				NodeSelector: mc.NodeSelector,
+				DeviceFilter: mc.DeviceFilter,
+				Devices:      []int{i},
+				MigEnabled:   mc.MigEnabled,
+				MigDevices:   migDevices,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
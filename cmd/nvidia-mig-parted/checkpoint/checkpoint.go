@@ -26,8 +26,6 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 
-	"github.com/NVIDIA/go-nvml/pkg/nvml"
-
 	checkpoint "github.com/NVIDIA/mig-parted/api/checkpoint/v1"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
 	"github.com/NVIDIA/mig-parted/pkg/mig/state"
@@ -88,7 +86,7 @@ func checkpointWrapper(c *cli.Command, f *Flags) error {
 		return err
 	}
 
-	nvml := nvml.New()
+	nvml := util.NewNvml()
 	err = util.NvmlInit(nvml)
 	if err != nil {
 		return fmt.Errorf("error initializing NVML: %v", err)
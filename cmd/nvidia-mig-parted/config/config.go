@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/assert"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/export"
+)
+
+var log = logrus.New()
+
+func GetLogger() *logrus.Logger {
+	return log
+}
+
+type RenderFlags struct {
+	ConfigFile     string
+	SelectedConfig string
+	OutputFormat   string
+}
+
+func BuildCommand() *cli.Command {
+	// Create the 'config' command
+	config := cli.Command{}
+	config.Name = "config"
+	config.Usage = "Inspect MIG config files"
+
+	// Register the subcommands with the 'config' command
+	config.Commands = []*cli.Command{
+		buildRenderCommand(),
+	}
+
+	return &config
+}
+
+func buildRenderCommand() *cli.Command {
+	// Create a flags struct to hold our flags
+	renderFlags := RenderFlags{}
+
+	// Create the 'render' command
+	render := cli.Command{}
+	render.Name = "render"
+	render.Usage = "Print a MIG config file with its includes merged and its templates expanded"
+	render.Action = func(_ context.Context, c *cli.Command) error {
+		return renderWrapper(c, &renderFlags)
+	}
+
+	// Setup the flags for this command
+	render.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "config-file",
+			Aliases:     []string{"f"},
+			Usage:       "Path to the configuration file",
+			Destination: &renderFlags.ConfigFile,
+			Sources:     cli.EnvVars("MIG_PARTED_CONFIG_FILE"),
+		},
+		&cli.StringFlag{
+			Name:        "selected-config",
+			Aliases:     []string{"c"},
+			Usage:       "The label of the mig-config to render (default: all of them)",
+			Destination: &renderFlags.SelectedConfig,
+			Sources:     cli.EnvVars("MIG_PARTED_SELECTED_CONFIG"),
+		},
+		&cli.StringFlag{
+			Name:        "output-format",
+			Aliases:     []string{"o"},
+			Usage:       "Format for the output [json | yaml]",
+			Destination: &renderFlags.OutputFormat,
+			Value:       export.YAMLFormat,
+			Sources:     cli.EnvVars("MIG_PARTED_OUTPUT_FORMAT"),
+		},
+	}
+
+	return &render
+}
+
+func renderWrapper(c *cli.Command, f *RenderFlags) error {
+	err := checkRenderFlags(f)
+	if err != nil {
+		_ = cli.ShowSubcommandHelp(c)
+		return err
+	}
+
+	spec, err := assert.ParseConfigFile(&assert.Flags{ConfigFile: f.ConfigFile})
+	if err != nil {
+		return fmt.Errorf("error parsing config file: %v", err)
+	}
+	log.Debugf("Parsed config: %+v", spec)
+
+	if f.SelectedConfig != "" {
+		config, exists := spec.MigConfigs[f.SelectedConfig]
+		if !exists {
+			return fmt.Errorf("selected mig-config not present: %v", f.SelectedConfig)
+		}
+		spec = &v1.Spec{
+			Version:    spec.Version,
+			MigConfigs: map[string]v1.MigConfigSpecSlice{f.SelectedConfig: config},
+		}
+	}
+
+	return export.WriteOutput(os.Stdout, spec, &export.Flags{OutputFormat: f.OutputFormat})
+}
+
+func checkRenderFlags(f *RenderFlags) error {
+	if f.ConfigFile == "" {
+		return fmt.Errorf("missing required flags 'config-file'")
+	}
+	return export.CheckFlags(&export.Flags{OutputFormat: f.OutputFormat})
+}
@@ -0,0 +1,348 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	checkpoint "github.com/NVIDIA/mig-parted/api/checkpoint/v1"
+	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/assert"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/export"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
+	"github.com/NVIDIA/mig-parted/pkg/mig/builder"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/mig/mode"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// node holds what converting between configs and checkpoints needs to know
+// about the GPUs of the node: their UUIDs, device IDs and MIG profiles.
+type node struct {
+	uuids          []string
+	deviceIDs      []types.DeviceID
+	capable        []bool
+	deviceProfiles discovery.DeviceProfiles
+}
+
+// getNode reads what it needs to know about the GPUs of the node through the
+// NVML library provided.
+func getNode(nvmlLib nvml.Interface) (*node, error) {
+	err := util.NvmlInit(nvmlLib)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing NVML: %v", err)
+	}
+	defer util.TryNvmlShutdown(nvmlLib)
+
+	n := &node{}
+	n.deviceIDs, err = util.GetNvmlGPUDeviceIDs(nvmlLib)
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating GPUs: %v", err)
+	}
+
+	modeManager := mode.NewNvmlMigModeManager(nvmlLib)
+
+	for i := range n.deviceIDs {
+		device, ret := nvmlLib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("error getting device handle: %v", ret)
+		}
+		uuid, ret := device.GetUUID()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("error getting device uuid: %v", ret)
+		}
+		capable, err := modeManager.IsMigCapable(i)
+		if err != nil {
+			return nil, fmt.Errorf("error checking MIG capable: %v", err)
+		}
+		n.uuids = append(n.uuids, uuid)
+		n.capable = append(n.capable, capable)
+	}
+
+	if slices.Contains(n.capable, true) {
+		n.deviceProfiles, err = discovery.DiscoverMIGProfilesFrom(nvmlLib)
+		if err != nil {
+			return nil, fmt.Errorf("error discovering MIG profiles: %w", err)
+		}
+	}
+
+	return n, nil
+}
+
+func (n *node) profiles(gpu int) ([]discovery.ProfileInfo, error) {
+	profiles, exists := n.deviceProfiles[gpu]
+	if !exists {
+		return nil, fmt.Errorf("no MIG profiles discovered for GPU %d", gpu)
+	}
+	return profiles, nil
+}
+
+// CheckpointToSpec converts a checkpoint of the GPUs of a node (those of the
+// NVML library provided) into a config creating the same GPU instances and
+// compute instances, at the same placements.
+func CheckpointToSpec(nvmlLib nvml.Interface, state *checkpoint.State, label string) (*v1.Spec, error) {
+	n, err := getNode(nvmlLib)
+	if err != nil {
+		return nil, err
+	}
+
+	var configSpecs v1.MigConfigSpecSlice
+	covered := make([]bool, len(n.uuids))
+	for _, deviceState := range state.MigState.Devices {
+		i := slices.Index(n.uuids, deviceState.UUID)
+		if i < 0 {
+			return nil, fmt.Errorf("GPU %v of the checkpoint is not on this node", deviceState.UUID)
+		}
+		covered[i] = true
+
+		configSpec := v1.MigConfigSpec{
+			DeviceFilter: []string{n.deviceIDs[i].Primary().String()},
+			Devices:      []int{i},
+			MigEnabled:   deviceState.MigMode == mode.Enabled,
+			MigDevices:   types.MigConfig{},
+		}
+		if configSpec.MigEnabled {
+			profiles, err := n.profiles(i)
+			if err != nil {
+				return nil, err
+			}
+			gpuInstances, err := gpuInstancesFromState(profiles, deviceState, i)
+			if err != nil {
+				return nil, fmt.Errorf("GPU %v: %v", deviceState.UUID, err)
+			}
+			if len(gpuInstances) > 0 {
+				configSpec.MigDevices = gpuInstances.MigConfig()
+				configSpec.GpuInstances = gpuInstances
+			}
+		}
+		configSpecs = append(configSpecs, configSpec)
+	}
+	log.Warnf("The GPUs of the checkpoint are identified by their index on this node rather than by UUID")
+
+	// The entries can only be merged (e.g. into 'devices: all') if they cover
+	// every GPU of the node, as the export of the node does.
+	if !slices.Contains(covered, false) {
+		configSpecs = export.MergeMigConfigSpecs(configSpecs)
+	} else {
+		for i := range covered {
+			if !covered[i] && n.capable[i] {
+				log.Warnf("GPU %d of this node is not in the checkpoint and is left out of the config", i)
+			}
+		}
+	}
+
+	spec := v1.Spec{
+		Version: v1.Version,
+		MigConfigs: map[string]v1.MigConfigSpecSlice{
+			label: configSpecs,
+		},
+	}
+	return &spec, nil
+}
+
+// gpuInstancesFromState names the GPU instances and compute instances of a
+// GPU in a checkpoint after the MIG profiles discovered on it.
+func gpuInstancesFromState(profiles []discovery.ProfileInfo, deviceState types.DeviceState, gpu int) (types.GpuInstancesConfig, error) {
+	giStates := slices.SortedFunc(slices.Values(deviceState.GpuInstances), func(a, b types.GpuInstanceState) int {
+		return cmp.Compare(a.Placement.Start, b.Placement.Start)
+	})
+
+	var gpuInstances types.GpuInstancesConfig
+	for _, giState := range giStates {
+		i := slices.IndexFunc(profiles, func(p discovery.ProfileInfo) bool {
+			return p.Profile.GetInfo().GIProfileID == giState.ProfileID
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("unknown GPU instance profile ID %v", giState.ProfileID)
+		}
+		pInfo := profiles[i]
+
+		if len(giState.ComputeInstances) == 0 {
+			log.Warnf("GPU instance %v at placement %v of GPU %d has no compute instances and is left out of the config", pInfo.Name, giState.Placement.Start, gpu)
+			continue
+		}
+
+		placement := int(giState.Placement.Start)
+		gi := types.GpuInstanceConfig{
+			Profile:   pInfo.Name,
+			Placement: &placement,
+		}
+		for _, ciState := range giState.ComputeInstances {
+			name, err := computeInstanceName(pInfo, ciState)
+			if err != nil {
+				return nil, err
+			}
+			gi.ComputeInstances = append(gi.ComputeInstances, name)
+		}
+		// A single compute instance spanning the GPU instance is the default.
+		if len(gi.ComputeInstances) == 1 && gi.ComputeInstances[0] == gi.Profile {
+			gi.ComputeInstances = nil
+		}
+		gpuInstances = append(gpuInstances, gi)
+	}
+	return gpuInstances, nil
+}
+
+func computeInstanceName(pInfo discovery.ProfileInfo, ciState types.ComputeInstanceState) (string, error) {
+	info := pInfo.Profile.GetInfo()
+	if info.CIProfileID == ciState.ProfileID && info.CIEngProfileID == ciState.EngProfileID {
+		return pInfo.Name, nil
+	}
+	for _, ci := range pInfo.ComputeInstances {
+		info := ci.Profile.GetInfo()
+		if info.CIProfileID == ciState.ProfileID && info.CIEngProfileID == ciState.EngProfileID {
+			return ci.Name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown compute instance profile ID (%v, %v) for GPU instance %v", ciState.ProfileID, ciState.EngProfileID, pInfo.Name)
+}
+
+// SpecToCheckpoint converts the selected MIG config of a spec into a
+// checkpoint of the GPUs of this node it applies to. GPU instances are given
+// a placement if they are declared without one, and MIG device requests
+// (e.g. "fill") are resolved against each GPU.
+func SpecToCheckpoint(spec *v1.Spec, selectedConfig string) (*checkpoint.State, error) {
+	flags := assert.Flags{SelectedConfig: selectedConfig}
+	migConfig, err := assert.GetSelectedMigConfig(&flags, spec)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting MIG config: %v", err)
+	}
+	migConfig, unmatched, err := assert.SelectNodeMigConfig(&flags, migConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting MIG config entries for this node: %v", err)
+	}
+	for _, i := range unmatched {
+		log.Warnf("Entry %d of the selected configuration does not match this node and is left out of the checkpoint", i)
+	}
+
+	n, err := getNode(util.NewNvml())
+	if err != nil {
+		return nil, err
+	}
+
+	deviceStates := make(map[int]types.DeviceState)
+	err = assert.WalkSelectedMigConfigForEachGPU(migConfig, func(mc *v1.MigConfigSpec, i int, _ types.DeviceID) error {
+		if !n.capable[i] {
+			if mc.MatchesAllDevices() {
+				return nil
+			}
+			return fmt.Errorf("GPU %d is not MIG capable", i)
+		}
+
+		deviceState := types.DeviceState{
+			UUID:    n.uuids[i],
+			MigMode: mode.Disabled,
+		}
+		if mc.MigEnabled {
+			deviceState.MigMode = mode.Enabled
+			profiles, err := n.profiles(i)
+			if err != nil {
+				return err
+			}
+			deviceState.GpuInstances, err = gpuInstanceStates(profiles, mc, i)
+			if err != nil {
+				return fmt.Errorf("GPU %d: %v", i, err)
+			}
+		}
+		deviceStates[i] = deviceState
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state := checkpoint.State{
+		Version: checkpoint.Version,
+	}
+	for _, i := range slices.Sorted(maps.Keys(deviceStates)) {
+		state.MigState.Devices = append(state.MigState.Devices, deviceStates[i])
+	}
+	return &state, nil
+}
+
+// gpuInstanceStates returns the GPU instances an entry of a MIG config
+// creates on a GPU, identified by the profile IDs of the GPU.
+func gpuInstanceStates(profiles []discovery.ProfileInfo, mc *v1.MigConfigSpec, gpu int) ([]types.GpuInstanceState, error) {
+	gpuInstances := mc.GpuInstances
+	if gpuInstances == nil {
+		migDevices := mc.MigDevices
+		if mc.MigDeviceRequests != nil {
+			var err error
+			migDevices, err = builder.ResolveMigDevices(profiles, mc.MigDeviceRequests)
+			if err != nil {
+				return nil, fmt.Errorf("error resolving MIG devices: %w", err)
+			}
+			log.Warnf("MIG devices %v are resolved to %v on GPU %d", mc.MigDeviceRequests, migDevices, gpu)
+		}
+		for _, name := range slices.Sorted(maps.Keys(migDevices)) {
+			if _, found := discovery.LookupProfile(profiles, name); !found {
+				return nil, fmt.Errorf("MIG device %v does not say which GPU instance it is created in, use 'gpu-instances' instead", name)
+			}
+			for range migDevices[name] {
+				gpuInstances = append(gpuInstances, types.GpuInstanceConfig{Profile: name})
+			}
+		}
+	}
+
+	placed, err := builder.PlaceGpuInstances(profiles, gpuInstances)
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(gpuInstances, func(gi types.GpuInstanceConfig) bool { return gi.Placement == nil }) {
+		log.Warnf("GPU instances declared without a placement are placed at %v on GPU %d", placed, gpu)
+	}
+
+	var giStates []types.GpuInstanceState
+	for _, gi := range placed {
+		pInfo, _ := discovery.LookupProfile(profiles, gi.Profile)
+		info := pInfo.Profile.GetInfo()
+
+		i := slices.IndexFunc(pInfo.Placements, func(p nvml.GpuInstancePlacement) bool { return int(p.Start) == *gi.Placement })
+		giState := types.GpuInstanceState{
+			ProfileID: info.GIProfileID,
+			Placement: pInfo.Placements[i],
+		}
+		for _, ci := range gi.GetComputeInstances() {
+			ciState, err := computeInstanceState(pInfo, ci)
+			if err != nil {
+				return nil, err
+			}
+			giState.ComputeInstances = append(giState.ComputeInstances, ciState)
+		}
+		giStates = append(giStates, giState)
+	}
+	return giStates, nil
+}
+
+func computeInstanceState(pInfo discovery.ProfileInfo, name string) (types.ComputeInstanceState, error) {
+	if name == pInfo.Name || pInfo.Profile.Matches(name) {
+		info := pInfo.Profile.GetInfo()
+		return types.ComputeInstanceState{ProfileID: info.CIProfileID, EngProfileID: info.CIEngProfileID}, nil
+	}
+	for _, ci := range pInfo.ComputeInstances {
+		if name == ci.Name || ci.Profile.Matches(name) {
+			info := ci.Profile.GetInfo()
+			return types.ComputeInstanceState{ProfileID: info.CIProfileID, EngProfileID: info.CIEngProfileID}, nil
+		}
+	}
+	return types.ComputeInstanceState{}, fmt.Errorf("compute instance profile %v is not supported by GPU instance %v", name, pInfo.Name)
+}
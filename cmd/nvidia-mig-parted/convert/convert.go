@@ -0,0 +1,278 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"sigs.k8s.io/yaml"
+
+	checkpoint "github.com/NVIDIA/mig-parted/api/checkpoint/v1"
+	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/assert"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/export"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
+)
+
+var log = logrus.New()
+
+func GetLogger() *logrus.Logger {
+	return log
+}
+
+type Flags struct {
+	InputFile      string
+	OutputFormat   string
+	ToCheckpoint   bool
+	SelectedConfig string
+	ConfigLabel    string
+}
+
+func BuildCommand() *cli.Command {
+	// Create a flags struct to hold our flags
+	convertFlags := Flags{}
+
+	// Create the 'convert' command
+	convert := cli.Command{}
+	convert.Name = "convert"
+	convert.Usage = "Convert a config file or a checkpoint file between spec versions and formats"
+	convert.Description = "Config files are written in the selected spec version and format. Checkpoint files are " +
+		"converted into the equivalent config, and configs into checkpoints with --to-checkpoint, " +
+		"using the GPUs of the node (or of --backend) to name their MIG profiles and identify them."
+	convert.Action = func(_ context.Context, c *cli.Command) error {
+		return convertWrapper(c, &convertFlags)
+	}
+
+	// Setup the flags for this command
+	convert.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "input-file",
+			Aliases:     []string{"f"},
+			Usage:       "Path to the config file, config directory or checkpoint file to convert, or '-' for stdin",
+			Destination: &convertFlags.InputFile,
+			Sources:     cli.EnvVars("MIG_PARTED_INPUT_FILE"),
+		},
+		&cli.StringFlag{
+			Name:        "output-format",
+			Aliases:     []string{"o"},
+			Usage:       "Format for the output [json | yaml] (default: yaml for configs, json for checkpoints)",
+			Destination: &convertFlags.OutputFormat,
+			Sources:     cli.EnvVars("MIG_PARTED_OUTPUT_FORMAT"),
+		},
+		&cli.BoolFlag{
+			Name:        "to-checkpoint",
+			Usage:       "Convert the selected config into a checkpoint of the GPUs of the node",
+			Destination: &convertFlags.ToCheckpoint,
+			Sources:     cli.EnvVars("MIG_PARTED_TO_CHECKPOINT"),
+		},
+		&cli.StringFlag{
+			Name:        "selected-config",
+			Aliases:     []string{"c"},
+			Usage:       "The label of the mig-config to convert into a checkpoint",
+			Destination: &convertFlags.SelectedConfig,
+			Sources:     cli.EnvVars("MIG_PARTED_SELECTED_CONFIG"),
+		},
+		&cli.StringFlag{
+			Name:        "config-label",
+			Aliases:     []string{"l"},
+			Usage:       "Label of the config converted from a checkpoint",
+			Destination: &convertFlags.ConfigLabel,
+			Value:       export.DefaultConfigLabel,
+			Sources:     cli.EnvVars("MIG_PARTED_CONFIG_LABEL"),
+		},
+	}
+
+	return &convert
+}
+
+func CheckFlags(f *Flags) error {
+	var missing []string
+	if f.InputFile == "" {
+		missing = append(missing, "input-file")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required flags '%v'", strings.Join(missing, ", "))
+	}
+
+	switch f.OutputFormat {
+	case "":
+	case export.JSONFormat:
+	case export.YAMLFormat:
+		if f.ToCheckpoint {
+			return fmt.Errorf("checkpoints can only be written in the '%v' output-format", export.JSONFormat)
+		}
+	default:
+		return fmt.Errorf("unrecognized 'output-format': %v", f.OutputFormat)
+	}
+
+	return nil
+}
+
+func convertWrapper(c *cli.Command, f *Flags) error {
+	err := CheckFlags(f)
+	if err != nil {
+		_ = cli.ShowSubcommandHelp(c)
+		return err
+	}
+
+	log.Debugf("Reading input file...")
+	input, err := readInput(f.InputFile)
+	if err != nil {
+		return fmt.Errorf("error reading input file: %v", err)
+	}
+
+	if input.isCheckpoint() {
+		if f.ToCheckpoint {
+			return fmt.Errorf("input file is already a checkpoint")
+		}
+		log.Debugf("Converting checkpoint into a config...")
+		spec, err := CheckpointToSpec(util.NewNvml(), input.checkpoint, f.ConfigLabel)
+		if err != nil {
+			return fmt.Errorf("error converting checkpoint: %v", err)
+		}
+		return writeSpec(os.Stdout, spec, f)
+	}
+
+	log.Debugf("Parsing config file...")
+	spec, err := input.loadSpec()
+	if err != nil {
+		return fmt.Errorf("error parsing config file: %v", err)
+	}
+
+	if f.ToCheckpoint {
+		log.Debugf("Converting config into a checkpoint...")
+		state, err := SpecToCheckpoint(spec, f.SelectedConfig)
+		if err != nil {
+			return fmt.Errorf("error converting config: %v", err)
+		}
+		return writeCheckpoint(os.Stdout, state)
+	}
+
+	return writeSpec(os.Stdout, spec, f)
+}
+
+// input is a config file, config directory or checkpoint file to convert.
+type input struct {
+	path       string
+	data       []byte
+	checkpoint *checkpoint.State
+}
+
+// readInput reads the file to convert, telling checkpoints apart from config
+// files by their top-level fields.
+func readInput(path string) (*input, error) {
+	in := &input{path: path}
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read error: %v", err)
+		}
+		in.data = data
+	} else {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("read error: %v", err)
+		}
+		if info.IsDir() {
+			return in, nil
+		}
+		in.data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read error: %v", err)
+		}
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := yaml.Unmarshal(in.data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+	if _, exists := fields["MigState"]; !exists {
+		return in, nil
+	}
+
+	var state checkpoint.State
+	if err := yaml.UnmarshalStrict(in.data, &state); err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+	if state.Version != checkpoint.Version {
+		return nil, fmt.Errorf("unknown checkpoint version: %v", state.Version)
+	}
+	in.checkpoint = &state
+	return in, nil
+}
+
+func (in *input) isCheckpoint() bool {
+	return in.checkpoint != nil
+}
+
+// loadSpec loads the config file (or directory) of the input along with the
+// files it includes, warning about what the converted config loses.
+func (in *input) loadSpec() (*v1.Spec, error) {
+	if in.data == nil {
+		log.Warnf("The config files of directory %v are merged into a single config", in.path)
+		spec, err := v1.LoadConfigFile(in.path)
+		return spec, assert.ReportValidationErrors(err)
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := yaml.Unmarshal(in.data, &fields); err == nil {
+		if _, exists := fields["include"]; exists {
+			log.Warnf("The files included by %v are merged into the converted config", in.path)
+		}
+		if _, exists := fields["templates"]; exists {
+			log.Warnf("The templates of %v are expanded into the MIG configs extending them", in.path)
+		}
+	}
+
+	var spec *v1.Spec
+	var err error
+	if in.path == "-" {
+		spec, err = v1.LoadConfig(in.data, "<stdin>", ".")
+	} else {
+		spec, err = v1.LoadConfigFile(in.path)
+	}
+	return spec, assert.ReportValidationErrors(err)
+}
+
+// writeSpec writes a config in the format selected. Configs are always
+// written in the v1 spec, the only version there is to convert between.
+func writeSpec(w io.Writer, spec *v1.Spec, f *Flags) error {
+	outputFormat := f.OutputFormat
+	if outputFormat == "" {
+		outputFormat = export.YAMLFormat
+	}
+	return export.WriteOutput(w, spec, &export.Flags{OutputFormat: outputFormat})
+}
+
+// writeCheckpoint writes a checkpoint as the 'checkpoint' command does.
+func writeCheckpoint(w io.Writer, state *checkpoint.State) error {
+	output, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling MIG state to json: %v", err)
+	}
+	if _, err := w.Write(append(output, '\n')); err != nil {
+		return fmt.Errorf("error writing JSON output: %w", err)
+	}
+	return nil
+}
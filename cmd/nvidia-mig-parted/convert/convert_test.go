@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	checkpoint "github.com/NVIDIA/mig-parted/api/checkpoint/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/export"
+	"github.com/NVIDIA/mig-parted/pkg/mig/config"
+	"github.com/NVIDIA/mig-parted/pkg/mig/state"
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+	"github.com/NVIDIA/mig-parted/pkg/sim/simtest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// convertFile converts a config file into the format given, as the 'convert' command does.
+func convertFile(t *testing.T, file string, outputFormat string) []byte {
+	in, err := readInput(file)
+	require.Nil(t, err, "Unexpected failure from readInput")
+	require.False(t, in.isCheckpoint())
+	spec, err := in.loadSpec()
+	require.Nil(t, err, "Unexpected failure from loadSpec")
+
+	var output bytes.Buffer
+	require.Nil(t, writeSpec(&output, spec, &Flags{OutputFormat: outputFormat}))
+	return output.Bytes()
+}
+
+func TestConvertConfigRoundTrip(t *testing.T) {
+	testCases := []struct {
+		description string
+		config      string
+		expected    string
+	}{
+		{
+			"MIG Devices",
+			`{"version": "v1", "mig-configs": {"all-1g.5gb": [{"devices": "all", "mig-enabled": true, "mig-devices": {"1g.5gb": 7}}]}}`,
+			`version: v1
+mig-configs:
+  all-1g.5gb:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      1g.5gb: 7
+`,
+		},
+		{
+			"GPU Instances",
+			`version: v1
+mig-configs:
+  shared:
+  - device-filter: "0x20B010DE"
+    devices: [0, 1]
+    mig-enabled: true
+    gpu-instances:
+    - {profile: 3g.20gb, placement: 4, compute-instances: [1c.3g.20gb, 2c.3g.20gb]}
+    - {profile: 3g.20gb}
+`,
+			`version: v1
+mig-configs:
+  shared:
+  - device-filter: "0x20B010DE"
+    devices: [0, 1]
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.20gb
+      placement: 4
+      compute-instances: [1c.3g.20gb, 2c.3g.20gb]
+    - profile: 3g.20gb
+`,
+		},
+		{
+			"Templates",
+			`version: v1
+templates:
+  a100: {device-filter: "0x20B010DE", devices: all}
+mig-configs:
+  a100-disabled:
+  - extends: a100
+    mig-enabled: false
+`,
+			`version: v1
+mig-configs:
+  a100-disabled:
+  - device-filter: "0x20B010DE"
+    devices: all
+    mig-enabled: false
+    mig-devices: {}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "config.yaml")
+			require.Nil(t, os.WriteFile(file, []byte(tc.config), 0600))
+
+			// Converting to JSON and back to YAML gives the same config, with
+			// its keys in the same order every time.
+			jsonFile := filepath.Join(dir, "config.json")
+			require.Nil(t, os.WriteFile(jsonFile, convertFile(t, file, export.JSONFormat), 0600))
+			converted := convertFile(t, jsonFile, export.YAMLFormat)
+			require.Equal(t, tc.expected, string(converted))
+
+			yamlFile := filepath.Join(dir, "converted.yaml")
+			require.Nil(t, os.WriteFile(yamlFile, converted, 0600))
+			require.Equal(t, converted, convertFile(t, yamlFile, export.YAMLFormat))
+		})
+	}
+}
+
+func TestCheckpointToSpec(t *testing.T) {
+	placement := 0
+	testCases := []struct {
+		description  string
+		gpus         []sim.GPUSpec
+		gpuInstances map[int]types.GpuInstancesConfig
+		expected     string
+	}{
+		{
+			"MIG Disabled",
+			[]sim.GPUSpec{{Model: "A100-SXM4-40GB", Count: 2}},
+			nil,
+			`version: v1
+mig-configs:
+  current:
+  - devices: all
+    mig-enabled: false
+    mig-devices: {}
+`,
+		},
+		{
+			"MIG Devices",
+			[]sim.GPUSpec{{Model: "A100-SXM4-40GB", MigEnabled: true, MigDevices: types.MigConfig{"3g.20gb": 1, "1g.5gb": 2}}},
+			nil,
+			`version: v1
+mig-configs:
+  current:
+  - devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.20gb
+      placement: 0
+    - profile: 1g.5gb
+      placement: 4
+    - profile: 1g.5gb
+      placement: 5
+`,
+		},
+		{
+			"Shared Compute Instances",
+			[]sim.GPUSpec{{Model: "H100-SXM5-80GB", MigEnabled: true}, {Model: "A100-SXM4-40GB"}},
+			map[int]types.GpuInstancesConfig{
+				0: {{Profile: "3g.40gb", Placement: &placement, ComputeInstances: []string{"1c.3g.40gb", "2c.3g.40gb"}}},
+			},
+			`version: v1
+mig-configs:
+  current:
+  - device-filter: "0x233010DE"
+    devices: all
+    mig-enabled: true
+    gpu-instances:
+    - profile: 3g.40gb
+      placement: 0
+      compute-instances: [1c.3g.40gb, 2c.3g.40gb]
+  - device-filter: "0x20B010DE"
+    devices: all
+    mig-enabled: false
+    mig-devices: {}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			nvmllib := simtest.NewNvml(t, tc.gpus...)
+			manager := config.NewNvmlMigConfigManager(nvmllib)
+			for i, gpuInstances := range tc.gpuInstances {
+				require.Nil(t, manager.SetGpuInstances(i, gpuInstances))
+			}
+			migState, err := state.NewMigStateManager(nvmllib).Fetch()
+			require.Nil(t, err)
+
+			spec, err := CheckpointToSpec(nvmllib, &checkpoint.State{Version: checkpoint.Version, MigState: *migState}, "current")
+			require.Nil(t, err, "Unexpected failure from CheckpointToSpec")
+
+			var output bytes.Buffer
+			require.Nil(t, writeSpec(&output, spec, &Flags{}))
+			require.Equal(t, tc.expected, output.String())
+		})
+	}
+}
+
+func TestCheckpointToSpecUnknownGPU(t *testing.T) {
+	nvmllib := simtest.NewNvml(t, sim.GPUSpec{Model: "A100-SXM4-40GB"})
+
+	var state checkpoint.State
+	require.Nil(t, yaml.Unmarshal([]byte(`{"Version": "v1", "MigState": {"Devices": [{"UUID": "GPU-unknown", "MigMode": 0}]}}`), &state))
+	_, err := CheckpointToSpec(nvmllib, &state, "current")
+	require.NotNil(t, err, "Unexpected success converting a checkpoint of another node")
+	require.Contains(t, err.Error(), "GPU GPU-unknown of the checkpoint is not on this node")
+}
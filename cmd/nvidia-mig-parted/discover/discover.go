@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+)
+
+var log = logrus.New()
+
+func GetLogger() *logrus.Logger {
+	return log
+}
+
+const (
+	JSONFormat = "json"
+	YAMLFormat = "yaml"
+)
+
+type Flags struct {
+	SaveFile     string
+	OutputFormat string
+	QuirksFile   string
+}
+
+func BuildCommand() *cli.Command {
+	discoverFlags := Flags{}
+
+	discover := cli.Command{}
+	discover.Name = "discover"
+	discover.Usage = "Discover the MIG profiles of the GPUs on the node and save them for generating MIG configs elsewhere"
+	discover.Action = func(ctx context.Context, c *cli.Command) error {
+		return runDiscover(ctx, c, &discoverFlags)
+	}
+
+	discover.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "save",
+			Aliases:     []string{"f"},
+			Usage:       "Path of the file to save the discovered profiles to (default: stdout)",
+			Destination: &discoverFlags.SaveFile,
+			Value:       "",
+		},
+		&cli.StringFlag{
+			Name:        "output-format",
+			Aliases:     []string{"o"},
+			Usage:       "Format for the output [json | yaml]",
+			Destination: &discoverFlags.OutputFormat,
+			Value:       JSONFormat,
+			Sources:     cli.EnvVars("MIG_PARTED_OUTPUT_FORMAT"),
+		},
+		&cli.StringFlag{
+			Name:        "quirks-file",
+			Usage:       "Path to a file with quirks correcting the MIG profiles discovered on some GPUs, in addition to the built-in ones",
+			Destination: &discoverFlags.QuirksFile,
+			Value:       "",
+			Sources:     cli.EnvVars("MIG_PARTED_QUIRKS_FILE"),
+		},
+	}
+
+	return &discover
+}
+
+func runDiscover(_ context.Context, c *cli.Command, f *Flags) error {
+	err := checkFlags(f)
+	if err != nil {
+		_ = cli.ShowSubcommandHelp(c)
+		return err
+	}
+
+	if f.QuirksFile != "" {
+		err := discovery.LoadQuirksFile(f.QuirksFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	deviceProfiles, err := discovery.DiscoverMIGProfiles()
+	if err != nil {
+		return fmt.Errorf("error discovering MIG profiles: %w", err)
+	}
+	snapshot := discovery.NewSnapshot(deviceProfiles)
+
+	var output []byte
+	switch f.OutputFormat {
+	case JSONFormat:
+		output, err = json.MarshalIndent(snapshot, "", "  ")
+		output = append(output, '\n')
+	case YAMLFormat:
+		output, err = yaml.Marshal(snapshot)
+	}
+	if err != nil {
+		return fmt.Errorf("error marshaling discovered profiles: %w", err)
+	}
+
+	writer := io.Writer(os.Stdout)
+	if f.SaveFile != "" {
+		file, err := os.Create(f.SaveFile)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	if _, err := writer.Write(output); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
+
+func checkFlags(f *Flags) error {
+	switch f.OutputFormat {
+	case JSONFormat:
+	case YAMLFormat:
+	default:
+		return fmt.Errorf("unrecognized 'output-format': %s", f.OutputFormat)
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 
 	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
+	"github.com/NVIDIA/mig-parted/pkg/mig/config"
 	"github.com/NVIDIA/mig-parted/pkg/mig/mode"
 	"github.com/NVIDIA/mig-parted/pkg/types"
 )
@@ -33,21 +34,24 @@ func ExportMigConfigs(c *Context) (*v1.Spec, error) {
 	}
 	defer util.TryNvmlShutdown(c.Nvml)
 
-	deviceIDs, err := util.GetGPUDeviceIDs()
+	nvmlSupported, err := util.IsNVMLVersionSupported(c.Nvml)
 	if err != nil {
-		return nil, fmt.Errorf("error enumerating GPUs: %v", err)
+		return nil, fmt.Errorf("error checking NVML version: %v", err)
 	}
-
-	modeManager, err := util.NewMigModeManager(c.Nvml)
-	if err != nil {
-		return nil, fmt.Errorf("error creating MIG Mode Manager: %w", err)
+	if !nvmlSupported {
+		return nil, fmt.Errorf("NVML version unsupported for performing MIG operations")
 	}
 
-	configManager, err := util.NewMigConfigManager(c.Nvml)
+	// Everything is read through the NVML library of the context, so that
+	// the GPUs exported are those of the backend it was created for.
+	deviceIDs, err := util.GetNvmlGPUDeviceIDs(c.Nvml)
 	if err != nil {
-		return nil, fmt.Errorf("error creating MIG Config Manager: %w", err)
+		return nil, fmt.Errorf("error enumerating GPUs: %v", err)
 	}
 
+	modeManager := mode.NewNvmlMigModeManager(c.Nvml)
+	configManager := config.NewNvmlMigConfigManager(c.Nvml)
+
 	configSpecs := make(v1.MigConfigSpecSlice, len(deviceIDs))
 	for i, deviceID := range deviceIDs {
 		// Strip any subsystem qualifier so exported configs apply to all board variants of a GPU.
@@ -67,11 +71,16 @@ func ExportMigConfigs(c *Context) (*v1.Spec, error) {
 		}
 
 		migDevices := types.MigConfig{}
+		var gpuInstances types.GpuInstancesConfig
 		if enabled {
 			migDevices, err = configManager.GetMigConfig(i)
 			if err != nil {
 				return nil, fmt.Errorf("error getting MIGConfig: %v", err)
 			}
+			gpuInstances, err = getSharedGpuInstances(configManager, i)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		configSpecs[i] = v1.MigConfigSpec{
@@ -79,23 +88,39 @@ func ExportMigConfigs(c *Context) (*v1.Spec, error) {
 			Devices:      []int{i},
 			MigEnabled:   enabled,
 			MigDevices:   migDevices,
+			GpuInstances: gpuInstances,
 		}
 	}
 
 	spec := v1.Spec{
 		Version: v1.Version,
 		MigConfigs: map[string]v1.MigConfigSpecSlice{
-			c.Flags.ConfigLabel: mergeMigConfigSpecs(configSpecs),
+			c.Flags.ConfigLabel: MergeMigConfigSpecs(configSpecs),
 		},
 	}
 
 	return &spec, nil
 }
 
-// mergeMigConfigSpecs merges the specs from a MigConfigSpecsSlice into a more
+// getSharedGpuInstances returns the GPU instances of a GPU if any of them is
+// split into compute instances, which its 'MigConfig' alone cannot express.
+func getSharedGpuInstances(configManager config.Manager, gpu int) (types.GpuInstancesConfig, error) {
+	gpuInstances, err := configManager.GetGpuInstances(gpu)
+	if err != nil {
+		return nil, fmt.Errorf("error getting GPU instances: %v", err)
+	}
+	for _, gi := range gpuInstances {
+		if gi.ComputeInstances != nil {
+			return gpuInstances, nil
+		}
+	}
+	return nil, nil
+}
+
+// MergeMigConfigSpecs merges the specs from a MigConfigSpecsSlice into a more
 // compact form for better display.
 //
-// We assume the 'specs' argument is passed in from the ExportMigConfigs(), so we know
+// We assume the 'specs' argument is built like those of ExportMigConfigs(), so we know
 // that the 'interface{}' types for '.DeviceFilter' and '.Devices' are both
 // slices and not strings.
 //
@@ -104,9 +129,9 @@ func ExportMigConfigs(c *Context) (*v1.Spec, error) {
 // '.DeviceFilter'.
 //
 // This allows us to simplify the logic below significantly.
-func mergeMigConfigSpecs(specs v1.MigConfigSpecSlice) v1.MigConfigSpecSlice {
-	// Merge the incoming specs by comparing their MigEnabled and MigDevices fields.
-	// For any two specs, if both of these are equal, then we merge them
+func MergeMigConfigSpecs(specs v1.MigConfigSpecSlice) v1.MigConfigSpecSlice {
+	// Merge the incoming specs by comparing their MigEnabled, MigDevices and GpuInstances fields.
+	// For any two specs, if all of these are equal, then we merge them
 	// together and concatenate their device filter and devices lists.
 	merged := []v1.MigConfigSpec{}
 OUTER:
@@ -122,6 +147,9 @@ OUTER:
 			if !s.MigDevices.Equals(m.MigDevices) {
 				continue
 			}
+			if (s.GpuInstances == nil) != (m.GpuInstances == nil) || !s.GpuInstances.Matches(m.GpuInstances) {
+				continue
+			}
 			merged[i].Devices = mergeAndSortIntSlices(m.Devices.([]int), s.Devices.([]int))
 			merged[i].DeviceFilter = mergeAndSortStringSlices(m.DeviceFilter.([]string), s.DeviceFilter.([]string))
 			continue OUTER
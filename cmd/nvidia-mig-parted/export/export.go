@@ -29,6 +29,7 @@ import (
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
 	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
 
 	yaml "gopkg.in/yaml.v2"
 )
@@ -101,7 +102,7 @@ func exportWrapper(c *cli.Command, f *Flags) error {
 	context := Context{
 		Command: c,
 		Flags:   f,
-		Nvml:    nvml.New(),
+		Nvml:    util.NewNvml(),
 	}
 
 	spec, err := ExportMigConfigs(&context)
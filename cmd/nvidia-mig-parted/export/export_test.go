@@ -22,6 +22,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+	"github.com/NVIDIA/mig-parted/pkg/sim/hardware"
+	"github.com/NVIDIA/mig-parted/pkg/sim/simtest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
 )
 
 func TestMergeConfigSpecs(t *testing.T) {
@@ -196,8 +200,45 @@ func TestMergeConfigSpecs(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.Description, func(t *testing.T) {
-			merged := mergeMigConfigSpecs(tc.Input)
+			merged := MergeMigConfigSpecs(tc.Input)
 			require.Equal(t, tc.Output, merged)
 		})
 	}
 }
+
+func TestExportMigConfigsFromSimulatedNode(t *testing.T) {
+	var gpus []sim.GPUSpec
+	expected := make(map[string]types.MigConfig)
+	for _, name := range hardware.Names() {
+		model, err := hardware.Get(name)
+		require.Nil(t, err)
+
+		// Use the largest GPU instance profile of each model to fill its GPU.
+		p := model.GpuInstanceProfiles[len(model.GpuInstanceProfiles)-1]
+		migDevices := types.MigConfig{p.Name: p.Count}
+		gpus = append(gpus, sim.GPUSpec{Model: name, MigEnabled: true, MigDevices: migDevices})
+
+		deviceID := types.NewDeviceIDFromPacked(model.Config().PciDeviceId)
+		expected[deviceID.String()] = migDevices
+	}
+
+	spec, err := ExportMigConfigs(&Context{
+		Flags: &Flags{ConfigLabel: "current"},
+		Nvml:  simtest.NewNvml(t, gpus...),
+	})
+	require.Nil(t, err, "Unexpected failure from ExportMigConfigs")
+
+	exported := make(map[string]types.MigConfig)
+	for _, s := range spec.MigConfigs["current"] {
+		require.True(t, s.MigEnabled)
+		switch filter := s.DeviceFilter.(type) {
+		case string:
+			exported[filter] = s.MigDevices
+		case []string:
+			for _, f := range filter {
+				exported[f] = s.MigDevices
+			}
+		}
+	}
+	require.Equal(t, expected, exported)
+}
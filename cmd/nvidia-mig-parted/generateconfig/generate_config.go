@@ -26,6 +26,8 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/NVIDIA/mig-parted/pkg/mig/builder"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
 )
 
 var log = logrus.New()
@@ -40,8 +42,16 @@ const (
 )
 
 type Flags struct {
-	OutputFile   string
-	OutputFormat string
+	OutputFile       string
+	OutputFormat     string
+	Enumerate        bool
+	MinSlices        int
+	IncludeProfiles  []string
+	BaseProfilesOnly bool
+	QuirksFile       string
+	FromFile         string
+	UserConfigFiles  []string
+	Precedence       string
 }
 
 func BuildCommand() *cli.Command {
@@ -71,6 +81,55 @@ func BuildCommand() *cli.Command {
 			Value:       YAMLFormat,
 			Sources:     cli.EnvVars("MIG_PARTED_OUTPUT_FORMAT"),
 		},
+		&cli.BoolFlag{
+			Name:        "enumerate",
+			Usage:       "Also generate a config for every maximal combination of MIG profiles on each device type",
+			Destination: &generateConfigFlags.Enumerate,
+			Sources:     cli.EnvVars("MIG_PARTED_ENUMERATE"),
+		},
+		&cli.IntFlag{
+			Name:        "min-slices",
+			Usage:       "Only use MIG profiles with at least this many GPU slices in enumerated configs",
+			Destination: &generateConfigFlags.MinSlices,
+			Value:       0,
+		},
+		&cli.StringSliceFlag{
+			Name:        "include-profile",
+			Usage:       "Only keep enumerated configs that include this MIG profile (may be repeated)",
+			Destination: &generateConfigFlags.IncludeProfiles,
+		},
+		&cli.BoolFlag{
+			Name:        "base-profiles-only",
+			Usage:       "Only use MIG profiles without attributes (e.g. +me, +gfx) in enumerated configs",
+			Destination: &generateConfigFlags.BaseProfilesOnly,
+		},
+		&cli.StringFlag{
+			Name:        "quirks-file",
+			Usage:       "Path to a file with quirks correcting the MIG profiles discovered on some GPUs, in addition to the built-in ones",
+			Destination: &generateConfigFlags.QuirksFile,
+			Value:       "",
+			Sources:     cli.EnvVars("MIG_PARTED_QUIRKS_FILE"),
+		},
+		&cli.StringFlag{
+			Name:        "from",
+			Usage:       "Generate the MIG configs from the profiles saved by 'discover --save' instead of those of the GPUs of the node",
+			Destination: &generateConfigFlags.FromFile,
+			Value:       "",
+			Sources:     cli.EnvVars("MIG_PARTED_DISCOVERY_FILE"),
+		},
+		&cli.StringSliceFlag{
+			Name:        "user-config-file",
+			Usage:       "Path to a file with MIG configs to add to the generated ones (may be repeated, later files take precedence)",
+			Destination: &generateConfigFlags.UserConfigFiles,
+			Sources:     cli.EnvVars("MIG_PARTED_USER_CONFIG_FILES"),
+		},
+		&cli.StringFlag{
+			Name:        "config-precedence",
+			Usage:       "Which MIG config to keep when a user-defined and a generated one have the same name [user | generated]",
+			Destination: &generateConfigFlags.Precedence,
+			Value:       string(builder.PrecedenceUser),
+			Sources:     cli.EnvVars("MIG_PARTED_CONFIG_PRECEDENCE"),
+		},
 	}
 
 	return &generateConfig
@@ -83,6 +142,13 @@ func runGenerateConfig(_ context.Context, c *cli.Command, f *Flags) error {
 		return err
 	}
 
+	if f.QuirksFile != "" {
+		err := discovery.LoadQuirksFile(f.QuirksFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	writer := io.Writer(os.Stdout)
 	if f.OutputFile != "" {
 		file, err := os.Create(f.OutputFile)
@@ -93,15 +159,42 @@ func runGenerateConfig(_ context.Context, c *cli.Command, f *Flags) error {
 		writer = file
 	}
 
+	var opts []builder.Option
+	if f.FromFile != "" {
+		snapshot, err := discovery.ParseSnapshotFile(f.FromFile)
+		if err != nil {
+			return fmt.Errorf("error parsing discovery file %v: %w", f.FromFile, err)
+		}
+		deviceProfiles, err := snapshot.DeviceProfiles()
+		if err != nil {
+			return fmt.Errorf("error reading discovery file %v: %w", f.FromFile, err)
+		}
+		opts = append(opts, builder.WithDeviceProfiles(deviceProfiles))
+	}
+	if f.Enumerate {
+		opts = append(opts, builder.WithEnumeration(builder.EnumerateFilter{
+			MinSlices:        f.MinSlices,
+			IncludeProfiles:  f.IncludeProfiles,
+			BaseProfilesOnly: f.BaseProfilesOnly,
+		}))
+	}
+	if len(f.UserConfigFiles) > 0 {
+		opt, err := userConfigsOption(f.UserConfigFiles, f.Precedence)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, opt)
+	}
+
 	var output []byte
 	switch f.OutputFormat {
 	case YAMLFormat:
-		output, err = builder.GenerateConfigYAML()
+		output, err = builder.GenerateConfigYAML(opts...)
 		if err != nil {
 			return fmt.Errorf("error generating MIG config: %w", err)
 		}
 	case JSONFormat:
-		output, err = builder.GenerateConfigJSON()
+		output, err = builder.GenerateConfigJSON(opts...)
 		if err != nil {
 			return fmt.Errorf("error generating MIG config: %w", err)
 		}
@@ -114,6 +207,23 @@ func runGenerateConfig(_ context.Context, c *cli.Command, f *Flags) error {
 	return nil
 }
 
+// userConfigsOption parses the user config files to overlay on the generated configs.
+func userConfigsOption(files []string, precedence string) (builder.Option, error) {
+	p, err := builder.ParsePrecedence(precedence)
+	if err != nil {
+		return nil, err
+	}
+	var userConfigs []*builder.UserConfigs
+	for _, file := range files {
+		uc, err := builder.ParseUserConfigsFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing user config file %v: %w", file, err)
+		}
+		userConfigs = append(userConfigs, uc)
+	}
+	return builder.WithUserConfigs(p, userConfigs...), nil
+}
+
 func checkFlags(f *Flags) error {
 	switch f.OutputFormat {
 	case JSONFormat:
@@ -121,5 +231,22 @@ func checkFlags(f *Flags) error {
 	default:
 		return fmt.Errorf("unrecognized 'output-format': %s", f.OutputFormat)
 	}
+	if !f.Enumerate && (f.MinSlices != 0 || len(f.IncludeProfiles) > 0 || f.BaseProfilesOnly) {
+		return fmt.Errorf("'min-slices', 'include-profile' and 'base-profiles-only' require 'enumerate'")
+	}
+	if f.FromFile != "" && f.QuirksFile != "" {
+		return fmt.Errorf("'quirks-file' cannot be used with 'from': quirks are applied when running 'discover'")
+	}
+	if _, err := builder.ParsePrecedence(f.Precedence); err != nil {
+		return fmt.Errorf("invalid 'config-precedence': %w", err)
+	}
+	if f.MinSlices < 0 {
+		return fmt.Errorf("invalid 'min-slices': %d", f.MinSlices)
+	}
+	for _, profile := range f.IncludeProfiles {
+		if err := types.AssertValidMigProfileFormat(profile); err != nil {
+			return fmt.Errorf("invalid 'include-profile' '%s': %w", profile, err)
+		}
+	}
 	return nil
 }
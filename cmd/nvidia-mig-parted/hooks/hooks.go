@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	hooks "github.com/NVIDIA/mig-parted/api/hooks/v1"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/apply"
+)
+
+var log = logrus.New()
+
+func GetLogger() *logrus.Logger {
+	return log
+}
+
+type Flags struct {
+	HooksFile string
+}
+
+func BuildCommand() *cli.Command {
+	// Create a flags struct to hold our flags
+	hooksFlags := Flags{}
+
+	// Create the 'hooks' command and its subcommands
+	hooksCommand := cli.Command{}
+	hooksCommand.Name = "hooks"
+	hooksCommand.Usage = "Inspect the hooks run when applying a MIG configuration"
+
+	validate := cli.Command{}
+	validate.Name = "validate"
+	validate.Usage = "Validate a hooks file"
+	validate.Action = func(_ context.Context, c *cli.Command) error {
+		return validateWrapper(c, &hooksFlags)
+	}
+
+	list := cli.Command{}
+	list.Name = "list"
+	list.Usage = "List the hooks that will be run at each stage of applying a MIG configuration"
+	list.Action = func(_ context.Context, c *cli.Command) error {
+		return listWrapper(c, &hooksFlags)
+	}
+
+	// Setup the flags for the subcommands
+	validate.Flags = []cli.Flag{
+		hooksFileFlag(&hooksFlags),
+	}
+	list.Flags = []cli.Flag{
+		hooksFileFlag(&hooksFlags),
+	}
+
+	hooksCommand.Commands = []*cli.Command{
+		&validate,
+		&list,
+	}
+
+	return &hooksCommand
+}
+
+func hooksFileFlag(f *Flags) cli.Flag {
+	return &cli.StringFlag{
+		Name:        "hooks-file",
+		Aliases:     []string{"k"},
+		Usage:       "Path to the hooks file",
+		Destination: &f.HooksFile,
+		Sources:     cli.EnvVars("MIG_PARTED_HOOKS_FILE"),
+	}
+}
+
+func CheckFlags(f *Flags) error {
+	var missing []string
+	if f.HooksFile == "" {
+		missing = append(missing, "hooks-file")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required flags '%v'", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func validateWrapper(c *cli.Command, f *Flags) error {
+	err := CheckFlags(f)
+	if err != nil {
+		_ = cli.ShowSubcommandHelp(c)
+		return err
+	}
+
+	log.Debugf("Parsing Hooks file...")
+	_, err = apply.ParseHooksFile(f.HooksFile)
+	if err != nil {
+		return fmt.Errorf("error parsing hooks file: %v", err)
+	}
+
+	fmt.Println("Hooks file is valid")
+	return nil
+}
+
+func listWrapper(c *cli.Command, f *Flags) error {
+	err := CheckFlags(f)
+	if err != nil {
+		_ = cli.ShowSubcommandHelp(c)
+		return err
+	}
+
+	log.Debugf("Parsing Hooks file...")
+	spec, err := apply.ParseHooksFile(f.HooksFile)
+	if err != nil {
+		return fmt.Errorf("error parsing hooks file: %v", err)
+	}
+
+	for _, name := range hooks.KnownHooks {
+		fmt.Printf("%v:\n", name)
+		if len(spec.Hooks[name]) == 0 {
+			fmt.Printf("  (none)\n")
+			continue
+		}
+		for i, hook := range spec.Hooks[name] {
+			fmt.Printf("  [%d] %v\n", i, describeHook(&hook))
+		}
+	}
+
+	return nil
+}
+
+// describeHook returns a one-line, human readable description of what a hook does.
+func describeHook(h *hooks.HookSpec) string {
+	var desc string
+	switch h.GetType() {
+	case hooks.CommandHookType:
+		desc = fmt.Sprintf("command: %v", strings.Join(append([]string{h.Command}, quoteArgs(h.Args)...), " "))
+		if h.Workdir != "" {
+			desc += fmt.Sprintf(" (workdir: %v)", h.Workdir)
+		}
+	case hooks.SystemdStopHookType:
+		desc = fmt.Sprintf("systemd-stop: %v", strings.Join(h.Services, ", "))
+	case hooks.SystemdStartHookType:
+		services := "all previously stopped services"
+		if len(h.Services) > 0 {
+			services = strings.Join(h.Services, ", ")
+		}
+		desc = fmt.Sprintf("systemd-start: %v", services)
+		if h.NoBlock {
+			desc += " (no-block)"
+		}
+	case hooks.WaitNoGPUProcessesHookType:
+		timeout, _ := h.GetTimeout(hooks.DefaultWaitNoGPUProcessesTimeout)
+		desc = fmt.Sprintf("wait-no-gpu-processes (timeout: %v)", timeout)
+	case hooks.HTTPHookType:
+		method := h.Method
+		if method == "" {
+			method = "POST"
+		}
+		desc = fmt.Sprintf("http: %v %v", strings.ToUpper(method), h.URL)
+	default:
+		desc = h.GetType()
+	}
+
+	if h.When != "" {
+		desc += fmt.Sprintf(" [when: %v]", h.When)
+	}
+	if h.GetOnFailure() == hooks.FailurePolicyIgnore {
+		desc += " [on-failure: ignore]"
+	}
+
+	return desc
+}
+
+func quoteArgs(args []string) []string {
+	var quoted []string
+	for _, a := range args {
+		if strings.ContainsAny(a, " \t\"'") {
+			a = fmt.Sprintf("%q", a)
+		}
+		quoted = append(quoted, a)
+	}
+	return quoted
+}
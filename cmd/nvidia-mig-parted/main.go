@@ -18,7 +18,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
@@ -26,16 +28,27 @@ import (
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/apply"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/assert"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/checkpoint"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/config"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/convert"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/discover"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/export"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/generateconfig"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/hooks"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/pack"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/restore"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/schema"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
 	"github.com/NVIDIA/mig-parted/internal/info"
+	"github.com/NVIDIA/mig-parted/pkg/mig/mode"
 )
 
 // Flags holds variables that represent the set of top level flags that can be passed to the mig-parted CLI.
 type Flags struct {
-	Debug bool
+	Debug               bool
+	Backend             string
+	PciBootTimeout      time.Duration
+	PciBootPollInterval time.Duration
+	PciBootResetRetries int
 }
 
 func main() {
@@ -58,6 +71,34 @@ func main() {
 			Destination: &flags.Debug,
 			Sources:     cli.EnvVars("MIG_PARTED_DEBUG"),
 		},
+		&cli.StringFlag{
+			Name:        "backend",
+			Usage:       "The GPUs to operate on: 'nvml' for the GPUs of this node, or 'sim:<file>' for a simulated node described in <file>",
+			Value:       util.NvmlBackend,
+			Destination: &flags.Backend,
+			Sources:     cli.EnvVars(util.BackendEnvVar),
+		},
+		&cli.DurationFlag{
+			Name:        "pci-boot-timeout",
+			Usage:       "How long to wait for a GPU to boot before accessing its MIG mode when the nvidia module is not loaded",
+			Value:       mode.WaitForBootTimeout,
+			Destination: &flags.PciBootTimeout,
+			Sources:     cli.EnvVars("MIG_PARTED_PCI_BOOT_TIMEOUT"),
+		},
+		&cli.DurationFlag{
+			Name:        "pci-boot-poll-interval",
+			Usage:       "How often to check whether a GPU has booted when the nvidia module is not loaded",
+			Value:       mode.WaitForBootSleepInterval,
+			Destination: &flags.PciBootPollInterval,
+			Sources:     cli.EnvVars("MIG_PARTED_PCI_BOOT_POLL_INTERVAL"),
+		},
+		&cli.IntFlag{
+			Name:        "pci-boot-reset-retries",
+			Usage:       "How many times to reset a GPU that does not boot in time (and wait again) when the nvidia module is not loaded",
+			Value:       0,
+			Destination: &flags.PciBootResetRetries,
+			Sources:     cli.EnvVars("MIG_PARTED_PCI_BOOT_RESET_RETRIES"),
+		},
 	}
 
 	// Register the subcommands with the top-level CLI
@@ -65,9 +106,15 @@ func main() {
 		apply.BuildCommand(),
 		assert.BuildCommand(),
 		export.BuildCommand(),
+		discover.BuildCommand(),
 		generateconfig.BuildCommand(),
+		pack.BuildCommand(),
+		config.BuildCommand(),
 		checkpoint.BuildCommand(),
 		restore.BuildCommand(),
+		hooks.BuildCommand(),
+		convert.BuildCommand(),
+		schema.BuildCommand(),
 	}
 
 	// Set log-level for all subcommands
@@ -76,18 +123,53 @@ func main() {
 		if flags.Debug {
 			logLevel = log.DebugLevel
 		}
+		// The standard logger is used to report the output of any hooks that are run.
+		log.SetLevel(logLevel)
 		applyLog := apply.GetLogger()
 		applyLog.SetLevel(logLevel)
 		assertLog := assert.GetLogger()
 		assertLog.SetLevel(logLevel)
 		exportLog := export.GetLogger()
 		exportLog.SetLevel(logLevel)
+		discoverLog := discover.GetLogger()
+		discoverLog.SetLevel(logLevel)
 		generateConfigLog := generateconfig.GetLogger()
 		generateConfigLog.SetLevel(logLevel)
+		packLog := pack.GetLogger()
+		packLog.SetLevel(logLevel)
+		configLog := config.GetLogger()
+		configLog.SetLevel(logLevel)
 		checkpointLog := export.GetLogger()
 		checkpointLog.SetLevel(logLevel)
 		restoreLog := export.GetLogger()
 		restoreLog.SetLevel(logLevel)
+		hooksLog := hooks.GetLogger()
+		hooksLog.SetLevel(logLevel)
+		convertLog := convert.GetLogger()
+		convertLog.SetLevel(logLevel)
+		schemaLog := schema.GetLogger()
+		schemaLog.SetLevel(logLevel)
+
+		if flags.PciBootTimeout <= 0 {
+			return ctx, fmt.Errorf("invalid pci-boot-timeout '%v': must be positive", flags.PciBootTimeout)
+		}
+		if flags.PciBootPollInterval <= 0 {
+			return ctx, fmt.Errorf("invalid pci-boot-poll-interval '%v': must be positive", flags.PciBootPollInterval)
+		}
+		if flags.PciBootResetRetries < 0 {
+			return ctx, fmt.Errorf("invalid pci-boot-reset-retries '%v': must not be negative", flags.PciBootResetRetries)
+		}
+		util.SetPciMigModeManagerOptions(
+			mode.WithBootTimeout(flags.PciBootTimeout),
+			mode.WithBootPollInterval(flags.PciBootPollInterval),
+			mode.WithBootResetRetries(flags.PciBootResetRetries),
+		)
+
+		err := util.SetBackend(flags.Backend)
+		if err != nil {
+			return ctx, err
+		}
+
 		return ctx, nil
 	}
 
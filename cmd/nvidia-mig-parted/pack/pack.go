@@ -0,0 +1,244 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v2"
+
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/mig/packer"
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+)
+
+var log = logrus.New()
+
+func GetLogger() *logrus.Logger {
+	return log
+}
+
+const (
+	JSONFormat = "json"
+	YAMLFormat = "yaml"
+)
+
+type Flags struct {
+	RequirementsFile string
+	GPUs             []string
+	ConfigName       string
+	OutputFile       string
+	OutputFormat     string
+	AllowPartial     bool
+	QuirksFile       string
+}
+
+func BuildCommand() *cli.Command {
+	packFlags := Flags{}
+
+	pack := cli.Command{}
+	pack.Name = "pack"
+	pack.Usage = "Generate a MIG configuration providing the MIG devices needed by a set of workload requirements"
+	pack.Action = func(ctx context.Context, c *cli.Command) error {
+		return runPack(ctx, c, &packFlags)
+	}
+
+	pack.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "requirements-file",
+			Aliases:     []string{"r"},
+			Usage:       "Path to the file with the counts and minimum memory or compute of the MIG devices needed",
+			Destination: &packFlags.RequirementsFile,
+			Value:       "",
+			Sources:     cli.EnvVars("MIG_PARTED_REQUIREMENTS_FILE"),
+		},
+		&cli.StringSliceFlag{
+			Name:        "gpu",
+			Usage:       "Pack for GPUs of this model instead of those of the node, as <model>[=<count>] (may be repeated)",
+			Destination: &packFlags.GPUs,
+		},
+		&cli.StringFlag{
+			Name:        "config-name",
+			Usage:       "The name of the generated MIG config",
+			Destination: &packFlags.ConfigName,
+			Value:       packer.DefaultConfigName,
+		},
+		&cli.StringFlag{
+			Name:        "output-file",
+			Aliases:     []string{"f"},
+			Usage:       "Output file path (default: stdout)",
+			Destination: &packFlags.OutputFile,
+			Value:       "",
+			Sources:     cli.EnvVars("MIG_PARTED_OUTPUT_FILE"),
+		},
+		&cli.StringFlag{
+			Name:        "output-format",
+			Aliases:     []string{"o"},
+			Usage:       "Format for the output [json | yaml]",
+			Destination: &packFlags.OutputFormat,
+			Value:       YAMLFormat,
+			Sources:     cli.EnvVars("MIG_PARTED_OUTPUT_FORMAT"),
+		},
+		&cli.BoolFlag{
+			Name:        "allow-partial",
+			Usage:       "Output a MIG configuration even if some requirements cannot be met",
+			Destination: &packFlags.AllowPartial,
+		},
+		&cli.StringFlag{
+			Name:        "quirks-file",
+			Usage:       "Path to a file with quirks correcting the MIG profiles discovered on some GPUs, in addition to the built-in ones",
+			Destination: &packFlags.QuirksFile,
+			Value:       "",
+			Sources:     cli.EnvVars("MIG_PARTED_QUIRKS_FILE"),
+		},
+	}
+
+	return &pack
+}
+
+func runPack(_ context.Context, c *cli.Command, f *Flags) error {
+	err := checkFlags(f)
+	if err != nil {
+		_ = cli.ShowSubcommandHelp(c)
+		return err
+	}
+
+	if f.QuirksFile != "" {
+		err := discovery.LoadQuirksFile(f.QuirksFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	requirements, err := packer.ParseRequirementsFile(f.RequirementsFile)
+	if err != nil {
+		return fmt.Errorf("error parsing requirements file: %w", err)
+	}
+
+	deviceProfiles, err := discoverProfiles(f.GPUs)
+	if err != nil {
+		return fmt.Errorf("error discovering MIG profiles: %w", err)
+	}
+
+	result, err := packer.Pack(deviceProfiles, requirements, f.ConfigName)
+	if err != nil {
+		return fmt.Errorf("error packing requirements: %w", err)
+	}
+
+	for _, a := range result.Assignments {
+		log.Infof("GPU %d: %d %s device(s) for %s", a.Device, a.Count, a.Profile, a.Requirement)
+	}
+	log.Infof("Using %d memory slice(s), %d of them wasted on larger profiles than required", result.UsedSlices, result.WastedSlices)
+	for _, u := range result.Unmet {
+		log.Warnf("Unable to provide %d instance(s) for %s: %s", u.Missing, u.Requirement, u.Reason)
+	}
+	if len(result.Unmet) > 0 && !f.AllowPartial {
+		return fmt.Errorf("unable to meet %d requirement(s)", len(result.Unmet))
+	}
+
+	var output []byte
+	switch f.OutputFormat {
+	case YAMLFormat:
+		output, err = yaml.Marshal(result.Spec)
+	case JSONFormat:
+		output, err = json.MarshalIndent(result.Spec, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("error marshaling MIG config: %w", err)
+	}
+
+	writer := io.Writer(os.Stdout)
+	if f.OutputFile != "" {
+		file, err := os.Create(f.OutputFile)
+		if err != nil {
+			return fmt.Errorf("error creating output file: %w", err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	if _, err := writer.Write(output); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+
+	return nil
+}
+
+// discoverProfiles discovers the MIG profiles of the GPUs of the node, or of a
+// simulated node with the GPUs given as <model>[=<count>] when packing offline.
+func discoverProfiles(gpus []string) (discovery.DeviceProfiles, error) {
+	if len(gpus) == 0 {
+		return discovery.DiscoverMIGProfiles()
+	}
+
+	spec := &sim.NodeSpec{Version: sim.Version}
+	for _, gpu := range gpus {
+		gpuSpec, err := parseGPU(gpu)
+		if err != nil {
+			return nil, err
+		}
+		spec.GPUs = append(spec.GPUs, *gpuSpec)
+	}
+	err := spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := sim.New(spec, "")
+	if err != nil {
+		return nil, fmt.Errorf("error creating GPUs: %w", err)
+	}
+	return discovery.DiscoverMIGProfilesFrom(node.Nvml())
+}
+
+// parseGPU parses a GPU model and optional count given as <model>[=<count>].
+func parseGPU(gpu string) (*sim.GPUSpec, error) {
+	model, countStr, hasCount := strings.Cut(gpu, "=")
+	count := 1
+	if hasCount {
+		var err error
+		count, err = strconv.Atoi(countStr)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid 'gpu' '%s': count must be a positive integer", gpu)
+		}
+	}
+	return &sim.GPUSpec{Model: model, Count: count}, nil
+}
+
+func checkFlags(f *Flags) error {
+	if f.RequirementsFile == "" {
+		return fmt.Errorf("missing 'requirements-file'")
+	}
+	if f.ConfigName == "" {
+		return fmt.Errorf("missing 'config-name'")
+	}
+	switch f.OutputFormat {
+	case JSONFormat:
+	case YAMLFormat:
+	default:
+		return fmt.Errorf("unrecognized 'output-format': %s", f.OutputFormat)
+	}
+	return nil
+}
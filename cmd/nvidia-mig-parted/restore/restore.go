@@ -27,11 +27,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 
-	"github.com/NVIDIA/go-nvml/pkg/nvml"
-
 	checkpoint "github.com/NVIDIA/mig-parted/api/checkpoint/v1"
 	hooks "github.com/NVIDIA/mig-parted/api/hooks/v1"
 	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/apply"
+	"github.com/NVIDIA/mig-parted/cmd/nvidia-mig-parted/util"
 	"github.com/NVIDIA/mig-parted/pkg/mig/state"
 	"github.com/NVIDIA/mig-parted/pkg/types"
 )
@@ -64,8 +63,8 @@ func BuildCommand() *cli.Command {
 	restore := cli.Command{}
 	restore.Name = "restore"
 	restore.Usage = "Restore MIG state from a checkpoint file"
-	restore.Action = func(_ context.Context, c *cli.Command) error {
-		return restoreWrapper(c, &restoreFlags)
+	restore.Action = func(ctx context.Context, c *cli.Command) error {
+		return restoreWrapper(ctx, c, &restoreFlags)
 	}
 
 	// Setup the flags for this command
@@ -155,7 +154,7 @@ func (c *Context) ApplyMigConfig() error {
 	return c.MigStateManager.RestoreConfig(c.MigState)
 }
 
-func restoreWrapper(c *cli.Command, f *Flags) error {
+func restoreWrapper(ctx context.Context, c *cli.Command, f *Flags) error {
 	err := CheckFlags(f)
 	if err != nil {
 		_ = cli.ShowSubcommandHelp(c)
@@ -177,12 +176,25 @@ func restoreWrapper(c *cli.Command, f *Flags) error {
 		}
 	}
 
+	nvmlLib := util.NewNvml()
+
+	actions := apply.NewHookActions(ctx, nvmlLib, "")
+	defer actions.Close()
+
 	context := Context{
 		Command:         c,
 		Flags:           f,
-		Hooks:           apply.NewApplyHooks(hooksSpec.Hooks),
+		Hooks:           apply.NewApplyHooks(hooksSpec.Hooks, actions),
 		MigState:        &checkpoint.MigState,
-		MigStateManager: state.NewMigStateManager(nvml.New()),
+		MigStateManager: state.NewMigStateManager(nvmlLib),
+	}
+
+	if hooksSpec.Hooks.HasConditions() {
+		log.Debugf("Checking which changes will be restored...")
+		err = actions.Prepare(&context, f.ModeOnly)
+		if err != nil {
+			return fmt.Errorf("error checking which changes will be restored: %v", err)
+		}
 	}
 
 	err = apply.ApplyMigConfigWithHooks(log, c, f.ModeOnly, context.Hooks, &context)
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	checkpoint "github.com/NVIDIA/mig-parted/api/checkpoint/v1"
+	hooks "github.com/NVIDIA/mig-parted/api/hooks/v1"
+	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/internal/jsonschema"
+)
+
+var log = logrus.New()
+
+func GetLogger() *logrus.Logger {
+	return log
+}
+
+// schemas maps each file format to the function generating its JSON Schema.
+var schemas = map[string]func() *jsonschema.Schema{
+	"config":     v1.Schema,
+	"hooks":      hooks.Schema,
+	"checkpoint": checkpoint.Schema,
+}
+
+type Flags struct {
+	Format string
+}
+
+func BuildCommand() *cli.Command {
+	// Create a flags struct to hold our flags
+	schemaFlags := Flags{}
+
+	// Create the 'schema' command
+	schema := cli.Command{}
+	schema.Name = "schema"
+	schema.Usage = "Print the JSON Schema of the config, hooks or checkpoint file format"
+	schema.ArgsUsage = fmt.Sprintf("<%v>", strings.Join(formats(), " | "))
+	schema.Description = "The schema can be used by editors to autocomplete and validate files, " +
+		"e.g. through a '# yaml-language-server: $schema=<file>' comment at the top of a YAML file."
+	schema.Action = func(_ context.Context, c *cli.Command) error {
+		schemaFlags.Format = c.Args().First()
+		return schemaWrapper(c, &schemaFlags)
+	}
+
+	return &schema
+}
+
+func CheckFlags(f *Flags) error {
+	if f.Format == "" {
+		return fmt.Errorf("missing required argument: one of %v", formats())
+	}
+	if _, exists := schemas[f.Format]; !exists {
+		return fmt.Errorf("unrecognized file format '%v': expected one of %v", f.Format, formats())
+	}
+	return nil
+}
+
+func schemaWrapper(c *cli.Command, f *Flags) error {
+	err := CheckFlags(f)
+	if err != nil {
+		_ = cli.ShowSubcommandHelp(c)
+		return err
+	}
+
+	log.Debugf("Generating JSON Schema of the %v file format...", f.Format)
+	return writeSchema(os.Stdout, schemas[f.Format]())
+}
+
+func writeSchema(w io.Writer, schema *jsonschema.Schema) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(schema); err != nil {
+		return fmt.Errorf("error writing JSON Schema: %w", err)
+	}
+	return nil
+}
+
+func formats() []string {
+	return slices.Sorted(maps.Keys(schemas))
+}
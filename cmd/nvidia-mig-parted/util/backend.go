@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+)
+
+const (
+	// BackendEnvVar is the environment variable used to select the backend of the mig-parted CLI.
+	BackendEnvVar = "MIG_PARTED_BACKEND"
+	// NvmlBackend selects the NVML library and PCI bus of the node (the default).
+	NvmlBackend = "nvml"
+	// SimBackendPrefix selects a simulated node described in the file following the prefix.
+	SimBackendPrefix = "sim:"
+)
+
+var simNode *sim.Node
+
+// SetBackend selects the NVML library and PCI bus used by all subsequent operations.
+// The backend is either 'nvml' or 'sim:<file>', where <file> describes a simulated node.
+func SetBackend(backend string) error {
+	switch {
+	case backend == "" || backend == NvmlBackend:
+		simNode = nil
+		return nil
+	case strings.HasPrefix(backend, SimBackendPrefix):
+		file := strings.TrimPrefix(backend, SimBackendPrefix)
+		if file == "" {
+			return fmt.Errorf("invalid backend '%v': missing node spec file", backend)
+		}
+		node, err := sim.Load(file)
+		if err != nil {
+			return fmt.Errorf("error loading simulated node from %v: %v", file, err)
+		}
+		simNode = node
+		return nil
+	}
+	return fmt.Errorf("invalid backend '%v': expected '%v' or '%v<file>'", backend, NvmlBackend, SimBackendPrefix)
+}
+
+// IsSimulated returns whether the selected backend is a simulated node.
+func IsSimulated() bool {
+	return simNode != nil
+}
+
+// NewNvml returns the NVML library of the selected backend.
+func NewNvml() nvml.Interface {
+	if simNode != nil {
+		return simNode.Nvml()
+	}
+	return nvml.New()
+}
+
+// NewNvpci returns the PCI bus of the selected backend.
+func NewNvpci() nvpci.Interface {
+	if simNode != nil {
+		return simNode.Nvpci()
+	}
+	return nvpci.New()
+}
@@ -21,6 +21,7 @@ import (
 	"os/exec"
 	"strings"
 
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
@@ -38,6 +39,20 @@ func GetGPUDeviceIDs() ([]types.DeviceID, error) {
 	return pciGetGPUDeviceIDs()
 }
 
+// GetGPUDevices returns the attributes of each GPU that device filters match
+// on. They are read through NVML when the nvidia module is loaded, and looked
+// up by PCI device ID in the built-in GPU models otherwise.
+func GetGPUDevices() ([]types.DeviceInfo, error) {
+	nvidiaModuleLoaded, err := IsNvidiaModuleLoaded()
+	if err != nil {
+		return nil, fmt.Errorf("error checking if nvidia module loaded: %v", err)
+	}
+	if nvidiaModuleLoaded {
+		return nvmlGetGPUDevices()
+	}
+	return pciGetGPUDevices()
+}
+
 func ResetAllGPUs() (string, error) {
 	nvidiaModuleLoaded, err := IsNvidiaModuleLoaded()
 	if err != nil {
@@ -50,8 +65,7 @@ func ResetAllGPUs() (string, error) {
 }
 
 func pciVisitGPUs(visit func(*nvpci.NvidiaPCIDevice) error) error {
-	nvpci := nvpci.New()
-	gpus, err := nvpci.GetGPUs()
+	gpus, err := NewNvpci().GetGPUs()
 	if err != nil {
 		return fmt.Errorf("error enumerating GPUs: %v", err)
 	}
@@ -64,6 +78,31 @@ func pciVisitGPUs(visit func(*nvpci.NvidiaPCIDevice) error) error {
 	return nil
 }
 
+// GetNvmlGPUDeviceIDs returns the device IDs of the GPUs of an initialized
+// NVML library, in the order of their NVML index.
+func GetNvmlGPUDeviceIDs(nvmlLib nvml.Interface) ([]types.DeviceID, error) {
+	count, ret := nvmlLib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("error getting device count: %v", ret)
+	}
+
+	var ids []types.DeviceID
+	for i := range count {
+		device, ret := nvmlLib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("error getting device handle: %v", ret)
+		}
+		pciInfo, ret := device.GetPciInfo()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("error getting PCI info of device %d: %v", i, ret)
+		}
+		id := types.NewDeviceIDFromPacked(pciInfo.PciDeviceId)
+		subsystemID := types.NewDeviceIDFromPacked(pciInfo.PciSubSystemId)
+		ids = append(ids, types.NewDeviceIDWithSubsystem(id.Device, id.Vendor, subsystemID.Device, subsystemID.Vendor))
+	}
+	return ids, nil
+}
+
 func pciGetGPUDeviceIDs() ([]types.DeviceID, error) {
 	var ids []types.DeviceID
 	err := pciVisitGPUs(func(gpu *nvpci.NvidiaPCIDevice) error {
@@ -77,7 +116,7 @@ func pciGetGPUDeviceIDs() ([]types.DeviceID, error) {
 }
 
 func nvmlGetGPUDeviceIDs() ([]types.DeviceID, error) {
-	nvmlLib := nvml.New()
+	nvmlLib := NewNvml()
 	err := NvmlInit(nvmlLib)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing NVML: %v", err)
@@ -100,6 +139,67 @@ func nvmlGetGPUDeviceIDs() ([]types.DeviceID, error) {
 	return ids, nil
 }
 
+func pciGetGPUDevices() ([]types.DeviceInfo, error) {
+	ids, err := pciGetGPUDeviceIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []types.DeviceInfo
+	for _, id := range ids {
+		devices = append(devices, lookupDeviceInfo(id))
+	}
+	return devices, nil
+}
+
+func nvmlGetGPUDevices() ([]types.DeviceInfo, error) {
+	nvmlLib := NewNvml()
+	err := NvmlInit(nvmlLib)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing NVML: %v", err)
+	}
+	defer TryNvmlShutdown(nvmlLib)
+
+	deviceLib := nvdev.New(nvmlLib)
+
+	var devices []types.DeviceInfo
+	err = pciVisitGPUs(func(gpu *nvpci.NvidiaPCIDevice) error {
+		handle, ret := nvmlLib.DeviceGetHandleByPciBusId(gpu.Address)
+		if ret != nvml.SUCCESS {
+			return nil
+		}
+
+		// Attributes NVML fails to report fall back to the built-in GPU models.
+		device := lookupDeviceInfo(types.NewDeviceIDWithSubsystem(gpu.Device, gpu.Vendor, gpu.SubsystemDevice, gpu.SubsystemVendor))
+		if name, ret := handle.GetName(); ret == nvml.SUCCESS {
+			device.Name = name
+		}
+		if memory, ret := handle.GetMemoryInfo(); ret == nvml.SUCCESS {
+			device.MemoryMB = memory.Total / (1024 * 1024)
+		}
+		if dev, err := deviceLib.NewDevice(handle); err == nil {
+			if architecture, err := dev.GetArchitectureAsString(); err == nil {
+				device.Architecture = architecture
+			}
+		}
+
+		devices = append(devices, device)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// lookupDeviceInfo resolves the attributes of a GPU from its PCI device ID
+// through the known devices, leaving them empty for unknown GPUs.
+func lookupDeviceInfo(id types.DeviceID) types.DeviceInfo {
+	device, _ := types.LookupDeviceInfo(id)
+	device.ID = id
+	return device
+}
+
 func pciResetAllGPUs() (string, error) {
 	err := pciVisitGPUs(func(gpu *nvpci.NvidiaPCIDevice) error {
 		err := gpu.Reset()
@@ -112,7 +212,7 @@ func pciResetAllGPUs() (string, error) {
 }
 
 func nvmlGetGPUPciBusIds() ([]string, error) {
-	nvmlLib := nvml.New()
+	nvmlLib := NewNvml()
 	err := NvmlInit(nvmlLib)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing NVML: %v", err)
@@ -150,6 +250,10 @@ func nvmlResetAllGPUs() (string, error) {
 		return "No GPUs to reset...", nil
 	}
 
+	if IsSimulated() {
+		return fmt.Sprintf("Skipping reset of simulated GPUs %v", strings.Join(pciBusIDs, ",")), nil
+	}
+
 	cmd := exec.Command("nvidia-smi", "-r", "-i", strings.Join(pciBusIDs, ",")) //nolint:gosec
 	output, err := cmd.CombinedOutput()
 	return string(output), err
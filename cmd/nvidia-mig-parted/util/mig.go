@@ -25,13 +25,21 @@ import (
 	"github.com/NVIDIA/mig-parted/pkg/mig/mode"
 )
 
+var pciMigModeManagerOptions []mode.PciOption
+
+// SetPciMigModeManagerOptions sets the options used whenever a PCI-based MIG mode manager
+// is created (i.e. when the nvidia module is not loaded or NVML is unsupported).
+func SetPciMigModeManagerOptions(opts ...mode.PciOption) {
+	pciMigModeManagerOptions = opts
+}
+
 func NewMigModeManager(nvmlLib nvml.Interface) (mode.Manager, error) {
 	nvidiaModuleLoaded, err := IsNvidiaModuleLoaded()
 	if err != nil {
 		return nil, fmt.Errorf("error checking if nvidia module loaded: %v", err)
 	}
 	if !nvidiaModuleLoaded {
-		return mode.NewPciMigModeManager(), nil
+		return mode.NewPciMigModeManager(pciMigModeManagerOptions...), nil
 	}
 
 	nvmlSupported, err := IsNVMLVersionSupported(nvmlLib)
@@ -39,7 +47,7 @@ func NewMigModeManager(nvmlLib nvml.Interface) (mode.Manager, error) {
 		return nil, fmt.Errorf("error checking NVML version: %v", err)
 	}
 	if !nvmlSupported {
-		return mode.NewPciMigModeManager(), nil
+		return mode.NewPciMigModeManager(pciMigModeManagerOptions...), nil
 	}
 
 	return mode.NewNvmlMigModeManager(nvmlLib), nil
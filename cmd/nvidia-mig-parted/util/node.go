@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+const (
+	// HostRootEnvVar is the environment variable holding the path at which the
+	// root filesystem of the host is mounted when running in a container.
+	HostRootEnvVar = "MIG_PARTED_HOST_ROOT"
+	// NodeNameEnvVar is the environment variable holding the name of the node
+	// when running in a container, whose own hostname is not the node's.
+	NodeNameEnvVar = "NODE_NAME"
+)
+
+var (
+	osReleaseFile     = "/etc/os-release"
+	kernelVersionFile = "/proc/sys/kernel/osrelease"
+)
+
+// GetHostname returns the name of this node, taken from NODE_NAME if set
+// (e.g. from the downward API of a pod) and from the kernel otherwise.
+func GetHostname() (string, error) {
+	if name := os.Getenv(NodeNameEnvVar); name != "" {
+		return name, nil
+	}
+	return os.Hostname()
+}
+
+// GetNodeInfo returns the facts about this node that node selectors match on,
+// including the key/value pairs of a facts file (if any) in the format of
+// /etc/os-release. The os-release of the node is read under hostRoot, the
+// path at which its root filesystem is mounted ("/" if empty), as that of a
+// container describes its image rather than the node.
+func GetNodeInfo(hostRoot, factsFile string) (types.NodeInfo, error) {
+	var node types.NodeInfo
+	var err error
+
+	node.Hostname, err = GetHostname()
+	if err != nil {
+		return node, fmt.Errorf("error getting hostname: %v", err)
+	}
+
+	osRelease := filepath.Join("/", hostRoot, osReleaseFile)
+	node.OSRelease, err = readKeyValueFile(osRelease)
+	if err != nil && !os.IsNotExist(err) {
+		return node, fmt.Errorf("error reading %v: %v", osRelease, err)
+	}
+
+	kernelVersion, err := os.ReadFile(kernelVersionFile)
+	if err != nil && !os.IsNotExist(err) {
+		return node, fmt.Errorf("error reading %v: %v", kernelVersionFile, err)
+	}
+	node.KernelVersion = strings.TrimSpace(string(kernelVersion))
+
+	deviceIDs, err := GetGPUDeviceIDs()
+	if err != nil {
+		return node, fmt.Errorf("error enumerating GPUs: %v", err)
+	}
+	node.GPUCount = len(deviceIDs)
+
+	if factsFile != "" {
+		node.Facts, err = readKeyValueFile(factsFile)
+		if err != nil {
+			return node, fmt.Errorf("error reading facts file: %v", err)
+		}
+	}
+
+	return node, nil
+}
+
+// readKeyValueFile reads a file of KEY=value lines, where values may be
+// quoted and lines starting with '#' are comments.
+func readKeyValueFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%v:%d: expected 'KEY=value'", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
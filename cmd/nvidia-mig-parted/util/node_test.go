@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHostname(t *testing.T) {
+	kernelHostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	t.Setenv(NodeNameEnvVar, "")
+	hostname, err := GetHostname()
+	require.NoError(t, err)
+	require.Equal(t, kernelHostname, hostname)
+
+	t.Setenv(NodeNameEnvVar, "gpu-node-1")
+	hostname, err = GetHostname()
+	require.NoError(t, err)
+	require.Equal(t, "gpu-node-1", hostname)
+}
+
+func TestGetNodeInfoHostRoot(t *testing.T) {
+	dir := t.TempDir()
+	nodeSpecFile := filepath.Join(dir, "node.yaml")
+	require.NoError(t, os.WriteFile(nodeSpecFile, []byte("version: v1\ngpus:\n- model: A100-SXM4-40GB\n"), 0600))
+	require.NoError(t, SetBackend(SimBackendPrefix+nodeSpecFile))
+	t.Cleanup(func() { _ = SetBackend(NvmlBackend) })
+
+	hostRoot := filepath.Join(dir, "host")
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "etc"), 0755))
+	osRelease := "# host os-release\nID=ubuntu\nVERSION_ID=\"24.04\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(hostRoot, "etc", "os-release"), []byte(osRelease), 0600))
+	t.Setenv(NodeNameEnvVar, "gpu-node-1")
+
+	node, err := GetNodeInfo(hostRoot, "")
+	require.NoError(t, err)
+	require.Equal(t, "gpu-node-1", node.Hostname)
+	require.Equal(t, map[string]string{"ID": "ubuntu", "VERSION_ID": "24.04"}, node.OSRelease)
+	require.Equal(t, 1, node.GPUCount)
+
+	// A host root without an os-release leaves its fields empty.
+	node, err = GetNodeInfo(filepath.Join(dir, "missing"), "")
+	require.NoError(t, err)
+	require.Empty(t, node.OSRelease)
+}
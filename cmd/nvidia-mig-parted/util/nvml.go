@@ -32,6 +32,9 @@ const (
 )
 
 func IsNvidiaModuleLoaded() (bool, error) {
+	if IsSimulated() {
+		return true, nil
+	}
 	modules, err := os.ReadFile("/proc/modules")
 	if err != nil {
 		return false, fmt.Errorf("unable to read /proc/modules: %v", err)
@@ -70,7 +73,7 @@ func IsNVMLVersionSupported(nvmlLib nvml.Interface) (bool, error) {
 
 func NvmlInit(nvmlLib nvml.Interface) error {
 	if nvmlLib == nil {
-		nvmlLib = nvml.New()
+		nvmlLib = NewNvml()
 	}
 	ret := nvmlLib.Init()
 	if ret != nvml.SUCCESS {
@@ -81,7 +84,7 @@ func NvmlInit(nvmlLib nvml.Interface) error {
 
 func TryNvmlShutdown(nvmlLib nvml.Interface) {
 	if nvmlLib == nil {
-		nvmlLib = nvml.New()
+		nvmlLib = NewNvml()
 	}
 	ret := nvmlLib.Shutdown()
 	if ret != nvml.SUCCESS {
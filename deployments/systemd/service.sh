@@ -36,6 +36,7 @@ if nvidia-mig-parted generate-config -f "${CONFIG_FILE}.tmp"; then
 	{
 		echo "# DO NOT EDIT: Auto-generated from hardware on every boot."
 		echo "# To use a custom config, set MIG_PARTED_CONFIG_FILE."
+		echo "# To add configs to the generated ones, set MIG_PARTED_USER_CONFIG_FILES."
 		echo ""
 		cat "${CONFIG_FILE}.tmp"
 	} > "${CONFIG_FILE}"
@@ -97,6 +97,12 @@ function nvidia-mig-manager::service::persist_config_across_reboot() {
 		if [ -n "${MIG_PARTED_CONFIG_FILE}" ]; then
 			echo "Environment=\"MIG_PARTED_CONFIG_FILE=${MIG_PARTED_CONFIG_FILE}\""
 		fi
+		if [ -n "${MIG_PARTED_USER_CONFIG_FILES}" ]; then
+			echo "Environment=\"MIG_PARTED_USER_CONFIG_FILES=${MIG_PARTED_USER_CONFIG_FILES}\""
+		fi
+		if [ -n "${MIG_PARTED_CONFIG_PRECEDENCE}" ]; then
+			echo "Environment=\"MIG_PARTED_CONFIG_PRECEDENCE=${MIG_PARTED_CONFIG_PRECEDENCE}\""
+		fi
 		if [ -n "${MIG_PARTED_HOOKS_FILE}" ]; then
 			echo "Environment=\"MIG_PARTED_HOOKS_FILE=${MIG_PARTED_HOOKS_FILE}\""
 		fi
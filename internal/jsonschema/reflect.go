@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Reflector generates a JSON Schema from Go types, following their 'json'
+// struct tags as 'encoding/json' does. Named types become definitions of the
+// root schema, and structs reject the properties they do not hold. Fields
+// whose values are parsed by a custom 'UnmarshalJSON' are described through
+// 'Fields', and the definitions generated can be refined with 'Definition'.
+type Reflector struct {
+	// Fields replaces the schema generated for a struct field, keyed by the
+	// name of the struct type and the JSON name of the field (e.g. "Spec.version").
+	Fields map[string]*Schema
+
+	defs map[string]*Schema
+}
+
+// Reflect generates the root schema of 't', holding the definitions of all
+// the named types generated so far.
+func (r *Reflector) Reflect(t reflect.Type) *Schema {
+	root := r.Schema(t)
+	root.Schema = Draft
+	root.Defs = r.defs
+	return root
+}
+
+// Schema generates the schema of 't', referring to its definition if it is a
+// named type.
+func (r *Reflector) Schema(t reflect.Type) *Schema {
+	if r.defs == nil {
+		r.defs = make(map[string]*Schema)
+	}
+	return r.schemaOf(t)
+}
+
+// Define adds the definition 'name' to the root schema, returning a reference to it.
+func (r *Reflector) Define(name string, s *Schema) *Schema {
+	if r.defs == nil {
+		r.defs = make(map[string]*Schema)
+	}
+	r.defs[name] = s
+	return Ref(name)
+}
+
+// Definition returns the definition 'name' of the root schema, or nil if it
+// has not been generated.
+func (r *Reflector) Definition(name string) *Schema {
+	return r.defs[name]
+}
+
+func (r *Reflector) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Name() == "" || t.PkgPath() == "" {
+		return r.inline(t)
+	}
+	if _, exists := r.defs[t.Name()]; !exists {
+		// Add a placeholder first, so that recursive types refer to themselves.
+		r.defs[t.Name()] = &Schema{}
+		*r.defs[t.Name()] = *r.inline(t)
+	}
+	return Ref(t.Name())
+}
+
+func (r *Reflector) inline(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}, Minimum: Number(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: r.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return r.object(t)
+	}
+	return &Schema{}
+}
+
+// object generates the schema of a struct. Fields without 'omitempty' are
+// required, and may be null if they are slices or maps.
+func (r *Reflector) object(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 Types{"object"},
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: False(),
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		omitempty := slices.Contains(strings.Split(options, ","), "omitempty")
+
+		if field, exists := r.Fields[t.Name()+"."+name]; exists {
+			s.Properties[name] = field
+		} else {
+			s.Properties[name] = r.schemaOf(f.Type)
+			if !omitempty && (f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Map) {
+				s.Properties[name] = nullable(s.Properties[name])
+			}
+		}
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// nullable allows a schema to also be null, as nil slices and maps are.
+func nullable(s *Schema) *Schema {
+	if len(s.Type) == 0 {
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+	}
+	s.Type = append(s.Type, "null")
+	return s
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Draft is the JSON Schema dialect of the schemas generated.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema, holding the subset of keywords needed to describe
+// the file formats of 'nvidia-mig-parted'.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type    Types         `json:"type,omitempty"`
+	Const   interface{}   `json:"const,omitempty"`
+	Enum    []interface{} `json:"enum,omitempty"`
+	Pattern string        `json:"pattern,omitempty"`
+	Minimum *float64      `json:"minimum,omitempty"`
+	Maximum *float64      `json:"maximum,omitempty"`
+
+	MinLength     *int `json:"minLength,omitempty"`
+	MinItems      *int `json:"minItems,omitempty"`
+	MinProperties *int `json:"minProperties,omitempty"`
+	MaxProperties *int `json:"maxProperties,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	PropertyNames        *Schema            `json:"propertyNames,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+	If    *Schema   `json:"if,omitempty"`
+	Then  *Schema   `json:"then,omitempty"`
+	Else  *Schema   `json:"else,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+
+	// never marks the 'false' schema, which no value is valid against.
+	never bool
+}
+
+// Types holds the JSON types a value may have, written as a single string
+// when there is only one.
+type Types []string
+
+// False returns the schema no value is valid against (e.g. to disallow
+// properties not listed in 'Properties').
+func False() *Schema {
+	return &Schema{never: true}
+}
+
+// Ref returns a schema referring to the definition 'name' of the root schema.
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+// Int returns a pointer to 'n', to set the keywords holding a count.
+func Int(n int) *int {
+	return &n
+}
+
+// Number returns a pointer to 'n', to set the keywords holding a bound.
+func Number(n float64) *float64 {
+	return &n
+}
+
+// MarshalJSON marshals a 'Schema', writing the 'false' schema as a boolean.
+// Characters such as '<' in patterns are not escaped, to keep them readable.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.never {
+		return []byte("false"), nil
+	}
+	type plain Schema
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode((*plain)(s)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// MarshalJSON marshals 'Types' as a string if it holds a single type.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
@@ -0,0 +1,316 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate checks a JSON document against a root schema, supporting the
+// keywords a 'Schema' holds. The errors found are returned together, each
+// prefixed with the JSON pointer of the offending value.
+func (s *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return err
+	}
+	return errors.Join(s.validate(s, v, "")...)
+}
+
+func (s *Schema) validate(root *Schema, v interface{}, path string) []error {
+	if s.never {
+		return []error{pathError(path, "not allowed")}
+	}
+	if s.Ref != "" {
+		ref, err := root.resolve(s.Ref)
+		if err != nil {
+			return []error{pathError(path, err.Error())}
+		}
+		if errs := ref.validate(root, v, path); len(errs) > 0 {
+			return errs
+		}
+	}
+
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return hasType(v, t) }) {
+		return []error{pathError(path, fmt.Sprintf("expected %v, got %v", strings.Join(s.Type, " or "), typeOf(v)))}
+	}
+
+	var errs []error
+	if s.Const != nil && !equal(v, s.Const) {
+		errs = append(errs, pathError(path, fmt.Sprintf("expected %v", format(s.Const))))
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e interface{}) bool { return equal(v, e) }) {
+		var values []string
+		for _, e := range s.Enum {
+			values = append(values, format(e))
+		}
+		errs = append(errs, pathError(path, fmt.Sprintf("expected one of [%v], got %v", strings.Join(values, ", "), format(v))))
+	}
+
+	switch value := v.(type) {
+	case string:
+		errs = append(errs, s.validateString(value, path)...)
+	case json.Number:
+		errs = append(errs, s.validateNumber(value, path)...)
+	case []interface{}:
+		errs = append(errs, s.validateArray(root, value, path)...)
+	case map[string]interface{}:
+		errs = append(errs, s.validateObject(root, value, path)...)
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, sub.validate(root, v, path)...)
+	}
+	if len(s.AnyOf) > 0 {
+		var anyErrs []error
+		for _, sub := range s.AnyOf {
+			subErrs := sub.validate(root, v, path)
+			if len(subErrs) == 0 {
+				anyErrs = nil
+				break
+			}
+			anyErrs = append(anyErrs, subErrs...)
+		}
+		if len(anyErrs) > 0 {
+			errs = append(errs, pathError(path, "does not match any of the allowed forms: "+joinErrors(anyErrs)))
+		}
+	}
+	if len(s.OneOf) > 0 {
+		var matched int
+		var oneErrs []error
+		for _, sub := range s.OneOf {
+			subErrs := sub.validate(root, v, path)
+			if len(subErrs) == 0 {
+				matched++
+			}
+			oneErrs = append(oneErrs, subErrs...)
+		}
+		if matched == 0 {
+			errs = append(errs, pathError(path, "does not match any of the allowed forms: "+joinErrors(oneErrs)))
+		}
+		if matched > 1 {
+			errs = append(errs, pathError(path, "matches more than one of the allowed forms"))
+		}
+	}
+	if s.Not != nil && len(s.Not.validate(root, v, path)) == 0 {
+		errs = append(errs, pathError(path, "matches a form that is not allowed"))
+	}
+	if s.If != nil {
+		if len(s.If.validate(root, v, path)) == 0 {
+			if s.Then != nil {
+				errs = append(errs, s.Then.validate(root, v, path)...)
+			}
+		} else if s.Else != nil {
+			errs = append(errs, s.Else.validate(root, v, path)...)
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) validateString(v string, path string) []error {
+	var errs []error
+	if s.MinLength != nil && utf8.RuneCountInString(v) < *s.MinLength {
+		errs = append(errs, pathError(path, fmt.Sprintf("must be at least %d character(s) long", *s.MinLength)))
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return append(errs, pathError(path, fmt.Sprintf("invalid pattern '%v': %v", s.Pattern, err)))
+		}
+		if !re.MatchString(v) {
+			errs = append(errs, pathError(path, fmt.Sprintf("'%v' does not match pattern '%v'", v, s.Pattern)))
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateNumber(v json.Number, path string) []error {
+	n, err := v.Float64()
+	if err != nil {
+		return []error{pathError(path, err.Error())}
+	}
+	var errs []error
+	if s.Minimum != nil && n < *s.Minimum {
+		errs = append(errs, pathError(path, fmt.Sprintf("must be at least %v", *s.Minimum)))
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		errs = append(errs, pathError(path, fmt.Sprintf("must be at most %v", *s.Maximum)))
+	}
+	return errs
+}
+
+func (s *Schema) validateArray(root *Schema, v []interface{}, path string) []error {
+	var errs []error
+	if s.MinItems != nil && len(v) < *s.MinItems {
+		errs = append(errs, pathError(path, fmt.Sprintf("must have at least %d item(s)", *s.MinItems)))
+	}
+	if s.Items != nil {
+		for i, item := range v {
+			errs = append(errs, s.Items.validate(root, item, path+"/"+strconv.Itoa(i))...)
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateObject(root *Schema, v map[string]interface{}, path string) []error {
+	var errs []error
+	if s.MinProperties != nil && len(v) < *s.MinProperties {
+		errs = append(errs, pathError(path, fmt.Sprintf("must have at least %d propert(ies)", *s.MinProperties)))
+	}
+	if s.MaxProperties != nil && len(v) > *s.MaxProperties {
+		errs = append(errs, pathError(path, fmt.Sprintf("must have at most %d propert(ies)", *s.MaxProperties)))
+	}
+	for _, r := range s.Required {
+		if _, exists := v[r]; !exists {
+			errs = append(errs, pathError(path, fmt.Sprintf("missing required property '%v'", r)))
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(v)) {
+		child := path + "/" + escape(k)
+		if s.PropertyNames != nil {
+			for _, err := range s.PropertyNames.validate(root, k, child) {
+				errs = append(errs, fmt.Errorf("invalid property name: %w", err))
+			}
+		}
+		if p, exists := s.Properties[k]; exists {
+			errs = append(errs, p.validate(root, v[k], child)...)
+			continue
+		}
+		if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.never {
+				errs = append(errs, pathError(path, fmt.Sprintf("unexpected property '%v'", k)))
+				continue
+			}
+			errs = append(errs, s.AdditionalProperties.validate(root, v[k], child)...)
+		}
+	}
+	return errs
+}
+
+// resolve returns the definition a '$ref' of the root schema refers to.
+func (s *Schema) resolve(ref string) (*Schema, error) {
+	name, found := strings.CutPrefix(ref, "#/$defs/")
+	if !found {
+		return nil, fmt.Errorf("unsupported reference '%v'", ref)
+	}
+	def, exists := s.Defs[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown definition '%v'", name)
+	}
+	return def, nil
+}
+
+func hasType(v interface{}, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	}
+	return typeOf(v) == t
+}
+
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// equal compares a decoded JSON value to a value of a schema keyword, which
+// may hold Go values that are not decoded JSON (e.g. an 'int').
+func equal(v interface{}, expected interface{}) bool {
+	data, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var e interface{}
+	if err := decoder.Decode(&e); err != nil {
+		return false
+	}
+	if n, ok := v.(json.Number); ok {
+		m, ok := e.(json.Number)
+		if !ok {
+			return false
+		}
+		f1, err1 := n.Float64()
+		f2, err2 := m.Float64()
+		return err1 == nil && err2 == nil && f1 == f2
+	}
+	return reflect.DeepEqual(v, e)
+}
+
+func format(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// escape escapes a property name for use in a JSON pointer.
+func escape(k string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1")
+}
+
+func pathError(path string, msg string) error {
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Errorf("%v: %v", path, msg)
+}
+
+func joinErrors(errs []error) string {
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return "(" + strings.Join(msgs, "; ") + ")"
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	count := &Schema{Type: Types{"integer"}, Minimum: Number(0)}
+	schema := &Schema{
+		Schema: Draft,
+		Type:   Types{"object"},
+		Properties: map[string]*Schema{
+			// $ref
+			"count":  Ref("count"),
+			"counts": {Type: Types{"array"}, MinItems: Int(1), Items: Ref("count")},
+			"broken": Ref("missing"),
+			// anyOf and oneOf
+			"devices": {AnyOf: []*Schema{
+				{Type: Types{"string"}, Const: "all"},
+				{Type: Types{"array"}, Items: count},
+			}},
+			"size": {OneOf: []*Schema{
+				{Type: Types{"integer"}, Minimum: Number(0)},
+				{Type: Types{"integer"}, Maximum: Number(10)},
+			}},
+			// not
+			"name": {Type: Types{"string"}, MinLength: Int(1), Not: &Schema{Enum: []interface{}{"all", "none"}}},
+			// if/then/else
+			"mig": {
+				Type: Types{"object"},
+				If: &Schema{
+					Properties: map[string]*Schema{"enabled": {Const: true}},
+					Required:   []string{"enabled"},
+				},
+				Then: &Schema{Required: []string{"devices"}},
+				Else: &Schema{Properties: map[string]*Schema{"devices": False()}},
+			},
+			// propertyNames and additionalProperties
+			"profiles": {
+				Type:                 Types{"object"},
+				PropertyNames:        &Schema{Pattern: `^[0-9]+g\.[0-9]+gb$`},
+				AdditionalProperties: count,
+				MaxProperties:        Int(2),
+			},
+		},
+		AdditionalProperties: False(),
+		Defs:                 map[string]*Schema{"count": count},
+	}
+
+	testCases := []struct {
+		description    string
+		document       string
+		expectedErrors []string
+	}{
+		{"Empty Object", `{}`, nil},
+		{"Wrong Root Type", `[]`, []string{"/: expected object, got array"}},
+		{"Invalid JSON", `{`, []string{"unexpected EOF"}},
+
+		{"Ref Valid", `{"count": 3, "counts": [1, 2]}`, nil},
+		{"Ref Wrong Type", `{"count": "3"}`, []string{"/count: expected integer, got string"}},
+		{"Ref Not An Integer", `{"count": 1.5}`, []string{"/count: expected integer, got number"}},
+		{"Ref Out Of Range", `{"counts": [1, -1]}`, []string{"/counts/1: must be at least 0"}},
+		{"Ref Unknown Definition", `{"broken": 1}`, []string{"/broken: unknown definition 'missing'"}},
+		{"Min Items", `{"counts": []}`, []string{"/counts: must have at least 1 item(s)"}},
+
+		{"AnyOf First Form", `{"devices": "all"}`, nil},
+		{"AnyOf Second Form", `{"devices": [0, 1]}`, nil},
+		{"AnyOf No Form", `{"devices": "some"}`, []string{"/devices: does not match any of the allowed forms: (/devices: expected \"all\"; /devices: expected array, got string)"}},
+		{"AnyOf Invalid Item", `{"devices": [-1]}`, []string{"/devices: does not match any of the allowed forms"}},
+
+		{"OneOf Single Form", `{"size": 11}`, nil},
+		{"OneOf Both Forms", `{"size": 5}`, []string{"/size: matches more than one of the allowed forms"}},
+		{"OneOf No Form", `{"size": "5"}`, []string{"/size: does not match any of the allowed forms"}},
+
+		{"Not Allowed Value", `{"name": "all"}`, []string{"/name: matches a form that is not allowed"}},
+		{"Not Other Value", `{"name": "custom"}`, nil},
+		{"Min Length", `{"name": ""}`, []string{"/name: must be at least 1 character(s) long"}},
+
+		{"If Then Holds", `{"mig": {"enabled": true, "devices": "all"}}`, nil},
+		{"If Then Fails", `{"mig": {"enabled": true}}`, []string{"/mig: missing required property 'devices'"}},
+		{"If Else Holds", `{"mig": {"enabled": false}}`, nil},
+		{"If Else Fails", `{"mig": {"devices": "all"}}`, []string{"/mig/devices: not allowed"}},
+
+		{"Property Names Valid", `{"profiles": {"1g.5gb": 7, "3g.20gb": 2}}`, nil},
+		{"Property Names Invalid", `{"profiles": {"1g.5gb": 7, "all": 1}}`, []string{"invalid property name: /profiles/all: 'all' does not match pattern"}},
+		{"Additional Properties Schema", `{"profiles": {"1g.5gb": -1}}`, []string{"/profiles/1g.5gb: must be at least 0"}},
+		{"Additional Properties False", `{"count": 1, "extra": true}`, []string{"/: unexpected property 'extra'"}},
+		{"Max Properties", `{"profiles": {"1g.5gb": 1, "2g.10gb": 1, "3g.20gb": 1}}`, []string{"/profiles: must have at most 2 propert(ies)"}},
+
+		{"Errors Reported Together", `{"count": -1, "name": "none", "extra": 1}`, []string{
+			"/count: must be at least 0",
+			"/name: matches a form that is not allowed",
+			"/: unexpected property 'extra'",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := schema.Validate([]byte(tc.document))
+			if len(tc.expectedErrors) == 0 {
+				require.Nil(t, err, "Unexpected failure from Validate")
+				return
+			}
+			require.NotNil(t, err, "Unexpected success from Validate")
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestValidatePointerEscaping(t *testing.T) {
+	schema := &Schema{AdditionalProperties: &Schema{Type: Types{"integer"}}}
+
+	err := schema.Validate([]byte(`{"a/b": "x", "c~d": "y"}`))
+	require.NotNil(t, err, "Unexpected success from Validate")
+	require.Contains(t, err.Error(), "/a~1b: expected integer, got string")
+	require.Contains(t, err.Error(), "/c~0d: expected integer, got string")
+}
+
+func TestMarshalFalseSchema(t *testing.T) {
+	schema := &Schema{Type: Types{"object"}, AdditionalProperties: False(), Pattern: "^<[a-z]+>$"}
+
+	data, err := schema.MarshalJSON()
+	require.Nil(t, err)
+	require.JSONEq(t, `{"type": "object", "pattern": "^<[a-z]+>$", "additionalProperties": false}`, string(data))
+	require.Contains(t, string(data), "<")
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package suggest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Closest returns the candidate closest to 's' by edit distance.
+// It returns false if no candidate is close enough to be a plausible typo of 's',
+// or to be what 's' abbreviates (e.g. "memory" for "memory-gb").
+func Closest(s string, candidates []string) (string, bool) {
+	best := ""
+	bestDistance := -1
+	for _, c := range candidates {
+		d := distance(s, c)
+		if d > maxDistance(s) && !isPart(s, c) {
+			continue
+		}
+		if bestDistance == -1 || d < bestDistance {
+			best = c
+			bestDistance = d
+		}
+	}
+
+	if bestDistance == -1 {
+		return "", false
+	}
+
+	return best, true
+}
+
+// DidYouMean returns a " (did you mean '<candidate>'?)" hint for 's', or an empty string if there is none.
+func DidYouMean(s string, candidates []string) string {
+	c, ok := Closest(s, candidates)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%v'?)", c)
+}
+
+// maxDistance returns the largest edit distance still considered a typo for a string of the given length.
+func maxDistance(s string) int {
+	return max(1, len(s)/3)
+}
+
+// isPart checks if 's' is one of the '-' separated parts of 'c'.
+func isPart(s, c string) bool {
+	return s != "" && slices.Contains(strings.Split(c, "-"), s)
+}
+
+// distance computes the Levenshtein distance between two strings.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDidYouMean(t *testing.T) {
+	testCases := []struct {
+		description string
+		s           string
+		candidates  []string
+		expected    string
+	}{
+		{
+			description: "Exact match",
+			s:           "hooks",
+			candidates:  []string{"version", "hooks"},
+			expected:    " (did you mean 'hooks'?)",
+		},
+		{
+			description: "Single edit in a short string",
+			s:           "hook",
+			candidates:  []string{"version", "hooks"},
+			expected:    " (did you mean 'hooks'?)",
+		},
+		{
+			description: "Two edits in a short string",
+			s:           "hok",
+			candidates:  []string{"hooks"},
+			expected:    "",
+		},
+		{
+			description: "Edits within a third of the length",
+			s:           "pre-apply-confg",
+			candidates:  []string{"pre-apply-config", "pre-apply-mode"},
+			expected:    " (did you mean 'pre-apply-config'?)",
+		},
+		{
+			description: "Edits beyond a third of the length",
+			s:           "whenever",
+			candidates:  []string{"apply-start", "apply-exit"},
+			expected:    "",
+		},
+		{
+			description: "Part of a candidate",
+			s:           "memory",
+			candidates:  []string{"name", "architecture", "memory-gb", "not"},
+			expected:    " (did you mean 'memory-gb'?)",
+		},
+		{
+			description: "Closer typo preferred over a part",
+			s:           "gpu",
+			candidates:  []string{"gpu-count", "cpu"},
+			expected:    " (did you mean 'cpu'?)",
+		},
+		{
+			description: "Ties resolved by the order of the candidates",
+			s:           "ab",
+			candidates:  []string{"ac", "ad"},
+			expected:    " (did you mean 'ac'?)",
+		},
+		{
+			description: "Empty string is not part of a candidate",
+			s:           "",
+			candidates:  []string{"a-b"},
+			expected:    "",
+		},
+		{
+			description: "No candidates",
+			s:           "hooks",
+			candidates:  nil,
+			expected:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, DidYouMean(tc.s, tc.candidates))
+		})
+	}
+}
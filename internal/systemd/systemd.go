@@ -148,6 +148,15 @@ func (sm *Manager) StartService(serviceName string) error {
 	return nil
 }
 
+// EnqueueStartService enqueues a start job for a systemd service without waiting for it to complete
+func (sm *Manager) EnqueueStartService(serviceName string) error {
+	_, err := sm.conn.StartUnitContext(sm.ctx, serviceName, "replace", nil)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue start of service %s: %w", serviceName, err)
+	}
+	return nil
+}
+
 // StopService stops a systemd service
 func (sm *Manager) StopService(serviceName string) error {
 	ch := make(chan string, 1)
@@ -240,6 +249,15 @@ func (sm *Manager) StopSystemdServices(services []string) ([]string, error) {
 
 // StartSystemdServices starts multiple systemd services
 func (sm *Manager) StartSystemdServices(services []string) error {
+	return sm.startSystemdServices(services, sm.StartService)
+}
+
+// EnqueueStartSystemdServices enqueues start jobs for multiple systemd services without waiting for them to complete
+func (sm *Manager) EnqueueStartSystemdServices(services []string) error {
+	return sm.startSystemdServices(services, sm.EnqueueStartService)
+}
+
+func (sm *Manager) startSystemdServices(services []string, start func(string) error) error {
 	var ret error
 
 	for _, service := range services {
@@ -249,7 +267,7 @@ func (sm *Manager) StartSystemdServices(services []string) error {
 		}
 
 		fmt.Printf("Starting %s\n", service)
-		if err := sm.StartService(service); err != nil {
+		if err := start(service); err != nil {
 			fmt.Printf("Error Starting %s: skipping, but continuing...\n", service)
 			ret = errors.Join(ret, err)
 		}
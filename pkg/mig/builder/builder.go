@@ -38,12 +38,51 @@ func normalizeProfileName(profileStr string) string {
 	return strings.ReplaceAll(profileStr, "+", ".")
 }
 
+// Option configures the configs generated in addition to the base ones.
+type Option func(*options)
+
+type options struct {
+	enumerate       bool
+	enumerateFilter EnumerateFilter
+	precedence      Precedence
+	userConfigs     []*UserConfigs
+	deviceProfiles  discovery.DeviceProfiles
+}
+
+// WithEnumeration adds a config for every maximal combination of profiles on
+// each device type, limited by the filter provided.
+func WithEnumeration(filter EnumerateFilter) Option {
+	return func(o *options) {
+		o.enumerate = true
+		o.enumerateFilter = filter
+	}
+}
+
+// WithDeviceProfiles generates the configs from previously discovered profiles
+// (e.g. those of a discovery snapshot) instead of discovering them from hardware.
+func WithDeviceProfiles(deviceProfiles discovery.DeviceProfiles) Option {
+	return func(o *options) {
+		o.deviceProfiles = deviceProfiles
+	}
+}
+
 // buildMigConfigSpec creates a v1.Spec from discovered profiles.
 // This is an internal function - use GenerateConfigSpec() instead.
-func buildMigConfigSpec(deviceProfiles discovery.DeviceProfiles) (*migspec.Spec, error) {
-	// Group profiles by profile name across all devices
-	// map[profileName]map[deviceID]discovery.ProfileInfo
-	profileGroups := make(map[string]map[string]discovery.ProfileInfo)
+func buildMigConfigSpec(deviceProfiles discovery.DeviceProfiles, opts ...Option) (*migspec.Spec, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Group the counts filling a device with each profile by profile name across all devices
+	// map[profileName]map[deviceID]count
+	profileGroups := make(map[string]map[string]int)
+	addProfileCount := func(profileName, deviceIDStr string, count int) {
+		if profileGroups[profileName] == nil {
+			profileGroups[profileName] = make(map[string]int)
+		}
+		profileGroups[profileName][deviceIDStr] = count
+	}
 
 	// Track all unique device IDs in the system
 	allDeviceIDs := make(map[string]bool)
@@ -52,11 +91,21 @@ func buildMigConfigSpec(deviceProfiles discovery.DeviceProfiles) (*migspec.Spec,
 		for _, pInfo := range profiles {
 			deviceIDStr := pInfo.DeviceID.String()
 			allDeviceIDs[deviceIDStr] = true
-
-			if profileGroups[pInfo.Name] == nil {
-				profileGroups[pInfo.Name] = make(map[string]discovery.ProfileInfo)
+			addProfileCount(pInfo.Name, deviceIDStr, pInfo.MaxCount)
+
+			// Compute Instance profiles fill a device with GPU instances of their
+			// GPU instance profile, each shared by as many Compute Instances as fit
+			// (e.g. 'all-1c.3g.40gb' gives 2 GPU instances of 3 Compute Instances).
+			// Those using a GPU instance on their own would leave compute slices unused.
+			if !isBaseProfile(pInfo) {
+				continue
+			}
+			for _, ciInfo := range pInfo.ComputeInstances {
+				if ciInfo.MaxCount < 2 {
+					continue
+				}
+				addProfileCount(ciInfo.Name, deviceIDStr, pInfo.MaxCount*ciInfo.MaxCount)
 			}
-			profileGroups[pInfo.Name][deviceIDStr] = pInfo
 		}
 	}
 
@@ -92,8 +141,8 @@ func buildMigConfigSpec(deviceProfiles discovery.DeviceProfiles) (*migspec.Spec,
 		// Group devices by their max count for this profile
 		// Some devices may support different max counts for the same profile
 		countToDevices := make(map[int][]string)
-		for deviceIDStr, pInfo := range devicesWithProfile {
-			countToDevices[pInfo.MaxCount] = append(countToDevices[pInfo.MaxCount], deviceIDStr)
+		for deviceIDStr, maxCount := range devicesWithProfile {
+			countToDevices[maxCount] = append(countToDevices[maxCount], deviceIDStr)
 		}
 
 		// Sort counts for consistent output
@@ -138,24 +187,54 @@ func buildMigConfigSpec(deviceProfiles discovery.DeviceProfiles) (*migspec.Spec,
 		configs[configName] = configSpecs
 	}
 
+	mediaExtensionConfigs, err := buildMediaExtensionConfigs(deviceProfiles, allDeviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	for configName, configSpecs := range mediaExtensionConfigs {
+		configs[configName] = configSpecs
+	}
+
+	if o.enumerate {
+		enumerated, err := buildEnumeratedConfigs(deviceProfiles, allDeviceIDs, o.enumerateFilter)
+		if err != nil {
+			return nil, err
+		}
+		for configName, configSpecs := range enumerated {
+			configs[configName] = configSpecs
+		}
+	}
+
+	overlayUserConfigs(configs, o.precedence, o.userConfigs)
+
 	return &migspec.Spec{
 		Version:    migspec.Version,
 		MigConfigs: configs,
 	}, nil
 }
 
-// GenerateConfigSpec discovers MIG profiles from hardware and builds a config spec.
-func GenerateConfigSpec() (*migspec.Spec, error) {
-	deviceProfiles, err := discovery.DiscoverMIGProfiles()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover MIG profiles: %w", err)
+// GenerateConfigSpec discovers MIG profiles from hardware (unless provided with
+// WithDeviceProfiles) and builds a config spec.
+func GenerateConfigSpec(opts ...Option) (*migspec.Spec, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	deviceProfiles := o.deviceProfiles
+	if deviceProfiles == nil {
+		var err error
+		deviceProfiles, err = discovery.DiscoverMIGProfiles()
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover MIG profiles: %w", err)
+		}
 	}
-	return buildMigConfigSpec(deviceProfiles)
+	return buildMigConfigSpec(deviceProfiles, opts...)
 }
 
 // GenerateConfigYAML discovers MIG profiles and generates the full config as YAML bytes.
-func GenerateConfigYAML() ([]byte, error) {
-	spec, err := GenerateConfigSpec()
+func GenerateConfigYAML(opts ...Option) ([]byte, error) {
+	spec, err := GenerateConfigSpec(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -169,8 +248,8 @@ func GenerateConfigYAML() ([]byte, error) {
 }
 
 // GenerateConfigJSON discovers MIG profiles and generates the full config as JSON bytes.
-func GenerateConfigJSON() ([]byte, error) {
-	spec, err := GenerateConfigSpec()
+func GenerateConfigJSON(opts ...Option) ([]byte, error) {
+	spec, err := GenerateConfigSpec(opts...)
 	if err != nil {
 		return nil, err
 	}
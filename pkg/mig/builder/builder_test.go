@@ -17,6 +17,7 @@
 package builder
 
 import (
+	"maps"
 	"slices"
 	"testing"
 
@@ -24,9 +25,13 @@ import (
 	"github.com/stretchr/testify/require"
 
 	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
 	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/config"
 	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/sim/hardware"
+	"github.com/NVIDIA/mig-parted/pkg/sim/simtest"
 	"github.com/NVIDIA/mig-parted/pkg/types"
 )
 
@@ -40,6 +45,15 @@ func mockProfile(g, gb int, attrs, negAttrs []string) nvdev.MigProfile {
 	}
 }
 
+func mockComputeInstances(g, gb int, cs ...int) []discovery.ComputeInstanceInfo {
+	var cis []discovery.ComputeInstanceInfo
+	for _, c := range cs {
+		profile := nvdev.MigProfileInfo{C: c, G: g, GB: gb}
+		cis = append(cis, discovery.ComputeInstanceInfo{Name: profile.String(), MaxCount: g / c, Profile: profile})
+	}
+	return cis
+}
+
 func mockDeviceID(id uint32) types.DeviceID {
 	device := uint16(id >> 16)
 	vendor := uint16(id & 0xFFFF)
@@ -207,6 +221,7 @@ func TestBuildMigConfigSpec(t *testing.T) {
 		name           string
 		deviceProfiles discovery.DeviceProfiles
 		wantConfigs    []wantConfig
+		absentConfigs  []string
 	}{
 		{
 			name:           "A100-80GB",
@@ -224,6 +239,9 @@ func TestBuildMigConfigSpec(t *testing.T) {
 				{"all-3g.40gb", "3g.40gb", 2, nil},
 				{"all-4g.40gb", "4g.40gb", 1, nil},
 				{"all-7g.80gb", "7g.80gb", 1, nil},
+				// Media extensions
+				{"all-6x1g.10gb_1x1g.10gb.me", "1g.10gb", 6, nil},
+				{"all-6x1g.10gb_1x1g.10gb.me", "1g.10gb+me", 1, nil},
 			},
 		},
 		{
@@ -239,6 +257,11 @@ func TestBuildMigConfigSpec(t *testing.T) {
 				{"all-2g.12gb", "2g.12gb", 2, nil},
 				{"all-2g.12gb.me", "2g.12gb+me", 1, nil},
 				{"all-4g.24gb", "4g.24gb", 1, nil},
+				// Media extensions
+				{"all-3x1g.6gb_1x1g.6gb.me", "1g.6gb", 3, nil},
+				{"all-3x1g.6gb_1x1g.6gb.me", "1g.6gb+me", 1, nil},
+				{"all-1x2g.12gb_1x2g.12gb.me", "2g.12gb", 1, nil},
+				{"all-1x2g.12gb_1x2g.12gb.me", "2g.12gb+me", 1, nil},
 			},
 		},
 		{
@@ -308,7 +331,33 @@ func TestBuildMigConfigSpec(t *testing.T) {
 				{"all-2g.48gb-me", "2g.48gb-me", 2, nil},
 				{"all-4g.96gb", "4g.96gb", 1, nil},
 				{"all-4g.96gb.gfx", "4g.96gb+gfx", 1, nil},
+				// Media extensions, with -me profiles next to those taking all of them
+				{"all-3x1g.24gb_1x1g.24gb.me", "1g.24gb", 3, nil},
+				{"all-1x1g.24gb.me.all_3x1g.24gb-me", "1g.24gb+me.all", 1, nil},
+				{"all-1x1g.24gb.me.all_3x1g.24gb-me", "1g.24gb-me", 3, nil},
+				{"all-1x2g.48gb.me.all_1x2g.48gb-me", "2g.48gb-me", 1, nil},
+			},
+		},
+		{
+			name: "compute instance profiles sharing GPU instances",
+			deviceProfiles: discovery.DeviceProfiles{
+				0: {
+					{Name: "3g.40gb", MaxCount: 2, DeviceID: mockDeviceID(deviceIDA100_80GB), Profile: mockProfile(3, 40, nil, nil),
+						ComputeInstances: mockComputeInstances(3, 40, 1, 2)},
+					{Name: "3g.40gb+me", MaxCount: 1, DeviceID: mockDeviceID(deviceIDA100_80GB), Profile: mockProfile(3, 40, []string{"me"}, nil),
+						ComputeInstances: mockComputeInstances(3, 40, 1, 2)},
+					{Name: "7g.80gb", MaxCount: 1, DeviceID: mockDeviceID(deviceIDA100_80GB), Profile: mockProfile(7, 80, nil, nil),
+						ComputeInstances: mockComputeInstances(7, 80, 1, 2, 3, 4)},
+				},
 			},
+			wantConfigs: []wantConfig{
+				{"all-1c.3g.40gb", "1c.3g.40gb", 6, nil},
+				{"all-1c.7g.80gb", "1c.7g.80gb", 7, nil},
+				{"all-2c.7g.80gb", "2c.7g.80gb", 3, nil},
+				{"all-3c.7g.80gb", "3c.7g.80gb", 2, nil},
+			},
+			// Compute Instances using a GPU instance on their own, or of GPU instances with attributes.
+			absentConfigs: []string{"all-2c.3g.40gb", "all-4c.7g.80gb", "all-1c.3g.40gb.me"},
 		},
 		{
 			name: "same profile different max counts creates multiple entries",
@@ -369,6 +418,118 @@ func TestBuildMigConfigSpec(t *testing.T) {
 				assert.True(t, matched.MigEnabled, "config %s should have mig-enabled: true", want.configName)
 				assert.Equal(t, want.count, matched.MigDevices[want.profileName], "config %s should have %s: %d", want.configName, want.profileName, want.count)
 			}
+			for _, configName := range tc.absentConfigs {
+				assert.NotContains(t, spec.MigConfigs, configName)
+			}
 		})
 	}
 }
+
+func TestGenerateConfigSpecWithDeviceProfiles(t *testing.T) {
+	deviceProfiles := discovery.DeviceProfiles{0: gpuProfiles["A100-80GB"], 1: gpuProfiles["A30-24GB"]}
+
+	expected, err := buildMigConfigSpec(deviceProfiles)
+	require.Nil(t, err)
+
+	// No NVML is needed to generate the configs of previously discovered profiles.
+	spec, err := GenerateConfigSpec(WithDeviceProfiles(deviceProfiles))
+	require.Nil(t, err, "Unexpected failure from GenerateConfigSpec")
+	require.Equal(t, expected, spec)
+}
+
+// newSimulatedNode returns the NVML library of a simulated node with one GPU of each model, along with their MIG profiles.
+func newSimulatedNode(t *testing.T, models []string) (nvml.Interface, discovery.DeviceProfiles) {
+	nvmllib := simtest.NewNvml(t, simtest.Models(models...)...)
+	deviceProfiles, err := discovery.DiscoverMIGProfilesFrom(nvmllib)
+	require.NoError(t, err)
+	return nvmllib, deviceProfiles
+}
+
+// applyConfigs applies every MIG-enabled config in a spec to the GPUs of an
+// NVML library that it matches, checking that exporting gives it back.
+func applyConfigs(t *testing.T, nvmllib nvml.Interface, spec *v1.Spec) {
+	manager := config.NewNvmlMigConfigManager(nvmllib)
+
+	count, ret := nvmllib.DeviceGetCount()
+	require.Equal(t, nvml.SUCCESS, ret)
+	for i := range count {
+		device, ret := nvmllib.DeviceGetHandleByIndex(i)
+		require.Equal(t, nvml.SUCCESS, ret)
+		ret, _ = device.SetMigMode(nvml.DEVICE_MIG_ENABLE)
+		require.Equal(t, nvml.SUCCESS, ret)
+		pciInfo, ret := device.GetPciInfo()
+		require.Equal(t, nvml.SUCCESS, ret)
+		deviceID := types.NewDeviceIDFromPacked(pciInfo.PciDeviceId)
+
+		for _, configName := range slices.Sorted(maps.Keys(spec.MigConfigs)) {
+			for _, cfg := range spec.MigConfigs[configName] {
+				if !cfg.MigEnabled || len(cfg.MigDevices) == 0 || !cfg.MatchesDeviceFilter(deviceID) {
+					continue
+				}
+				err := manager.SetMigConfig(i, cfg.MigDevices)
+				require.NoError(t, err, "applying config %s to GPU %d (%s)", configName, i, deviceID)
+
+				exported, err := manager.GetMigConfig(i)
+				require.NoError(t, err)
+				assert.Equal(t, cfg.MigDevices, exported, "exporting config %s from GPU %d (%s)", configName, i, deviceID)
+			}
+		}
+	}
+}
+
+// TestGenerateConfigSpecOnSimulatedNode generates a config for a simulated node
+// with one GPU of every model that has a hardware description, and applies every
+// generated config to the GPUs it selects.
+func TestGenerateConfigSpecOnSimulatedNode(t *testing.T) {
+	models := hardware.Names()
+	nvmllib, deviceProfiles := newSimulatedNode(t, models)
+
+	spec, err := GenerateConfigSpec(WithDeviceProfiles(deviceProfiles))
+	require.NoError(t, err)
+	require.Contains(t, spec.MigConfigs, "all-balanced")
+
+	// Every profile of every model gets a config with its full count.
+	for _, name := range models {
+		model, err := hardware.Get(name)
+		require.NoError(t, err)
+		deviceID := types.NewDeviceIDFromPacked(model.Config().PciDeviceId)
+
+		balanced := slices.ContainsFunc(spec.MigConfigs["all-balanced"], func(cfg v1.MigConfigSpec) bool {
+			return cfg.MatchesDeviceFilter(deviceID)
+		})
+		assert.True(t, balanced, "config all-balanced: no entry for %s", name)
+
+		for _, p := range model.GpuInstanceProfiles {
+			configName := "all-" + normalizeProfileName(p.Name)
+			configs, ok := spec.MigConfigs[configName]
+			require.True(t, ok, "missing config: %s", configName)
+
+			var matched *v1.MigConfigSpec
+			for i := range configs {
+				if configs[i].MatchesDeviceFilter(deviceID) {
+					matched = &configs[i]
+				}
+			}
+			require.NotNil(t, matched, "config %s: no entry for %s", configName, name)
+			assert.Equal(t, p.Count, matched.MigDevices[p.Name], "config %s should have %s: %d on %s", configName, p.Name, p.Count, name)
+		}
+	}
+
+	// Media extension and shared compute instance configs make use of the whole GPU.
+	a100 := types.NewDeviceIDFromPacked(0x20B010DE)
+	for configName, migDevices := range map[string]types.MigConfig{
+		"all-6x1g.5gb_1x1g.5gb.me": {"1g.5gb": 6, "1g.5gb+me": 1},
+		"all-1c.3g.20gb":           {"1c.3g.20gb": 6},
+		"all-2c.7g.40gb":           {"2c.7g.40gb": 3},
+	} {
+		require.Contains(t, spec.MigConfigs, configName)
+		idx := slices.IndexFunc(spec.MigConfigs[configName], func(cfg v1.MigConfigSpec) bool {
+			return cfg.MatchesDeviceFilter(a100)
+		})
+		require.NotEqual(t, -1, idx, "config %s: no entry for A100-SXM4-40GB", configName)
+		assert.Equal(t, migDevices, spec.MigConfigs[configName][idx].MigDevices)
+	}
+
+	// Every generated config can be applied to (and exported from) the GPUs it selects.
+	applyConfigs(t, nvmllib, spec)
+}
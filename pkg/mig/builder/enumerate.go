@@ -0,0 +1,275 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	log "github.com/sirupsen/logrus"
+
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// EnumerateFilter limits the combinations of profiles generated by enumeration.
+type EnumerateFilter struct {
+	// MinSlices excludes profiles with fewer GPU slices (e.g. 2 excludes all 1g profiles).
+	MinSlices int
+	// IncludeProfiles only keeps combinations containing every one of these profiles.
+	IncludeProfiles []string
+	// BaseProfilesOnly excludes profiles with attributes (+me, +gfx, -me, etc.).
+	BaseProfilesOnly bool
+}
+
+// candidate is a profile at one of its possible placements.
+type candidate struct {
+	profile   int
+	placement nvml.GpuInstancePlacement
+}
+
+// allows checks whether the filter lets a profile take part in combinations.
+func (f *EnumerateFilter) allows(pInfo discovery.ProfileInfo) bool {
+	if pInfo.Profile.GetInfo().G < f.MinSlices {
+		return false
+	}
+	if f.BaseProfilesOnly && !isBaseProfile(pInfo) {
+		return false
+	}
+	return true
+}
+
+// includes checks whether a combination contains all the profiles required by the filter.
+func (f *EnumerateFilter) includes(combination types.MigConfig, profiles map[string]discovery.ProfileInfo) bool {
+	for _, include := range f.IncludeProfiles {
+		found := false
+		for name := range combination {
+			if profiles[name].Profile.Matches(include) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// enumerateCombinations returns every maximal combination of the profiles of a
+// single device type: a set of instance counts that fits on the device given the
+// placements and maximum count of each profile, and which leaves no room for
+// another instance of any profile.
+func enumerateCombinations(profiles []discovery.ProfileInfo) ([]types.MigConfig, error) {
+	var candidates []candidate
+	for i, pInfo := range profiles {
+		if err := pInfo.CheckPlacementMasks(); err != nil {
+			return nil, err
+		}
+		for _, placement := range pInfo.Placements {
+			candidates = append(candidates, candidate{i, placement})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(
+			cmp.Compare(a.placement.Start, b.placement.Start),
+			cmp.Compare(a.placement.Size, b.placement.Size),
+		)
+	})
+
+	// Visit every set of non-overlapping candidates, recording the instance
+	// counts of each one. The recorded counts are closed under removing an
+	// instance, so a combination is maximal if adding any profile to it gives
+	// counts that were never recorded.
+	valid := make(map[string][]int)
+	counts := make([]int, len(profiles))
+	var visit func(next int, used uint64)
+	visit = func(next int, used uint64) {
+		valid[countsKey(counts)] = slices.Clone(counts)
+		for i := next; i < len(candidates); i++ {
+			c := candidates[i]
+			mask := discovery.PlacementMask(c.placement)
+			if used&mask != 0 || counts[c.profile] >= profiles[c.profile].MaxCount {
+				continue
+			}
+			counts[c.profile]++
+			visit(i+1, used|mask)
+			counts[c.profile]--
+		}
+	}
+	visit(0, 0)
+
+	var combinations []types.MigConfig
+	for _, key := range slices.Sorted(maps.Keys(valid)) {
+		counts := valid[key]
+		if isMaximal(counts, valid) {
+			combination := make(types.MigConfig)
+			for i, count := range counts {
+				if count > 0 {
+					combination[profiles[i].Name] = count
+				}
+			}
+			if len(combination) > 0 {
+				combinations = append(combinations, combination)
+			}
+		}
+	}
+	return combinations, nil
+}
+
+func isMaximal(counts []int, valid map[string][]int) bool {
+	for i := range counts {
+		counts[i]++
+		_, grows := valid[countsKey(counts)]
+		counts[i]--
+		if grows {
+			return false
+		}
+	}
+	return true
+}
+
+func countsKey(counts []int) string {
+	return fmt.Sprint(counts)
+}
+
+// combinationName builds a readable config name for a combination of profiles,
+// listing the largest profiles first (e.g. "all-1x3g.40gb_1x2g.20gb_2x1g.10gb").
+func combinationName(combination types.MigConfig, profiles map[string]discovery.ProfileInfo) string {
+	names := slices.SortedFunc(maps.Keys(combination), func(a, b string) int {
+		ia, ib := profiles[a].Profile.GetInfo(), profiles[b].Profile.GetInfo()
+		return cmp.Or(
+			-cmp.Compare(ia.G, ib.G),
+			-cmp.Compare(ia.GB, ib.GB),
+			cmp.Compare(a, b),
+		)
+	})
+
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%dx%s", combination[name], normalizeProfileName(name)))
+	}
+	return "all-" + strings.Join(parts, "_")
+}
+
+// combinationConfigs collects configs for combinations of profiles, named after
+// their contents, along with the device IDs supporting each one.
+type combinationConfigs struct {
+	combinations map[string]types.MigConfig
+	deviceIDs    map[string][]string
+}
+
+func newCombinationConfigs() *combinationConfigs {
+	return &combinationConfigs{
+		combinations: make(map[string]types.MigConfig),
+		deviceIDs:    make(map[string][]string),
+	}
+}
+
+// add records that devices with the given ID support a combination of their profiles.
+func (cc *combinationConfigs) add(deviceID string, combination types.MigConfig, profiles map[string]discovery.ProfileInfo) {
+	configName := combinationName(combination, profiles)
+	cc.combinations[configName] = combination
+	cc.deviceIDs[configName] = append(cc.deviceIDs[configName], deviceID)
+}
+
+// specs creates a config for each combination, with a device-filter on systems
+// with more than one device type.
+func (cc *combinationConfigs) specs(allDeviceIDs map[string]bool) map[string]migspec.MigConfigSpecSlice {
+	configs := make(map[string]migspec.MigConfigSpecSlice)
+	for configName, combination := range cc.combinations {
+		spec := migspec.MigConfigSpec{
+			Devices:    "all",
+			MigEnabled: true,
+			MigDevices: combination,
+		}
+		if len(allDeviceIDs) > 1 {
+			spec.DeviceFilter = cc.deviceIDs[configName]
+		}
+		configs[configName] = migspec.MigConfigSpecSlice{spec}
+	}
+	return configs
+}
+
+// groupProfilesByDeviceID organizes profiles by device ID and then by profile name.
+func groupProfilesByDeviceID(deviceProfiles discovery.DeviceProfiles) map[string]map[string]discovery.ProfileInfo {
+	deviceTypes := make(map[string]map[string]discovery.ProfileInfo)
+	for _, profiles := range deviceProfiles {
+		for _, pInfo := range profiles {
+			deviceID := pInfo.DeviceID.String()
+			if deviceTypes[deviceID] == nil {
+				deviceTypes[deviceID] = make(map[string]discovery.ProfileInfo)
+			}
+			deviceTypes[deviceID][pInfo.Name] = pInfo
+		}
+	}
+	return deviceTypes
+}
+
+// buildEnumeratedConfigs creates a config for every maximal combination of
+// profiles on each device type, other than those using a single profile at its
+// max count (already covered by the "all-<profile>" configs).
+func buildEnumeratedConfigs(deviceProfiles discovery.DeviceProfiles, allDeviceIDs map[string]bool, filter EnumerateFilter) (map[string]migspec.MigConfigSpecSlice, error) {
+	deviceTypes := groupProfilesByDeviceID(deviceProfiles)
+	combinations := newCombinationConfigs()
+
+	for _, deviceID := range slices.Sorted(maps.Keys(deviceTypes)) {
+		profiles := deviceTypes[deviceID]
+
+		var allowed []discovery.ProfileInfo
+		for _, name := range slices.Sorted(maps.Keys(profiles)) {
+			pInfo := profiles[name]
+			if !filter.allows(pInfo) {
+				continue
+			}
+			if len(pInfo.Placements) == 0 {
+				log.Warnf("Profile %s on device %s has no known placements, skipping it in enumerated configs", name, deviceID)
+				continue
+			}
+			allowed = append(allowed, pInfo)
+		}
+
+		deviceCombinations, err := enumerateCombinations(allowed)
+		if err != nil {
+			return nil, fmt.Errorf("error enumerating combinations for device %s: %w", deviceID, err)
+		}
+
+		generated := 0
+		for _, combination := range deviceCombinations {
+			if len(combination) == 1 {
+				name := slices.Collect(maps.Keys(combination))[0]
+				if combination[name] == profiles[name].MaxCount {
+					continue
+				}
+			}
+			if !filter.includes(combination, profiles) {
+				continue
+			}
+			combinations.add(deviceID, combination, profiles)
+			generated++
+		}
+
+		log.Infof("Generated %d enumerated config(s) for device %s", generated, deviceID)
+	}
+
+	return combinations.specs(allDeviceIDs), nil
+}
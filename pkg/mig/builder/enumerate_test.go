@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func mockPlacements(size uint32, starts ...uint32) []nvml.GpuInstancePlacement {
+	var placements []nvml.GpuInstancePlacement
+	for _, start := range starts {
+		placements = append(placements, nvml.GpuInstancePlacement{Start: start, Size: size})
+	}
+	return placements
+}
+
+// placedProfiles returns the A100-40GB profiles along with their placements on 8 memory slices.
+func placedProfiles() []discovery.ProfileInfo {
+	placements := map[string][]nvml.GpuInstancePlacement{
+		"1g.5gb":    mockPlacements(1, 0, 1, 2, 3, 4, 5, 6),
+		"1g.5gb+me": mockPlacements(1, 0, 1, 2, 3, 4, 5, 6),
+		"1g.10gb":   mockPlacements(2, 0, 2, 4, 6),
+		"2g.10gb":   mockPlacements(2, 0, 2, 4),
+		"3g.20gb":   mockPlacements(4, 0, 4),
+		"4g.20gb":   mockPlacements(4, 0),
+		"7g.40gb":   mockPlacements(8, 0),
+	}
+	var profiles []discovery.ProfileInfo
+	for _, pInfo := range gpuProfiles["A100-40GB"] {
+		pInfo.Placements = placements[pInfo.Name]
+		profiles = append(profiles, pInfo)
+	}
+	return profiles
+}
+
+func TestEnumerateCombinations(t *testing.T) {
+	var base []discovery.ProfileInfo
+	for _, pInfo := range placedProfiles() {
+		if isBaseProfile(pInfo) {
+			base = append(base, pInfo)
+		}
+	}
+
+	combinations, err := enumerateCombinations(base)
+	require.NoError(t, err)
+
+	expected := []types.MigConfig{
+		{"7g.40gb": 1},
+		{"4g.20gb": 1, "3g.20gb": 1},
+		{"3g.20gb": 2},
+		{"3g.20gb": 1, "2g.10gb": 1, "1g.5gb": 2},
+		{"3g.20gb": 1, "2g.10gb": 2},
+		{"4g.20gb": 1, "2g.10gb": 1, "1g.10gb": 1},
+		{"1g.5gb": 7},
+		{"1g.10gb": 4},
+	}
+	for _, e := range expected {
+		assert.Contains(t, combinations, e)
+	}
+
+	// Combinations are maximal and never exceed the max count of a profile.
+	assert.NotContains(t, combinations, types.MigConfig{"4g.20gb": 1})
+	assert.NotContains(t, combinations, types.MigConfig{"3g.20gb": 1, "1g.5gb": 3})
+	for _, combination := range combinations {
+		for _, pInfo := range base {
+			assert.LessOrEqual(t, combination[pInfo.Name], pInfo.MaxCount)
+		}
+	}
+}
+
+func TestCombinationName(t *testing.T) {
+	profiles := make(map[string]discovery.ProfileInfo)
+	for _, pInfo := range placedProfiles() {
+		profiles[pInfo.Name] = pInfo
+	}
+
+	name := combinationName(types.MigConfig{"1g.5gb": 2, "3g.20gb": 1, "1g.10gb": 1, "1g.5gb+me": 1}, profiles)
+	require.Equal(t, "all-1x3g.20gb_1x1g.10gb_2x1g.5gb_1x1g.5gb.me", name)
+}
+
+func TestBuildEnumeratedConfigs(t *testing.T) {
+	a100 := placedProfiles()
+	a30 := discovery.DeviceProfiles{}
+	for _, pInfo := range gpuProfiles["A30-24GB"] {
+		pInfo.Placements = map[int][]nvml.GpuInstancePlacement{
+			1: mockPlacements(1, 0, 1, 2, 3),
+			2: mockPlacements(2, 0, 2),
+			4: mockPlacements(4, 0),
+		}[pInfo.Profile.GetInfo().G]
+		a30[0] = append(a30[0], pInfo)
+	}
+
+	testCases := []struct {
+		description    string
+		deviceProfiles discovery.DeviceProfiles
+		filter         EnumerateFilter
+		want           map[string]types.MigConfig
+		notWant        []string
+	}{
+		{
+			description:    "single profile at max count is left to the all-<profile> configs",
+			deviceProfiles: discovery.DeviceProfiles{0: a100},
+			filter:         EnumerateFilter{BaseProfilesOnly: true},
+			want: map[string]types.MigConfig{
+				"all-1x4g.20gb_1x3g.20gb":          {"4g.20gb": 1, "3g.20gb": 1},
+				"all-1x3g.20gb_1x2g.10gb_2x1g.5gb": {"3g.20gb": 1, "2g.10gb": 1, "1g.5gb": 2},
+			},
+			notWant: []string{"all-2x3g.20gb", "all-1x7g.40gb", "all-7x1g.5gb"},
+		},
+		{
+			description:    "min slices",
+			deviceProfiles: discovery.DeviceProfiles{0: a100},
+			filter:         EnumerateFilter{MinSlices: 2},
+			want: map[string]types.MigConfig{
+				"all-1x4g.20gb_1x3g.20gb": {"4g.20gb": 1, "3g.20gb": 1},
+				"all-1x4g.20gb_1x2g.10gb": {"4g.20gb": 1, "2g.10gb": 1},
+				"all-1x3g.20gb_2x2g.10gb": {"3g.20gb": 1, "2g.10gb": 2},
+			},
+			notWant: []string{"all-1x3g.20gb_1x2g.10gb_2x1g.5gb"},
+		},
+		{
+			description:    "include profile",
+			deviceProfiles: discovery.DeviceProfiles{0: a100},
+			filter:         EnumerateFilter{IncludeProfiles: []string{"1g.5gb+me"}},
+			want: map[string]types.MigConfig{
+				"all-1x3g.20gb_1x2g.10gb_1x1g.5gb_1x1g.5gb.me": {"3g.20gb": 1, "2g.10gb": 1, "1g.5gb": 1, "1g.5gb+me": 1},
+			},
+			notWant: []string{"all-1x4g.20gb_1x3g.20gb"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			configs, err := buildEnumeratedConfigs(tc.deviceProfiles, map[string]bool{idOther: true}, tc.filter)
+			require.NoError(t, err)
+			for name, migDevices := range tc.want {
+				require.Contains(t, configs, name)
+				require.Len(t, configs[name], 1)
+				assert.Nil(t, configs[name][0].DeviceFilter)
+				assert.Equal(t, migDevices, configs[name][0].MigDevices)
+			}
+			for _, name := range tc.notWant {
+				assert.NotContains(t, configs, name)
+			}
+			for name, cfg := range configs {
+				for _, include := range tc.filter.IncludeProfiles {
+					assert.Contains(t, cfg[0].MigDevices, include, "config %s", name)
+				}
+				if tc.filter.MinSlices > 0 {
+					assert.NotContains(t, name, "x1g.", "config %s", name)
+				}
+			}
+		})
+	}
+
+	t.Run("heterogeneous device types get device filters", func(t *testing.T) {
+		deviceProfiles := discovery.DeviceProfiles{0: a100, 1: a30[0]}
+		allDeviceIDs := map[string]bool{a100[0].DeviceID.String(): true, idA30: true}
+		configs, err := buildEnumeratedConfigs(deviceProfiles, allDeviceIDs, EnumerateFilter{BaseProfilesOnly: true})
+		require.NoError(t, err)
+
+		require.Contains(t, configs, "all-1x2g.12gb_2x1g.6gb")
+		assert.Equal(t, []string{idA30}, configs["all-1x2g.12gb_2x1g.6gb"][0].DeviceFilter)
+		for name, cfg := range configs {
+			if strings.Contains(name, "gb_") && !strings.Contains(name, "12gb") && !strings.Contains(name, "6gb") {
+				assert.Equal(t, []string{a100[0].DeviceID.String()}, cfg[0].DeviceFilter, "config %s", name)
+			}
+		}
+	})
+}
+
+func TestGenerateEnumeratedConfigSpecOnSimulatedNode(t *testing.T) {
+	nvmllib, deviceProfiles := newSimulatedNode(t, []string{"A30-PCIE-24GB", "H100-SXM5-80GB", "RTX-PRO-6000-96GB"})
+
+	spec, err := GenerateConfigSpec(WithDeviceProfiles(deviceProfiles), WithEnumeration(EnumerateFilter{}))
+	require.NoError(t, err)
+	require.Contains(t, spec.MigConfigs, "all-1x4g.40gb_1x3g.40gb")
+	require.Contains(t, spec.MigConfigs, "all-1x3g.40gb_1x2g.20gb_2x1g.10gb")
+
+	// Every enumerated config can be applied given the placement constraints of the devices it selects.
+	enumerated := &v1.Spec{Version: spec.Version, MigConfigs: map[string]v1.MigConfigSpecSlice{}}
+	for name, cfg := range spec.MigConfigs {
+		if strings.Contains(name, "x") {
+			enumerated.MigConfigs[name] = cfg
+		}
+	}
+	require.NotEmpty(t, enumerated.MigConfigs)
+	applyConfigs(t, nvmllib, enumerated)
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	log "github.com/sirupsen/logrus"
+
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// isMediaExtensionProfile returns true if the profile has media extensions (+me or +me.all).
+func isMediaExtensionProfile(pInfo discovery.ProfileInfo) bool {
+	info := pInfo.Profile.GetInfo()
+	if len(info.Attributes) != 1 || len(info.NegAttributes) != 0 {
+		return false
+	}
+	return info.Attributes[0] == nvdev.AttributeMediaExtensions || info.Attributes[0] == nvdev.AttributeMediaExtensionsAll
+}
+
+// findMediaExtensionFiller returns the profile used for the rest of a GPU next to
+// instances of a media extension profile: the profile of the same size without
+// media extensions (-me) if it takes all of them (+me.all), or the base profile
+// of the same size otherwise.
+func findMediaExtensionFiller(me discovery.ProfileInfo, profiles map[string]discovery.ProfileInfo) (discovery.ProfileInfo, bool) {
+	meInfo := me.Profile.GetInfo()
+	for _, name := range slices.Sorted(maps.Keys(profiles)) {
+		pInfo := profiles[name]
+		info := pInfo.Profile.GetInfo()
+		if info.G != meInfo.G || info.GB != meInfo.GB || len(info.Attributes) != 0 {
+			continue
+		}
+		if meInfo.Attributes[0] == nvdev.AttributeMediaExtensionsAll {
+			if slices.Equal(info.NegAttributes, []string{nvdev.AttributeMediaExtensions}) {
+				return pInfo, true
+			}
+			continue
+		}
+		if len(info.NegAttributes) == 0 {
+			return pInfo, true
+		}
+	}
+	return discovery.ProfileInfo{}, false
+}
+
+// mediaExtensionCombination returns the combination with the most instances of a
+// media extension profile and then the most instances of its filler.
+func mediaExtensionCombination(me, filler discovery.ProfileInfo) (types.MigConfig, error) {
+	if len(me.Placements) == 0 || len(filler.Placements) == 0 {
+		// Without placements, assume instances of the same size can take each other's place.
+		return types.MigConfig{me.Name: me.MaxCount, filler.Name: filler.MaxCount - me.MaxCount}, nil
+	}
+
+	combinations, err := enumerateCombinations([]discovery.ProfileInfo{me, filler})
+	if err != nil {
+		return nil, err
+	}
+	best := types.MigConfig{}
+	for _, combination := range combinations {
+		if combination[me.Name] == me.MaxCount && combination[filler.Name] >= best[filler.Name] {
+			best = combination
+		}
+	}
+	return best, nil
+}
+
+// buildMediaExtensionConfigs creates a config for every media extension profile
+// on each device type, with as many instances of it as possible and the rest of
+// the GPU used by the same size of profile without them (e.g.
+// "all-6x1g.10gb_1x1g.10gb.me"). The "all-<profile>" configs of these profiles
+// leave the rest of the GPU unused.
+func buildMediaExtensionConfigs(deviceProfiles discovery.DeviceProfiles, allDeviceIDs map[string]bool) (map[string]migspec.MigConfigSpecSlice, error) {
+	deviceTypes := groupProfilesByDeviceID(deviceProfiles)
+	combinations := newCombinationConfigs()
+
+	for _, deviceID := range slices.Sorted(maps.Keys(deviceTypes)) {
+		profiles := deviceTypes[deviceID]
+		for _, name := range slices.Sorted(maps.Keys(profiles)) {
+			me := profiles[name]
+			if !isMediaExtensionProfile(me) {
+				continue
+			}
+
+			filler, found := findMediaExtensionFiller(me, profiles)
+			if !found {
+				log.Debugf("No profile to use alongside %s on device %s, skipping its media extension config", name, deviceID)
+				continue
+			}
+
+			combination, err := mediaExtensionCombination(me, filler)
+			if err != nil {
+				return nil, fmt.Errorf("error combining %s with %s for device %s: %w", name, filler.Name, deviceID, err)
+			}
+			if combination[me.Name] == 0 || combination[filler.Name] <= 0 {
+				log.Debugf("No room for %s alongside %s on device %s, skipping its media extension config", filler.Name, name, deviceID)
+				continue
+			}
+
+			combinations.add(deviceID, combination, profiles)
+			log.Infof("Generated media extension config for profile '%s' on device %s: %v", name, deviceID, combination)
+		}
+	}
+
+	return combinations.specs(allDeviceIDs), nil
+}
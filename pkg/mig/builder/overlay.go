@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+)
+
+// Precedence decides which MIG config is kept when a user-defined config has
+// the same name as a generated one.
+type Precedence string
+
+const (
+	// PrecedenceUser keeps the user-defined config.
+	PrecedenceUser Precedence = "user"
+	// PrecedenceGenerated keeps the generated config.
+	PrecedenceGenerated Precedence = "generated"
+)
+
+// ParsePrecedence converts a string into a Precedence.
+func ParsePrecedence(s string) (Precedence, error) {
+	switch p := Precedence(s); p {
+	case PrecedenceUser, PrecedenceGenerated:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown precedence '%s': must be '%s' or '%s'", s, PrecedenceUser, PrecedenceGenerated)
+}
+
+// UserConfigs holds the named MIG configs of a user-supplied file.
+type UserConfigs struct {
+	Source string
+	Spec   *migspec.Spec
+}
+
+// ParseUserConfigsFile reads the named MIG configs of a user-supplied file, or
+// of every file of a directory, along with the files they include.
+func ParseUserConfigsFile(file string) (*UserConfigs, error) {
+	spec, err := migspec.LoadConfigFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return &UserConfigs{Source: file, Spec: spec}, nil
+}
+
+// WithUserConfigs overlays the MIG configs of user-supplied files on the
+// generated ones. A config of a later file replaces the config with the same
+// name of an earlier one, while the precedence decides between a user-defined
+// and a generated config with the same name.
+func WithUserConfigs(precedence Precedence, userConfigs ...*UserConfigs) Option {
+	return func(o *options) {
+		o.precedence = precedence
+		o.userConfigs = append(o.userConfigs, userConfigs...)
+	}
+}
+
+// overlayUserConfigs merges the configs of user-supplied files into the
+// generated configs, warning about every config whose name is used more than once.
+func overlayUserConfigs(configs map[string]migspec.MigConfigSpecSlice, precedence Precedence, userConfigs []*UserConfigs) {
+	generated := maps.Clone(configs)
+	sources := make(map[string]string)
+
+	for _, uc := range userConfigs {
+		for _, name := range slices.Sorted(maps.Keys(uc.Spec.MigConfigs)) {
+			if source, exists := sources[name]; exists {
+				log.Warnf("MIG config '%s' of %s replaces the one of %s", name, uc.Source, source)
+			} else if _, exists := generated[name]; exists {
+				if precedence == PrecedenceGenerated {
+					log.Warnf("MIG config '%s' of %s is ignored in favor of the generated one", name, uc.Source)
+					continue
+				}
+				log.Warnf("MIG config '%s' of %s replaces the generated one", name, uc.Source)
+			}
+			configs[name] = uc.Spec.MigConfigs[name]
+			sources[name] = uc.Source
+		}
+	}
+}
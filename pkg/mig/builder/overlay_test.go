@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func writeUserConfigs(t *testing.T, name, contents string) string {
+	file := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(file, []byte(contents), 0600))
+	return file
+}
+
+func userConfig(migDevices types.MigConfig) migspec.MigConfigSpecSlice {
+	return migspec.MigConfigSpecSlice{{Devices: "all", MigEnabled: true, MigDevices: migDevices}}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	precedence, err := ParsePrecedence("generated")
+	require.Nil(t, err)
+	require.Equal(t, PrecedenceGenerated, precedence)
+
+	_, err = ParsePrecedence("newest")
+	require.NotNil(t, err, "Unexpected success from ParsePrecedence")
+}
+
+func TestParseUserConfigsFile(t *testing.T) {
+	file := writeUserConfigs(t, "site.yaml", `
+version: v1
+mig-configs:
+  inference:
+  - devices: all
+    mig-enabled: true
+    mig-devices:
+      1g.5gb: 7
+`)
+	userConfigs, err := ParseUserConfigsFile(file)
+	require.Nil(t, err, "Unexpected failure from ParseUserConfigsFile")
+	require.Equal(t, file, userConfigs.Source)
+	require.Equal(t, userConfig(types.MigConfig{"1g.5gb": 7}), userConfigs.Spec.MigConfigs["inference"])
+
+	_, err = ParseUserConfigsFile(writeUserConfigs(t, "bad.yaml", "version: v2\n"))
+	require.NotNil(t, err, "Unexpected success parsing an unknown version")
+	_, err = ParseUserConfigsFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NotNil(t, err, "Unexpected success parsing a missing file")
+}
+
+func TestWithUserConfigs(t *testing.T) {
+	deviceProfiles := discovery.DeviceProfiles{0: gpuProfiles["A100-80GB"]}
+
+	site := &UserConfigs{Source: "site.yaml", Spec: &migspec.Spec{
+		Version: migspec.Version,
+		MigConfigs: map[string]migspec.MigConfigSpecSlice{
+			"all-disabled": userConfig(types.MigConfig{}),
+			"inference":    userConfig(types.MigConfig{"1g.5gb": 7}),
+		},
+	}}
+	team := &UserConfigs{Source: "team.yaml", Spec: &migspec.Spec{
+		Version: migspec.Version,
+		MigConfigs: map[string]migspec.MigConfigSpecSlice{
+			"inference": userConfig(types.MigConfig{"1g.5gb": 4, "3g.20gb": 1}),
+		},
+	}}
+
+	generated, err := buildMigConfigSpec(deviceProfiles)
+	require.Nil(t, err)
+
+	testCases := []struct {
+		description         string
+		precedence          Precedence
+		expectedAllDisabled migspec.MigConfigSpecSlice
+	}{
+		{"User Precedence", PrecedenceUser, site.Spec.MigConfigs["all-disabled"]},
+		{"Generated Precedence", PrecedenceGenerated, generated.MigConfigs["all-disabled"]},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			spec, err := buildMigConfigSpec(deviceProfiles, WithUserConfigs(tc.precedence, site, team))
+			require.Nil(t, err, "Unexpected failure from buildMigConfigSpec")
+
+			require.Len(t, spec.MigConfigs, len(generated.MigConfigs)+1)
+			require.Equal(t, tc.expectedAllDisabled, spec.MigConfigs["all-disabled"])
+			require.Equal(t, generated.MigConfigs["all-1g.10gb"], spec.MigConfigs["all-1g.10gb"])
+
+			// Later files take precedence over earlier ones.
+			require.Equal(t, team.Spec.MigConfigs["inference"], spec.MigConfigs["inference"])
+		})
+	}
+}
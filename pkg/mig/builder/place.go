@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// PlaceGpuInstances returns the GPU instances of a 'GpuInstancesConfig' with a
+// placement for each one declared without one, chosen among the placements
+// of its profile left free by the others. The GPU instances declared with a
+// placement keep it, and must be placed where their profile allows.
+func PlaceGpuInstances(profiles []discovery.ProfileInfo, gpuInstances types.GpuInstancesConfig) (types.GpuInstancesConfig, error) {
+	placed := slices.Clone(gpuInstances)
+
+	var used uint64
+	var unplaced []int
+	options := make([][]nvml.GpuInstancePlacement, len(placed))
+	for i, gi := range placed {
+		pInfo, found := discovery.LookupProfile(profiles, gi.Profile)
+		if !found {
+			return nil, fmt.Errorf("profile %v is not supported by the GPU", gi.Profile)
+		}
+		if err := pInfo.CheckPlacementMasks(); err != nil {
+			return nil, err
+		}
+
+		if gi.Placement == nil {
+			if len(pInfo.Placements) == 0 {
+				return nil, fmt.Errorf("profile %v has no known placements", pInfo.Name)
+			}
+			options[i] = pInfo.Placements
+			unplaced = append(unplaced, i)
+			continue
+		}
+
+		j := slices.IndexFunc(pInfo.Placements, func(p nvml.GpuInstancePlacement) bool { return int(p.Start) == *gi.Placement })
+		if j < 0 {
+			return nil, fmt.Errorf("placement %v is not possible for GPU instance '%v'", *gi.Placement, gi.Profile)
+		}
+		mask := discovery.PlacementMask(pInfo.Placements[j])
+		if used&mask != 0 {
+			return nil, fmt.Errorf("placement %v of GPU instance '%v' overlaps another GPU instance", *gi.Placement, gi.Profile)
+		}
+		used |= mask
+	}
+
+	// Place the GPU instances without a placement one after the other,
+	// backtracking when one of them no longer fits.
+	var place func(next int, used uint64) bool
+	place = func(next int, used uint64) bool {
+		if next == len(unplaced) {
+			return true
+		}
+		i := unplaced[next]
+		for _, placement := range options[i] {
+			mask := discovery.PlacementMask(placement)
+			if used&mask != 0 {
+				continue
+			}
+			start := int(placement.Start)
+			placed[i].Placement = &start
+			if place(next+1, used|mask) {
+				return true
+			}
+		}
+		placed[i].Placement = nil
+		return false
+	}
+	if !place(0, used) {
+		return nil, fmt.Errorf("GPU instances %v do not fit on the GPU", gpuInstances)
+	}
+
+	return placed, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestPlaceGpuInstances(t *testing.T) {
+	at := func(start int) *int { return &start }
+
+	testCases := []struct {
+		description   string
+		gpuInstances  types.GpuInstancesConfig
+		expected      types.GpuInstancesConfig
+		expectedError string
+	}{
+		{
+			description: "First free placements",
+			gpuInstances: types.GpuInstancesConfig{
+				{Profile: "3g.20gb", ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+				{Profile: "1g.5gb"},
+			},
+			expected: types.GpuInstancesConfig{
+				{Profile: "3g.20gb", Placement: at(0), ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+				{Profile: "1g.5gb", Placement: at(4)},
+			},
+		},
+		{
+			description: "Around declared placements",
+			gpuInstances: types.GpuInstancesConfig{
+				{Profile: "2g.10gb"},
+				{Profile: "1g.5gb", Placement: at(0)},
+				{Profile: "3g.20gb"},
+			},
+			expected: types.GpuInstancesConfig{
+				{Profile: "2g.10gb", Placement: at(2)},
+				{Profile: "1g.5gb", Placement: at(0)},
+				{Profile: "3g.20gb", Placement: at(4)},
+			},
+		},
+		{
+			description: "Backtracking",
+			gpuInstances: types.GpuInstancesConfig{
+				{Profile: "1g.5gb"},
+				{Profile: "4g.20gb"},
+			},
+			expected: types.GpuInstancesConfig{
+				{Profile: "1g.5gb", Placement: at(4)},
+				{Profile: "4g.20gb", Placement: at(0)},
+			},
+		},
+		{
+			description:   "Impossible placement",
+			gpuInstances:  types.GpuInstancesConfig{{Profile: "3g.20gb", Placement: at(2)}},
+			expectedError: "placement 2 is not possible",
+		},
+		{
+			description: "Overlapping placements",
+			gpuInstances: types.GpuInstancesConfig{
+				{Profile: "3g.20gb", Placement: at(0)},
+				{Profile: "2g.10gb", Placement: at(2)},
+			},
+			expectedError: "overlaps another GPU instance",
+		},
+		{
+			description: "Too many GPU instances",
+			gpuInstances: types.GpuInstancesConfig{
+				{Profile: "4g.20gb"},
+				{Profile: "3g.20gb"},
+				{Profile: "1g.5gb"},
+			},
+			expectedError: "do not fit on the GPU",
+		},
+		{
+			description:   "Unsupported profile",
+			gpuInstances:  types.GpuInstancesConfig{{Profile: "2g.40gb"}},
+			expectedError: "profile 2g.40gb is not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			placed, err := PlaceGpuInstances(placedProfiles(), tc.gpuInstances)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, placed)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math/bits"
+	"slices"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// ResolveMigDevices resolves MIG device requests against the profiles
+// discovered on a GPU, returning the concrete counts of each profile to
+// create. Among the sets of instances that fit on the GPU given the
+// placements of each profile and that satisfy every request, the one using
+// the most memory slices is chosen, preferring fewer (and so larger)
+// instances when several use as many.
+//
+// Profiles are requested by any name 'types.CanonicalMigProfileName' maps to
+// a profile of the GPU (e.g. "1g.6gb" for "1g.5gb"), and resolve to that
+// profile. A Compute Instance profile (e.g. "1c.3g.20gb") is requested as the
+// GPU instance profile it splits up, as each of its MIG devices is created in
+// a GPU instance of its own.
+func ResolveMigDevices(profiles []discovery.ProfileInfo, requests types.MigDeviceRequests) (types.MigConfig, error) {
+	var migProfiles []nvdev.MigProfile
+	for _, pInfo := range profiles {
+		migProfiles = append(migProfiles, pInfo.Profile)
+		for _, ci := range pInfo.ComputeInstances {
+			migProfiles = append(migProfiles, ci.Profile)
+		}
+	}
+
+	var requested []discovery.ProfileInfo
+	var names []string
+	var lower, upper []int
+	for _, name := range slices.Sorted(maps.Keys(requests)) {
+		canonicalName, err := types.CanonicalMigProfileName(name, migProfiles)
+		if err != nil {
+			return nil, err
+		}
+		pInfo, profileName, found := lookupRequestedProfile(profiles, canonicalName)
+		if !found {
+			return nil, fmt.Errorf("profile %v is not supported by the GPU", name)
+		}
+		if slices.Contains(names, profileName) {
+			return nil, fmt.Errorf("profile %v is requested more than once", profileName)
+		}
+		if len(pInfo.Placements) == 0 {
+			return nil, fmt.Errorf("profile %v has no known placements", pInfo.Name)
+		}
+
+		lo, hi := countBounds(requests[name], pInfo.MaxCount)
+		if lo > pInfo.MaxCount {
+			return nil, fmt.Errorf("%d instance(s) of profile %v requested, but at most %d fit", lo, profileName, pInfo.MaxCount)
+		}
+		requested = append(requested, pInfo)
+		names = append(names, profileName)
+		lower = append(lower, lo)
+		upper = append(upper, hi)
+	}
+
+	var candidates []candidate
+	for i, pInfo := range requested {
+		if err := pInfo.CheckPlacementMasks(); err != nil {
+			return nil, err
+		}
+		for _, placement := range pInfo.Placements {
+			candidates = append(candidates, candidate{i, placement})
+		}
+	}
+
+	// Visit every set of non-overlapping candidates within the upper bounds,
+	// keeping the best counts satisfying the lower bounds.
+	var best []int
+	bestSlots, bestInstances := -1, 0
+	counts := make([]int, len(requested))
+	instances := 0
+	var visit func(next int, used uint64)
+	visit = func(next int, used uint64) {
+		if satisfies(counts, lower) {
+			slots := bits.OnesCount64(used)
+			if cmp.Or(cmp.Compare(slots, bestSlots), cmp.Compare(bestInstances, instances)) > 0 {
+				best, bestSlots, bestInstances = slices.Clone(counts), slots, instances
+			}
+		}
+		for i := next; i < len(candidates); i++ {
+			c := candidates[i]
+			mask := discovery.PlacementMask(c.placement)
+			if used&mask != 0 || counts[c.profile] >= upper[c.profile] {
+				continue
+			}
+			counts[c.profile]++
+			instances++
+			visit(i+1, used|mask)
+			counts[c.profile]--
+			instances--
+		}
+	}
+	visit(0, 0)
+
+	if best == nil {
+		return nil, fmt.Errorf("requested MIG devices %v do not fit on the GPU", requests)
+	}
+
+	migDevices := make(types.MigConfig)
+	for i, count := range best {
+		if count > 0 {
+			migDevices[names[i]] = count
+		}
+	}
+	if len(migDevices) == 0 {
+		return nil, fmt.Errorf("requested MIG devices %v resolve to no MIG devices", requests)
+	}
+	return migDevices, nil
+}
+
+// lookupRequestedProfile returns the GPU instance profile of a device placing
+// the MIG devices of a profile named as in a MIG config, along with the name of
+// that profile on the device. The name may be that of a GPU instance profile or
+// of a Compute Instance profile splitting one up.
+func lookupRequestedProfile(profiles []discovery.ProfileInfo, name string) (discovery.ProfileInfo, string, bool) {
+	if pInfo, found := discovery.LookupProfile(profiles, name); found {
+		return pInfo, pInfo.Name, true
+	}
+	for _, pInfo := range profiles {
+		for _, ci := range pInfo.ComputeInstances {
+			if ci.Name == name || ci.Profile.Matches(name) {
+				return pInfo, ci.Name, true
+			}
+		}
+	}
+	return discovery.ProfileInfo{}, "", false
+}
+
+// countBounds returns the range of instance counts allowed by a request for a
+// profile of which at most maxCount instances fit on the GPU.
+func countBounds(count types.MigDeviceCount, maxCount int) (int, int) {
+	if count.Percent != 0 {
+		n := maxCount * count.Percent / 100
+		return n, n
+	}
+	if count.Max == types.MigDeviceCountUnbounded || count.Max > maxCount {
+		return count.Min, maxCount
+	}
+	return count.Min, count.Max
+}
+
+func satisfies(counts, lower []int) bool {
+	for i := range counts {
+		if counts[i] < lower[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestResolveMigDevices(t *testing.T) {
+	testCases := []struct {
+		description   string
+		requests      string
+		expected      types.MigConfig
+		expectedError string
+	}{
+		{
+			description: "Exact counts",
+			requests:    `{3g.20gb: 1, 2g.10gb: 1}`,
+			expected:    types.MigConfig{"3g.20gb": 1, "2g.10gb": 1},
+		},
+		{
+			description: "Fill the rest",
+			requests:    `{3g.20gb: 1, 1g.5gb: fill}`,
+			expected:    types.MigConfig{"3g.20gb": 1, "1g.5gb": 4},
+		},
+		{
+			description: "Fill a single profile",
+			requests:    `{1g.10gb: "*"}`,
+			expected:    types.MigConfig{"1g.10gb": 4},
+		},
+		{
+			description: "Larger profiles are preferred",
+			requests:    `{3g.20gb: max, 2g.10gb: max}`,
+			expected:    types.MigConfig{"3g.20gb": 2},
+		},
+		{
+			description: "Range",
+			requests:    `{2g.10gb: {min: 1}, 1g.5gb: fill}`,
+			expected:    types.MigConfig{"2g.10gb": 3, "1g.5gb": 1},
+		},
+		{
+			description: "Range with a max",
+			requests:    `{2g.10gb: {min: 1, max: 2}, 1g.5gb: fill}`,
+			expected:    types.MigConfig{"2g.10gb": 2, "1g.5gb": 3},
+		},
+		{
+			description: "Percentage",
+			requests:    `{1g.10gb: 50%}`,
+			expected:    types.MigConfig{"1g.10gb": 2},
+		},
+		{
+			description:   "Percentage of no instances",
+			requests:      `{7g.40gb: 50%}`,
+			expectedError: "resolve to no MIG devices",
+		},
+		{
+			description:   "Too many instances",
+			requests:      `{3g.20gb: 3}`,
+			expectedError: "at most 2 fit",
+		},
+		{
+			description:   "Requests do not fit",
+			requests:      `{4g.20gb: 1, 3g.20gb: 1, 2g.10gb: {min: 1}}`,
+			expectedError: "do not fit",
+		},
+		{
+			description:   "Unsupported profile",
+			requests:      `{2g.40gb: fill}`,
+			expectedError: `MIG profile "2g.40gb" not found on this GPU`,
+		},
+		{
+			description: "Compute Instance profile",
+			requests:    `{1c.3g.20gb: 1, 1g.5gb: fill}`,
+			expected:    types.MigConfig{"1c.3g.20gb": 1, "1g.5gb": 4},
+		},
+		{
+			description: "Compute Instance profiles of the same GPU instance profile",
+			requests:    `{1c.3g.20gb: 1, 2c.3g.20gb: 1, 3g.20gb: fill}`,
+			expected:    types.MigConfig{"1c.3g.20gb": 1, "2c.3g.20gb": 1},
+		},
+		{
+			description: "Compute Instance profile filling the GPU",
+			requests:    `{2c.3g.20gb: fill}`,
+			expected:    types.MigConfig{"2c.3g.20gb": 2},
+		},
+		{
+			description:   "Compute Instance profile requested more than once",
+			requests:      `{1c.1g.5gb: 1, 1g.5gb: fill}`,
+			expectedError: "profile 1g.5gb is requested more than once",
+		},
+		{
+			description:   "Unsupported Compute Instance profile",
+			requests:      `{1c.2g.10gb: 1, 1g.5gb: fill}`,
+			expectedError: `MIG profile "1c.2g.10gb" not found on this GPU`,
+		},
+		{
+			description: "Memory rounded differently",
+			requests:    `{3g.20gb: 1, 1g.6gb: fill}`,
+			expected:    types.MigConfig{"3g.20gb": 1, "1g.5gb": 4},
+		},
+		{
+			description: "Compute Instance profile with memory rounded differently",
+			requests:    `{1c.3g.21gb: 1, 1c.1g.5gb: fill}`,
+			expected:    types.MigConfig{"1c.3g.20gb": 1, "1g.5gb": 4},
+		},
+	}
+
+	profiles := placedProfiles()
+	for i := range profiles {
+		if profiles[i].Name == "3g.20gb" {
+			profiles[i].ComputeInstances = mockComputeInstances(3, 20, 1, 2)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var requests types.MigDeviceRequests
+			require.NoError(t, yaml.Unmarshal([]byte(tc.requests), &requests))
+
+			migDevices, err := ResolveMigDevices(profiles, requests)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, migDevices)
+		})
+	}
+}
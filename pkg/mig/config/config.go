@@ -17,7 +17,9 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -32,6 +34,10 @@ import (
 type Manager interface {
 	GetMigConfig(gpu int) (types.MigConfig, error)
 	SetMigConfig(gpu int, config types.MigConfig) error
+	CanonicalizeMigConfig(gpu int, config types.MigConfig) (types.CanonicalMigConfig, error)
+	GetGpuInstances(gpu int) (types.GpuInstancesConfig, error)
+	CanonicalizeGpuInstances(gpu int, config types.GpuInstancesConfig) (types.CanonicalGpuInstancesConfig, error)
+	SetGpuInstances(gpu int, config types.GpuInstancesConfig) error
 	ClearMigConfig(gpu int) error
 }
 
@@ -46,7 +52,12 @@ var _ Manager = (*nvmlMigConfigManager)(nil)
 // NVML GI/CI profile IDs for the given GPU. Global ParseMigProfile can pick IDs from
 // another GPU when names collide across devices.
 func resolveMigProfileOnDevice(profiles []nvdevlib.MigProfile, mp *types.MigProfile) (*types.MigProfile, error) {
-	key := mp.String()
+	return resolveMigProfileNameOnDevice(profiles, mp.String())
+}
+
+// resolveMigProfileNameOnDevice maps the name of a MIG profile (e.g. "1c.3g.20gb")
+// to the NVML GI/CI profile IDs for the given GPU.
+func resolveMigProfileNameOnDevice(profiles []nvdevlib.MigProfile, key string) (*types.MigProfile, error) {
 	for _, p := range profiles {
 		if p.Matches(key) {
 			info := p.GetInfo()
@@ -98,6 +109,53 @@ func (m *nvmlMigConfigManager) GetMigConfig(gpu int) (types.MigConfig, error) {
 	return migConfig, nil
 }
 
+// CanonicalizeMigConfig names the profiles of a 'MigConfig' as the MIG
+// profiles of a GPU are named, so that it can be compared with the MIG config
+// returned by 'GetMigConfig'.
+func (m *nvmlMigConfigManager) CanonicalizeMigConfig(gpu int, config types.MigConfig) (types.CanonicalMigConfig, error) {
+	device, ret := m.nvml.DeviceGetHandleByIndex(gpu)
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("error getting device handle: %v", ret)
+	}
+
+	profiles, err := m.getMigProfiles(device)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewCanonicalMigConfig(config, profiles)
+}
+
+// CanonicalizeGpuInstances names the profiles of a 'GpuInstancesConfig' as
+// the MIG profiles of a GPU are named, so that it can be compared with the GPU
+// instances returned by 'GetGpuInstances'.
+func (m *nvmlMigConfigManager) CanonicalizeGpuInstances(gpu int, config types.GpuInstancesConfig) (types.CanonicalGpuInstancesConfig, error) {
+	device, ret := m.nvml.DeviceGetHandleByIndex(gpu)
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("error getting device handle: %v", ret)
+	}
+
+	profiles, err := m.getMigProfiles(device)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewCanonicalGpuInstancesConfig(config, profiles)
+}
+
+// getMigProfiles lists the MIG profiles of a GPU.
+func (m *nvmlMigConfigManager) getMigProfiles(device nvml.Device) ([]nvdevlib.MigProfile, error) {
+	nvdev, err := nvdevlib.New(m.nvml).NewDevice(device)
+	if err != nil {
+		return nil, fmt.Errorf("error creating device wrapper: %w", err)
+	}
+	profiles, err := nvdev.GetMigProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("error listing MIG profiles on device: %w", err)
+	}
+	return profiles, nil
+}
+
 func (m *nvmlMigConfigManager) SetMigConfig(gpu int, config types.MigConfig) error {
 	device, ret := m.nvml.DeviceGetHandleByIndex(gpu)
 	if ret != nvml.SUCCESS {
@@ -113,13 +171,9 @@ func (m *nvmlMigConfigManager) SetMigConfig(gpu int, config types.MigConfig) err
 	if err != nil {
 		return fmt.Errorf("error asserting MIG enabled: %v", err)
 	}
-	nvdev, err := nvdevlib.New(m.nvml).NewDevice(device)
-	if err != nil {
-		return fmt.Errorf("error creating device wrapper: %w", err)
-	}
-	profiles, err := nvdev.GetMigProfiles()
+	profiles, err := m.getMigProfiles(device)
 	if err != nil {
-		return fmt.Errorf("error listing MIG profiles on device: %w", err)
+		return err
 	}
 
 	err = iteratePermutationsUntilSuccess(config, func(mps []*types.MigProfile) error {
@@ -217,6 +271,171 @@ func (m *nvmlMigConfigManager) SetMigConfig(gpu int, config types.MigConfig) err
 	return nil
 }
 
+// GetGpuInstances returns the GPU instances of a GPU along with their placement
+// and compute instances, ordered by placement. GPU instances without any
+// compute instances are left out, as they are from 'GetMigConfig'.
+func (m *nvmlMigConfigManager) GetGpuInstances(gpu int) (types.GpuInstancesConfig, error) {
+	device, ret := m.nvml.DeviceGetHandleByIndex(gpu)
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("error getting device handle: %v", ret)
+	}
+
+	deviceMemory, ret := device.GetMemoryInfo()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("error getting device memory: %v", ret)
+	}
+
+	err := m.nvlib.Mig.Device(device).AssertMigEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("error asserting MIG enabled: %v", err)
+	}
+
+	gpuInstances := types.GpuInstancesConfig{}
+	err = m.nvlib.Mig.Device(device).WalkGpuInstances(func(gi nvml.GpuInstance, giProfileID int, giProfileInfo nvml.GpuInstanceProfileInfo) error {
+		giInfo, ret := gi.GetInfo()
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("error getting GPU instance info for '%v': %v", giProfileID, ret)
+		}
+
+		placement := int(giInfo.Placement.Start)
+		gpuInstance := types.GpuInstanceConfig{Placement: &placement}
+		err := m.nvlib.Mig.GpuInstance(gi).WalkComputeInstances(func(ci nvml.ComputeInstance, ciProfileID int, ciEngProfileID int, ciProfileInfo nvml.ComputeInstanceProfileInfo) error {
+			mp, err := types.NewMigProfile(giProfileID, ciProfileID, ciEngProfileID, giProfileInfo.MemorySizeMB, deviceMemory.Total)
+			if err != nil {
+				return fmt.Errorf("error creating new MIG profile for (%v, %v, %v): %v", giProfileID, ciProfileID, ciEngProfileID, err)
+			}
+			giProfile := mp.MigProfileInfo
+			giProfile.C = giProfile.G
+			gpuInstance.Profile = giProfile.String()
+			gpuInstance.ComputeInstances = append(gpuInstance.ComputeInstances, mp.String())
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error walking compute instances for '%v': %v", giProfileID, err)
+		}
+
+		if len(gpuInstance.ComputeInstances) == 0 {
+			log.Debugf("Skipping GPU instance of profile '%v' at placement %v without compute instances", giProfileID, placement)
+			return nil
+		}
+		if len(gpuInstance.ComputeInstances) == 1 && gpuInstance.ComputeInstances[0] == gpuInstance.Profile {
+			gpuInstance.ComputeInstances = nil
+		}
+		gpuInstances = append(gpuInstances, gpuInstance)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking gpu instances for '%v': %v", gpu, err)
+	}
+
+	slices.SortFunc(gpuInstances, func(a, b types.GpuInstanceConfig) int {
+		return cmp.Compare(*a.Placement, *b.Placement)
+	})
+
+	return gpuInstances, nil
+}
+
+// SetGpuInstances replaces the MIG devices of a GPU with the GPU instances of
+// a 'GpuInstancesConfig', creating each one at its placement (if any) along
+// with its compute instances. GPU instances with a placement are created first.
+// Its profiles are first named as the GPU names them (see 'CanonicalGpuInstancesConfig').
+func (m *nvmlMigConfigManager) SetGpuInstances(gpu int, config types.GpuInstancesConfig) error {
+	device, ret := m.nvml.DeviceGetHandleByIndex(gpu)
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("error getting device handle: %v", ret)
+	}
+
+	err := m.nvlib.Mig.Device(device).AssertMigEnabled()
+	if err != nil {
+		return fmt.Errorf("error asserting MIG enabled: %v", err)
+	}
+	profiles, err := m.getMigProfiles(device)
+	if err != nil {
+		return err
+	}
+	canonical, err := types.NewCanonicalGpuInstancesConfig(config, profiles)
+	if err != nil {
+		return err
+	}
+
+	err = m.ClearMigConfig(gpu)
+	if err != nil {
+		return fmt.Errorf("error clearing MigConfig: %v", err)
+	}
+
+	var placed, unplaced types.GpuInstancesConfig
+	for _, g := range canonical {
+		if g.Placement != nil {
+			placed = append(placed, g)
+		} else {
+			unplaced = append(unplaced, g)
+		}
+	}
+
+	for _, g := range append(placed, unplaced...) {
+		err := createGpuInstance(device, profiles, g)
+		if err != nil {
+			e := m.ClearMigConfig(gpu)
+			if e != nil {
+				log.Errorf("Error clearing MIG config on GPU %d, erroneous devices may persist", gpu)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createGpuInstance creates a GPU instance of a 'GpuInstancesConfig' along with its compute instances.
+func createGpuInstance(device nvml.Device, profiles []nvdevlib.MigProfile, g types.GpuInstanceConfig) error {
+	resolved, err := resolveMigProfileNameOnDevice(profiles, g.Profile)
+	if err != nil {
+		return err
+	}
+
+	giProfileInfo, ret := device.GetGpuInstanceProfileInfo(resolved.GIProfileID)
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("error getting GPU instance profile info for '%v': %v", g.Profile, ret)
+	}
+
+	var gi nvml.GpuInstance
+	if g.Placement == nil {
+		gi, ret = device.CreateGpuInstance(&giProfileInfo)
+	} else {
+		placements, ret := device.GetGpuInstancePossiblePlacements(&giProfileInfo)
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("error getting possible placements for '%v': %v", g.Profile, ret)
+		}
+		i := slices.IndexFunc(placements, func(p nvml.GpuInstancePlacement) bool { return int(p.Start) == *g.Placement })
+		if i < 0 {
+			return fmt.Errorf("placement %v is not possible for GPU instance '%v'", *g.Placement, g.Profile)
+		}
+		gi, ret = device.CreateGpuInstanceWithPlacement(&giProfileInfo, &placements[i])
+	}
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("error creating GPU instance for '%v': %v", g.Profile, ret)
+	}
+
+	for _, ci := range g.GetComputeInstances() {
+		resolved, err := resolveMigProfileNameOnDevice(profiles, ci)
+		if err != nil {
+			return err
+		}
+
+		ciProfileInfo, ret := gi.GetComputeInstanceProfileInfo(resolved.CIProfileID, resolved.CIEngProfileID)
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("error getting Compute instance profile info for '%v': %v", ci, ret)
+		}
+
+		_, ret = gi.CreateComputeInstance(&ciProfileInfo)
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("error creating Compute instance for '%v' in GPU instance '%v': %v", ci, g.Profile, ret)
+		}
+	}
+
+	return nil
+}
+
 func (m *nvmlMigConfigManager) ClearMigConfig(gpu int) error {
 	device, ret := m.nvml.DeviceGetHandleByIndex(gpu)
 	if ret != nvml.SUCCESS {
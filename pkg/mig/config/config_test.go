@@ -164,6 +164,62 @@ func TestSetMigConfigResolvesMigProfileOnTargetGPU(t *testing.T) {
 	require.Equal(t, config, actual)
 }
 
+func TestGetSetGpuInstances(t *testing.T) {
+	types.SetMockNVdevlib()
+
+	placement := func(start int) *int { return &start }
+	testCases := []struct {
+		description string
+		config      types.GpuInstancesConfig
+		expected    types.GpuInstancesConfig
+	}{
+		{
+			description: "Compute instances sharing GPU instances",
+			config: types.GpuInstancesConfig{
+				{Profile: "3g.20gb", Placement: placement(4), ComputeInstances: []string{"2c.3g.20gb", "1c.3g.20gb"}},
+				{Profile: "3g.20gb", Placement: placement(0), ComputeInstances: []string{"1c.3g.20gb", "1c.3g.20gb", "1c.3g.20gb"}},
+			},
+			expected: types.GpuInstancesConfig{
+				{Profile: "3g.20gb", Placement: placement(0), ComputeInstances: []string{"1c.3g.20gb", "1c.3g.20gb", "1c.3g.20gb"}},
+				{Profile: "3g.20gb", Placement: placement(4), ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+			},
+		},
+		{
+			description: "Placements",
+			config: types.GpuInstancesConfig{
+				{Profile: "3g.20gb", Placement: placement(4)},
+				{Profile: "1g.5gb", Placement: placement(2)},
+			},
+			expected: types.GpuInstancesConfig{
+				{Profile: "1g.5gb", Placement: placement(2)},
+				{Profile: "3g.20gb", Placement: placement(4)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			manager := NewMockLunaServerMigConfigManager()
+
+			r1, r2 := EnableMigMode(manager, 0)
+			require.Equal(t, nvml.SUCCESS, r1)
+			require.Equal(t, nvml.SUCCESS, r2)
+
+			err := manager.SetGpuInstances(0, tc.config)
+			require.NoError(t, err, "Unexpected failure from SetGpuInstances")
+
+			actual, err := manager.GetGpuInstances(0)
+			require.NoError(t, err, "Unexpected failure from GetGpuInstances")
+			require.Equal(t, tc.expected, actual)
+			require.True(t, tc.config.Matches(actual))
+
+			migConfig, err := manager.GetMigConfig(0)
+			require.NoError(t, err, "Unexpected failure from GetMigConfig")
+			require.Equal(t, tc.config.MigConfig(), migConfig)
+		})
+	}
+}
+
 func TestClearMigConfig(t *testing.T) {
 	types.SetMockNVdevlib()
 	mcg := NewA100_SXM4_40GB_MigConfigGroup()
@@ -121,9 +121,10 @@ func (m *a100_sxm4_40gb_MigConfigGroup) init() {
 	for _, v := range configs {
 		m.Configs = append(m.Configs, v)
 	}
+	m.DeviceTypes = m.deviceTypes()
 }
 
-func (m *a100_sxm4_40gb_MigConfigGroup) GetDeviceTypes() []*types.MigProfile {
+func (m *a100_sxm4_40gb_MigConfigGroup) deviceTypes() []*types.MigProfile {
 	return []*types.MigProfile{
 		types.MustParseMigProfile(mig_1c_1g_5gb),
 		types.MustParseMigProfile(mig_1c_1g_5gb_me),
@@ -118,6 +118,15 @@ func TestValidConfiguration(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"Mix of devices (named by their compute slices)",
+			A100_SXM4_40GB,
+			types.MigConfig{
+				"1c.1g.5gb":  2,
+				"2c.2g.10gb": 2,
+			},
+			true,
+		},
 		{
 			"Mix of devices (one greater than max)",
 			A100_SXM4_40GB,
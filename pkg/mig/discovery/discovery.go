@@ -19,6 +19,7 @@ package discovery
 import (
 	"errors"
 	"fmt"
+	"slices"
 
 	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -32,17 +33,20 @@ import (
 // could be discovered.
 var ErrNoProfilesDiscovered = errors.New("no MIG profiles discovered for MIG-capable GPUs")
 
-var (
-	// deviceIDA30 is the PCI device ID for A30-24GB GPUs.
-	// NVML has a bug that reports incorrect profiles for this GPU.
-	deviceIDA30 = types.NewDeviceID(0x20B7, 0x10DE)
-)
-
 // ProfileInfo represents a discovered MIG profile with its metadata
 type ProfileInfo struct {
-	Name     string           // Profile name (e.g., "1g.10gb", "2g.20gb")
-	MaxCount int              // Maximum instance count for this profile
-	DeviceID types.DeviceID   // Device ID where this profile was discovered
+	Name             string                      // Profile name (e.g., "1g.10gb", "2g.20gb")
+	MaxCount         int                         // Maximum instance count for this profile
+	DeviceID         types.DeviceID              // Device ID where this profile was discovered
+	Profile          nvdev.MigProfile            // The underlying MIG profile object
+	Placements       []nvml.GpuInstancePlacement // Possible placements of an instance of this profile
+	ComputeInstances []ComputeInstanceInfo       // Compute Instance profiles that split up an instance of this profile
+}
+
+// ComputeInstanceInfo represents a Compute Instance profile using part of a GPU instance
+type ComputeInstanceInfo struct {
+	Name     string           // Profile name (e.g., "1c.3g.40gb")
+	MaxCount int              // Maximum count of these Compute Instances sharing one GPU instance
 	Profile  nvdev.MigProfile // The underlying MIG profile object
 }
 
@@ -51,14 +55,18 @@ type DeviceProfiles map[int][]ProfileInfo
 
 // discoverer holds dependencies for profile discovery, enabling testing with mocks
 type discoverer struct {
-	nvmllib   nvml.Interface
-	deviceLib nvdev.Interface
+	nvmllib       nvml.Interface
+	deviceLib     nvdev.Interface
+	quirks        []Quirk
+	driverVersion string
 }
 
-// isCIProfile returns true if the profile is a Compute Instance profile.
-// CI profiles have C > 0 and C < G (e.g. 1c.2g.20gb).
+// isCIProfile returns true if the profile is a Compute Instance profile that
+// splits up a GPU instance. CI profiles have C > 0 and C < G (e.g. 1c.2g.20gb),
+// and span at most half of the slices of the GPU instance rounded up (2C <= G+1),
+// as go-nvlib lists them.
 func isCIProfile(c, g int) bool {
-	return c > 0 && c < g
+	return c > 0 && c < g && 2*c <= g+1
 }
 
 // getDeviceID extracts the device ID from a nvdev.Device.
@@ -70,27 +78,63 @@ func getDeviceID(dev nvdev.Device) (types.DeviceID, error) {
 	return types.NewDeviceIDFromPacked(pciInfo.PciDeviceId), nil
 }
 
-// getHardcodedA30Profiles returns hardcoded MIG profiles for A30 GPUs.
-// This is needed because NVML reports incorrect profiles for A30.
-func getHardcodedA30Profiles(deviceID types.DeviceID) []ProfileInfo {
-	return []ProfileInfo{
-		{Name: "1g.6gb", MaxCount: 4, DeviceID: deviceID,
-			Profile: nvdev.MigProfileInfo{C: 1, G: 1, GB: 6}},
-		{Name: "1g.6gb+me", MaxCount: 1, DeviceID: deviceID,
-			Profile: nvdev.MigProfileInfo{C: 1, G: 1, GB: 6, Attributes: []string{"me"}}},
-		{Name: "2g.12gb", MaxCount: 2, DeviceID: deviceID,
-			Profile: nvdev.MigProfileInfo{C: 2, G: 2, GB: 12}},
-		{Name: "2g.12gb+me", MaxCount: 1, DeviceID: deviceID,
-			Profile: nvdev.MigProfileInfo{C: 2, G: 2, GB: 12, Attributes: []string{"me"}}},
-		{Name: "4g.24gb", MaxCount: 1, DeviceID: deviceID,
-			Profile: nvdev.MigProfileInfo{C: 4, G: 4, GB: 24}},
+// newComputeInstanceInfo describes a Compute Instance profile, along with how
+// many of its instances fit in one GPU instance. The count is the one NVML
+// reports for the GPU instances of 'dev', if any.
+func newComputeInstanceInfo(dev nvml.Device, profile nvdev.MigProfile) ComputeInstanceInfo {
+	return ComputeInstanceInfo{
+		Name:     profile.String(),
+		MaxCount: getComputeInstanceMaxCount(dev, profile.GetInfo()),
+		Profile:  profile,
+	}
+}
+
+// getComputeInstanceMaxCount returns how many Compute Instances of a profile fit
+// in a GPU instance. NVML only reports the Compute Instance profiles of an
+// existing GPU instance, so the count is computed from the slices of the
+// profile (e.g. 3 for 1c.3g, 2 for 3c.7g) when 'dev' is nil or holds no
+// instance of its GPU instance profile.
+func getComputeInstanceMaxCount(dev nvml.Device, info nvdev.MigProfileInfo) int {
+	if dev != nil {
+		count, ret := getReportedComputeInstanceMaxCount(dev, info)
+		if ret == nvml.SUCCESS {
+			return count
+		}
+		log.Debugf("Could not get instance count of Compute Instance profile %s from NVML, computing it: %v", info.String(), ret)
+	}
+	return info.G / info.C
+}
+
+// getReportedComputeInstanceMaxCount returns the instance count NVML reports for
+// a Compute Instance profile in the first GPU instance of its GPU instance profile.
+func getReportedComputeInstanceMaxCount(dev nvml.Device, info nvdev.MigProfileInfo) (int, nvml.Return) {
+	giProfileInfo, ret := dev.GetGpuInstanceProfileInfo(info.GIProfileID)
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	gis, ret := dev.GetGpuInstances(&giProfileInfo)
+	if ret != nvml.SUCCESS {
+		return 0, ret
 	}
+	if len(gis) == 0 {
+		return 0, nvml.ERROR_NOT_FOUND
+	}
+	ciProfileInfo, ret := gis[0].GetComputeInstanceProfileInfo(info.CIProfileID, info.CIEngProfileID)
+	if ret != nvml.SUCCESS {
+		return 0, ret
+	}
+	return int(ciProfileInfo.InstanceCount), nvml.SUCCESS
 }
 
 // DiscoverMIGProfiles discovers all MIG profiles on the system.
 // Returns map[deviceIndex][]ProfileInfo for all MIG-capable devices.
 func DiscoverMIGProfiles() (DeviceProfiles, error) {
-	nvmllib := nvml.New()
+	return DiscoverMIGProfilesFrom(util.NewNvml())
+}
+
+// DiscoverMIGProfilesFrom discovers all MIG profiles of the devices of the given
+// NVML library (e.g. the GPUs of a simulated node).
+func DiscoverMIGProfilesFrom(nvmllib nvml.Interface) (DeviceProfiles, error) {
 	err := util.NvmlInit(nvmllib)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing NVML: %w", err)
@@ -99,9 +143,15 @@ func DiscoverMIGProfiles() (DeviceProfiles, error) {
 
 	deviceLib := nvdev.New(nvmllib)
 
+	quirks, err := Quirks()
+	if err != nil {
+		return nil, err
+	}
+
 	d := &discoverer{
 		nvmllib:   nvmllib,
 		deviceLib: deviceLib,
+		quirks:    quirks,
 	}
 	return d.discoverProfiles()
 }
@@ -111,6 +161,14 @@ func (d *discoverer) discoverProfiles() (DeviceProfiles, error) {
 	result := make(DeviceProfiles)
 	migCapableCount := 0
 
+	if d.driverVersion == "" {
+		driverVersion, ret := d.nvmllib.SystemGetDriverVersion()
+		if ret != nvml.SUCCESS {
+			log.Warnf("Could not get driver version, skipping quirks limited to driver versions: %v", ret)
+		}
+		d.driverVersion = driverVersion
+	}
+
 	err := d.deviceLib.VisitDevices(func(i int, dev nvdev.Device) error {
 		deviceID, err := getDeviceID(dev)
 		if err != nil {
@@ -129,66 +187,16 @@ func (d *discoverer) discoverProfiles() (DeviceProfiles, error) {
 
 		migCapableCount++
 
-		// Check for A30 - use hardcoded profiles due to NVML bug where
-		// GetGpuInstanceProfileInfo returns incorrect InstanceCount values.
-		// The hardcoded values match the A30 MIG profiles from nvidia-smi.
-		if deviceIDA30.Matches(deviceID) {
-			log.Infof("Device %d is A30 (DeviceID: %s), using hardcoded profiles due to NVML bug",
-				i, deviceID.String())
-			result[i] = getHardcodedA30Profiles(deviceID)
-			return nil
-		}
-
-		log.Infof("Discovering MIG profiles for device %d (DeviceID: %s)", i, deviceID.String())
-
-		profiles, err := dev.GetMigProfiles()
-		if err != nil {
-			return fmt.Errorf("error getting MIG profiles for device %d: %w", i, err)
-		}
-
-		if len(profiles) == 0 {
-			log.Warnf("No MIG profiles found for device %d", i)
-			return nil
-		}
-
-		log.Infof("Found %d MIG profile(s) for device %d", len(profiles), i)
+		quirks, replaceProfiles := matchingQuirks(d.quirks, deviceID, d.driverVersion)
 
-		nvmlDevice := nvml.Device(dev)
 		var deviceProfiles []ProfileInfo
-
-		for _, profile := range profiles {
-			profileInfo := profile.GetInfo()
-			profileStr := profile.String()
-
-			// Skip Compute Instance (CI) profiles - these are sub-partitions of GPU Instances and not used for
-			// "all-*" MIG configurations. We do not have a use case currently where 'all-*' MIG profiles should
-			// provision MIG slices that leave some SMs/compute slices unutilized.
-			// CI profiles have C > 0 and C != G.
-			// Example: 1c.3g.20gb is a CI profile (1 compute slice in a 3g GPU instance), while 3g.20gb is a GI
-			// profile (full 3g GPU instance).
-			if isCIProfile(profileInfo.C, profileInfo.G) {
-				log.Infof("Skipping Compute Instance profile %s (C=%d, G=%d)", profileStr, profileInfo.C, profileInfo.G)
-				continue
-			}
-
-			giProfileInfo, ret := nvmlDevice.GetGpuInstanceProfileInfo(profileInfo.GIProfileID)
-			if ret != nvml.SUCCESS {
-				log.Warnf("Could not get GPU instance profile info for profile %s (GI ID: %d): %v",
-					profileStr, profileInfo.GIProfileID, ret)
-				continue
+		if !replaceProfiles {
+			deviceProfiles, err = d.discoverDeviceProfiles(i, dev, deviceID)
+			if err != nil {
+				return err
 			}
-
-			maxCount := int(giProfileInfo.InstanceCount)
-
-			deviceProfiles = append(deviceProfiles, ProfileInfo{
-				Name:     profileStr,
-				MaxCount: maxCount,
-				DeviceID: deviceID,
-				Profile:  profile,
-			})
-
-			log.Debugf("Profile %s on device %d has max count: %d", profileStr, i, maxCount)
 		}
+		deviceProfiles = applyQuirks(quirks, deviceProfiles, i, deviceID)
 
 		if len(deviceProfiles) == 0 {
 			log.Warnf("No valid MIG profiles with instance counts found for device %d", i)
@@ -217,3 +225,95 @@ func (d *discoverer) discoverProfiles() (DeviceProfiles, error) {
 
 	return result, nil
 }
+
+// discoverDeviceProfiles queries NVML for the MIG profiles of a device, along with
+// the max count and placements of each GPU instance profile.
+func (d *discoverer) discoverDeviceProfiles(i int, dev nvdev.Device, deviceID types.DeviceID) ([]ProfileInfo, error) {
+	log.Infof("Discovering MIG profiles for device %d (DeviceID: %s)", i, deviceID.String())
+
+	profiles, err := dev.GetMigProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("error getting MIG profiles for device %d: %w", i, err)
+	}
+
+	if len(profiles) == 0 {
+		log.Warnf("No MIG profiles found for device %d", i)
+		return nil, nil
+	}
+
+	log.Infof("Found %d MIG profile(s) for device %d", len(profiles), i)
+
+	nvmlDevice := nvml.Device(dev)
+	var deviceProfiles []ProfileInfo
+	computeInstances := make(map[int][]ComputeInstanceInfo)
+
+	for _, profile := range profiles {
+		profileInfo := profile.GetInfo()
+		profileStr := profile.String()
+
+		// Compute Instance (CI) profiles are sub-partitions of GPU Instances, so they are not
+		// listed as profiles of their own but recorded with the GPU Instance profile they split up.
+		// CI profiles have C > 0 and C != G.
+		// Example: 1c.3g.20gb is a CI profile (1 compute slice in a 3g GPU instance), while 3g.20gb is a GI
+		// profile (full 3g GPU instance).
+		if profileInfo.C != profileInfo.G {
+			// go-nvlib lists the profile once per compute engine profile, under the same
+			// name. Keep the first, which shares the compute engines of the GPU instance.
+			recorded := slices.ContainsFunc(computeInstances[profileInfo.GIProfileID], func(ci ComputeInstanceInfo) bool {
+				return ci.Name == profileStr
+			})
+			if !isCIProfile(profileInfo.C, profileInfo.G) || recorded {
+				continue
+			}
+			ci := newComputeInstanceInfo(nvmlDevice, profile)
+			log.Debugf("Compute Instance profile %s on device %d has max count per GPU instance: %d", profileStr, i, ci.MaxCount)
+			computeInstances[profileInfo.GIProfileID] = append(computeInstances[profileInfo.GIProfileID], ci)
+			continue
+		}
+
+		giProfileInfo, ret := nvmlDevice.GetGpuInstanceProfileInfo(profileInfo.GIProfileID)
+		if ret != nvml.SUCCESS {
+			log.Warnf("Could not get GPU instance profile info for profile %s (GI ID: %d): %v",
+				profileStr, profileInfo.GIProfileID, ret)
+			continue
+		}
+
+		maxCount := int(giProfileInfo.InstanceCount)
+
+		placements, ret := nvmlDevice.GetGpuInstancePossiblePlacements(&giProfileInfo)
+		if ret != nvml.SUCCESS {
+			log.Warnf("Could not get possible placements for profile %s (GI ID: %d): %v",
+				profileStr, profileInfo.GIProfileID, ret)
+			placements = nil
+		}
+
+		deviceProfiles = append(deviceProfiles, ProfileInfo{
+			Name:       profileStr,
+			MaxCount:   maxCount,
+			DeviceID:   deviceID,
+			Profile:    profile,
+			Placements: placements,
+		})
+
+		log.Debugf("Profile %s on device %d has max count: %d", profileStr, i, maxCount)
+	}
+
+	for j := range deviceProfiles {
+		deviceProfiles[j].ComputeInstances = computeInstances[deviceProfiles[j].Profile.GetInfo().GIProfileID]
+	}
+
+	return deviceProfiles, nil
+}
+
+// LookupProfile returns the profile of a device named as in a MIG config,
+// which may be any of the names the profile matches.
+func LookupProfile(profiles []ProfileInfo, name string) (ProfileInfo, bool) {
+	i := slices.IndexFunc(profiles, func(p ProfileInfo) bool { return p.Name == name })
+	if i < 0 {
+		i = slices.IndexFunc(profiles, func(p ProfileInfo) bool { return p.Profile.Matches(name) })
+	}
+	if i < 0 {
+		return ProfileInfo{}, false
+	}
+	return profiles[i], true
+}
@@ -20,13 +20,19 @@ import (
 	"testing"
 
 	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/dgxa100"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+	"github.com/NVIDIA/mig-parted/pkg/sim/hardware"
+	"github.com/NVIDIA/mig-parted/pkg/sim/simtest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
 )
 
 func TestDiscoverProfiles(t *testing.T) {
-	// TODO: Add tests for other device types when we have mocks for them
 	server := dgxa100.New()
 	deviceLib := nvdev.New(server, nvdev.WithVerifySymbols(false))
 
@@ -50,6 +56,102 @@ func TestDiscoverProfiles(t *testing.T) {
 	}
 }
 
+func TestDiscoverProfilesForModels(t *testing.T) {
+	for _, name := range hardware.Names() {
+		t.Run(name, func(t *testing.T) {
+			nvmllib := simtest.NewNvml(t, simtest.Models(name)...)
+
+			quirks, err := Quirks()
+			require.NoError(t, err)
+
+			d := &discoverer{
+				nvmllib:   nvmllib,
+				deviceLib: nvdev.New(nvmllib, nvdev.WithVerifySymbols(false)),
+				quirks:    quirks,
+			}
+
+			result, err := d.discoverProfiles()
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+
+			model, err := hardware.Get(name)
+			require.NoError(t, err)
+
+			expected := make(map[string]int)
+			for _, p := range model.GpuInstanceProfiles {
+				expected[p.Name] = p.Count
+			}
+			discovered := make(map[string]int)
+			for _, p := range result[0] {
+				discovered[p.Name] = p.MaxCount
+
+				// Compute Instance profiles are recorded with the GPU instance profile they split up.
+				info := p.Profile.GetInfo()
+				for _, ci := range p.ComputeInstances {
+					ciInfo := ci.Profile.GetInfo()
+					assert.Equal(t, info.GIProfileID, ciInfo.GIProfileID, "compute instance profile %s of %s", ci.Name, p.Name)
+					assert.Less(t, ciInfo.C, info.G, "compute instance profile %s of %s", ci.Name, p.Name)
+					assert.Equal(t, info.G/ciInfo.C, ci.MaxCount, "compute instance profile %s of %s", ci.Name, p.Name)
+				}
+				if info.G > 1 {
+					assert.NotEmpty(t, p.ComputeInstances, "profile %s should have compute instance profiles", p.Name)
+				}
+			}
+			assert.Equal(t, expected, discovered)
+		})
+	}
+}
+
+func TestDiscoverComputeInstanceCounts(t *testing.T) {
+	nvmllib := simtest.NewNvml(t, sim.GPUSpec{
+		Model:      "a100-sxm4-40gb",
+		MigEnabled: true,
+		MigDevices: types.MigConfig{"3g.20gb": 1},
+	})
+
+	// Report fewer 1c.3g.20gb Compute Instances than fit in the slices of the
+	// GPU instance, as a driver may.
+	device, ret := nvmllib.DeviceGetHandleByIndex(0)
+	require.Equal(t, nvml.SUCCESS, ret)
+	giProfileInfo, ret := device.GetGpuInstanceProfileInfo(nvml.GPU_INSTANCE_PROFILE_3_SLICE)
+	require.Equal(t, nvml.SUCCESS, ret)
+	gis, ret := device.GetGpuInstances(&giProfileInfo)
+	require.Equal(t, nvml.SUCCESS, ret)
+	require.Len(t, gis, 1)
+	gi := gis[0].(*server.GpuInstance)
+	getComputeInstanceProfileInfo := gi.GetComputeInstanceProfileInfoFunc
+	gi.GetComputeInstanceProfileInfoFunc = func(profile int, engProfile int) (nvml.ComputeInstanceProfileInfo, nvml.Return) {
+		info, ret := getComputeInstanceProfileInfo(profile, engProfile)
+		if profile == nvml.COMPUTE_INSTANCE_PROFILE_1_SLICE {
+			info.InstanceCount = 2
+		}
+		return info, ret
+	}
+
+	d := &discoverer{
+		nvmllib:   nvmllib,
+		deviceLib: nvdev.New(nvmllib, nvdev.WithVerifySymbols(false)),
+	}
+	result, err := d.discoverProfiles()
+	require.NoError(t, err)
+
+	computeInstances := make(map[string]map[string]int)
+	for _, p := range result[0] {
+		for _, ci := range p.ComputeInstances {
+			if computeInstances[p.Name] == nil {
+				computeInstances[p.Name] = make(map[string]int)
+			}
+			computeInstances[p.Name][ci.Name] = ci.MaxCount
+		}
+	}
+
+	// The counts of 3g.20gb come from NVML, those of the GPU instance
+	// profiles without an instance are computed.
+	assert.Equal(t, map[string]int{"1c.3g.20gb": 2, "2c.3g.20gb": 1}, computeInstances["3g.20gb"])
+	assert.Equal(t, map[string]int{"1c.2g.10gb": 2}, computeInstances["2g.10gb"])
+	assert.Equal(t, map[string]int{"1c.4g.20gb": 4, "2c.4g.20gb": 2}, computeInstances["4g.20gb"])
+}
+
 func TestIsCIProfile(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -61,6 +163,7 @@ func TestIsCIProfile(t *testing.T) {
 		{"CI profile 1c.2g", 1, 2, true},
 		{"CI profile 3c.7g", 3, 7, true},
 		{"full slice c==g", 7, 7, false},
+		{"more than half of the slices 3c.4g", 3, 4, false},
 	}
 
 	for _, tc := range testCases {
@@ -70,9 +173,14 @@ func TestIsCIProfile(t *testing.T) {
 	}
 }
 
-func TestGetHardcodedA30Profiles(t *testing.T) {
-	deviceID := deviceIDA30
-	profiles := getHardcodedA30Profiles(deviceID)
+func TestA30Quirk(t *testing.T) {
+	quirks, err := Quirks()
+	require.NoError(t, err)
+
+	deviceID := types.NewDeviceID(0x20B7, 0x10DE)
+	matching, replaceProfiles := matchingQuirks(quirks, deviceID, "550.54.15")
+	require.True(t, replaceProfiles, "the A30 profiles reported by NVML should be replaced")
+	profiles := applyQuirks(matching, nil, 0, deviceID)
 
 	require.Len(t, profiles, 5)
 
@@ -90,6 +198,35 @@ func TestGetHardcodedA30Profiles(t *testing.T) {
 		assert.NotNil(t, p.Profile, "profile %s should have non-nil Profile", p.Name)
 	}
 
+	// Verify placements are known for every profile
+	placements := make(map[string][]nvml.GpuInstancePlacement)
+	for _, p := range profiles {
+		placements[p.Name] = p.Placements
+	}
+	assert.Equal(t, map[string][]nvml.GpuInstancePlacement{
+		"1g.6gb":     getPlacements(1, 0, 1, 2, 3),
+		"1g.6gb+me":  getPlacements(1, 0, 1, 2, 3),
+		"2g.12gb":    getPlacements(2, 0, 2),
+		"2g.12gb+me": getPlacements(2, 0, 2),
+		"4g.24gb":    getPlacements(4, 0),
+	}, placements)
+
+	// Verify compute instance profiles split up the larger GPU instances
+	computeInstances := make(map[string]map[string]int)
+	for _, p := range profiles {
+		for _, ci := range p.ComputeInstances {
+			if computeInstances[p.Name] == nil {
+				computeInstances[p.Name] = make(map[string]int)
+			}
+			computeInstances[p.Name][ci.Name] = ci.MaxCount
+		}
+	}
+	assert.Equal(t, map[string]map[string]int{
+		"2g.12gb":    {"1c.2g.12gb": 2},
+		"2g.12gb+me": {"1c.2g.12gb+me": 2},
+		"4g.24gb":    {"1c.4g.24gb": 4, "2c.4g.24gb": 2},
+	}, computeInstances)
+
 	// Verify base profiles have no attributes
 	for _, p := range profiles {
 		info := p.Profile.GetInfo()
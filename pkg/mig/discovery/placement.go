@@ -0,0 +1,45 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+// MaxPlacementSlots is the number of memory slices a device may have for the
+// placements of its profiles to be held in a bitmask (one bit per slice in a uint64).
+const MaxPlacementSlots = 64
+
+// PlacementMask returns the memory slices occupied by a GPU instance at a
+// placement as a bitmask, where placements overlap if their masks do.
+func PlacementMask(placement nvml.GpuInstancePlacement) uint64 {
+	return ((uint64(1) << placement.Size) - 1) << placement.Start
+}
+
+// CheckPlacementMasks checks that every placement of a profile fits in the
+// bitmask returned by PlacementMask.
+func (p *ProfileInfo) CheckPlacementMasks() error {
+	for _, placement := range p.Placements {
+		if placement.Start+placement.Size > MaxPlacementSlots {
+			return fmt.Errorf("placement %d:%d of profile %s exceeds %d memory slices",
+				placement.Start, placement.Size, p.Name, MaxPlacementSlots)
+		}
+	}
+	return nil
+}
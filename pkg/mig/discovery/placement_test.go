@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+)
+
+func TestPlacementMask(t *testing.T) {
+	testCases := []struct {
+		description string
+		placement   nvml.GpuInstancePlacement
+		mask        uint64
+	}{
+		{"First Slice", nvml.GpuInstancePlacement{Start: 0, Size: 1}, 0b1},
+		{"Middle Slices", nvml.GpuInstancePlacement{Start: 4, Size: 2}, 0b110000},
+		{"Full GPU", nvml.GpuInstancePlacement{Start: 0, Size: 8}, 0xFF},
+		{"All Slots", nvml.GpuInstancePlacement{Start: 0, Size: MaxPlacementSlots}, ^uint64(0)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.mask, PlacementMask(tc.placement))
+		})
+	}
+}
+
+func TestCheckPlacementMasks(t *testing.T) {
+	profile := ProfileInfo{Name: "1g.10gb", Placements: getPlacements(1, 0, 1, 63)}
+	require.Nil(t, profile.CheckPlacementMasks())
+
+	profile.Placements = append(profile.Placements, nvml.GpuInstancePlacement{Start: 63, Size: 2})
+	err := profile.CheckPlacementMasks()
+	require.NotNil(t, err, "Unexpected success with a placement beyond the last slot")
+	require.Contains(t, err.Error(), "placement 63:2 of profile 1g.10gb exceeds 64 memory slices")
+}
@@ -0,0 +1,370 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// QuirksVersion indicates the version of the 'QuirksSpec' struct used to describe quirks.
+const QuirksVersion = "v1"
+
+// QuirksSpec describes workarounds for devices whose MIG profiles NVML reports incorrectly.
+type QuirksSpec struct {
+	Version string  `json:"version"`
+	Quirks  []Quirk `json:"quirks"`
+}
+
+// Quirk corrects the MIG profiles discovered on devices with a given device ID,
+// optionally only with driver versions in the (inclusive) range given. A bound
+// with fewer components covers every version it is a prefix of (e.g. a max of
+// "550" includes "550.54.15").
+type Quirk struct {
+	Name             string         `json:"name"`
+	Description      string         `json:"description,omitempty"`
+	DeviceID         string         `json:"device-id"`
+	MinDriverVersion string         `json:"min-driver-version,omitempty"`
+	MaxDriverVersion string         `json:"max-driver-version,omitempty"`
+	ReplaceProfiles  bool           `json:"replace-profiles,omitempty"`
+	Profiles         []ProfileQuirk `json:"profiles,omitempty"`
+	DisableProfiles  []string       `json:"disable-profiles,omitempty"`
+}
+
+// ProfileQuirk overrides the max count or placements of a MIG profile, or adds
+// the profile if it was not discovered (or 'replace-profiles' is set).
+type ProfileQuirk struct {
+	Name       string           `json:"name"`
+	MaxCount   int              `json:"max-count,omitempty"`
+	Placements *PlacementsQuirk `json:"placements,omitempty"`
+}
+
+// PlacementsQuirk gives the possible placements of a MIG profile as a size and a list of starts.
+type PlacementsQuirk struct {
+	Size   uint32   `json:"size"`
+	Starts []uint32 `json:"starts"`
+}
+
+//go:embed quirks.yaml
+var embeddedQuirksYaml []byte
+
+var loadEmbeddedQuirks = sync.OnceValues(func() ([]Quirk, error) {
+	spec, err := ParseQuirks(embeddedQuirksYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing embedded quirks: %w", err)
+	}
+	return spec.Quirks, nil
+})
+
+// operatorQuirks holds the quirks loaded with 'LoadQuirksFile'.
+var operatorQuirks []Quirk
+
+// ParseQuirksFile reads and validates quirks from a YAML (or JSON) file.
+func ParseQuirksFile(file string) (*QuirksSpec, error) {
+	quirksYaml, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read error: %v", err)
+	}
+	return ParseQuirks(quirksYaml)
+}
+
+// ParseQuirks parses and validates quirks from YAML (or JSON).
+func ParseQuirks(quirksYaml []byte) (*QuirksSpec, error) {
+	var spec QuirksSpec
+	err := yaml.UnmarshalStrict(quirksYaml, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// LoadQuirksFile adds the quirks of an operator-supplied file to those applied
+// during discovery. A quirk with the same name as an embedded one replaces it.
+func LoadQuirksFile(file string) error {
+	spec, err := ParseQuirksFile(file)
+	if err != nil {
+		return fmt.Errorf("error parsing quirks file %v: %w", file, err)
+	}
+	operatorQuirks = append(operatorQuirks, spec.Quirks...)
+	return nil
+}
+
+// Quirks returns the quirks applied during discovery: the embedded ones,
+// followed by (or replaced by) those loaded with 'LoadQuirksFile'.
+func Quirks() ([]Quirk, error) {
+	embedded, err := loadEmbeddedQuirks()
+	if err != nil {
+		return nil, err
+	}
+
+	var quirks []Quirk
+	for _, q := range embedded {
+		overridden := slices.ContainsFunc(operatorQuirks, func(o Quirk) bool { return o.Name == q.Name })
+		if !overridden {
+			quirks = append(quirks, q)
+		}
+	}
+	return append(quirks, operatorQuirks...), nil
+}
+
+// Validate checks that the quirks can be applied during discovery.
+func (s *QuirksSpec) Validate() error {
+	if s.Version != QuirksVersion {
+		return fmt.Errorf("unknown version: %v", s.Version)
+	}
+
+	names := make(map[string]bool)
+	for i, q := range s.Quirks {
+		if names[q.Name] {
+			return fmt.Errorf("quirks[%d]: duplicate name '%v'", i, q.Name)
+		}
+		names[q.Name] = true
+		if err := q.Validate(); err != nil {
+			return fmt.Errorf("quirks[%d]: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that a quirk can be applied during discovery.
+func (q *Quirk) Validate() error {
+	if q.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if _, err := types.NewDeviceIDFromString(q.DeviceID); err != nil {
+		return fmt.Errorf("invalid device-id '%v': %v", q.DeviceID, err)
+	}
+	for _, version := range []string{q.MinDriverVersion, q.MaxDriverVersion} {
+		if version == "" {
+			continue
+		}
+		if _, err := parseDriverVersion(version); err != nil {
+			return fmt.Errorf("invalid driver version '%v': %v", version, err)
+		}
+	}
+	if len(q.Profiles) == 0 && len(q.DisableProfiles) == 0 {
+		return fmt.Errorf("no profiles or disable-profiles specified")
+	}
+
+	profiles := make(map[string]bool)
+	for i, p := range q.Profiles {
+		if _, err := parseGIProfileName(p.Name); err != nil {
+			return fmt.Errorf("profiles[%d]: invalid name '%v': %v", i, p.Name, err)
+		}
+		if profiles[p.Name] {
+			return fmt.Errorf("profiles[%d]: duplicate name '%v'", i, p.Name)
+		}
+		profiles[p.Name] = true
+		if p.MaxCount < 0 {
+			return fmt.Errorf("profiles[%d]: invalid max-count '%v': must not be negative", i, p.MaxCount)
+		}
+		if q.ReplaceProfiles && p.MaxCount == 0 {
+			return fmt.Errorf("profiles[%d]: missing max-count with replace-profiles", i)
+		}
+		if p.Placements != nil && (p.Placements.Size == 0 || len(p.Placements.Starts) == 0) {
+			return fmt.Errorf("profiles[%d]: invalid placements: size and starts required", i)
+		}
+	}
+	for i, name := range q.DisableProfiles {
+		if err := types.AssertValidMigProfileFormat(name); err != nil {
+			return fmt.Errorf("disable-profiles[%d]: invalid name '%v': %v", i, name, err)
+		}
+	}
+
+	return nil
+}
+
+// appliesTo checks whether a quirk applies to a device with the given device ID and driver version.
+// Quirks limited to a range of driver versions never apply if the driver version is unknown.
+func (q *Quirk) appliesTo(deviceID types.DeviceID, driverVersion string) bool {
+	filter, err := types.NewDeviceIDFromString(q.DeviceID)
+	if err != nil || !filter.Matches(deviceID) {
+		return false
+	}
+	if q.MinDriverVersion == "" && q.MaxDriverVersion == "" {
+		return true
+	}
+	version, err := parseDriverVersion(driverVersion)
+	if err != nil {
+		return false
+	}
+	if q.MinDriverVersion != "" {
+		minVersion, _ := parseDriverVersion(q.MinDriverVersion)
+		if compareDriverVersion(version, minVersion) < 0 {
+			return false
+		}
+	}
+	if q.MaxDriverVersion != "" {
+		maxVersion, _ := parseDriverVersion(q.MaxDriverVersion)
+		if compareDriverVersion(version, maxVersion) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// compareDriverVersion compares a driver version with a bound, ignoring the
+// components of the version beyond those of the bound.
+func compareDriverVersion(version, bound []int) int {
+	if len(version) > len(bound) {
+		version = version[:len(bound)]
+	}
+	return slices.Compare(version, bound)
+}
+
+// apply corrects the profiles discovered on a device with the given device ID.
+func (q *Quirk) apply(profiles []ProfileInfo, deviceID types.DeviceID) []ProfileInfo {
+	if q.ReplaceProfiles {
+		profiles = nil
+	}
+
+	for _, p := range q.Profiles {
+		idx := slices.IndexFunc(profiles, func(pInfo ProfileInfo) bool { return pInfo.Name == p.Name })
+		if idx == -1 {
+			profile, _ := parseGIProfileName(p.Name)
+			profiles = append(profiles, ProfileInfo{
+				Name:             p.Name,
+				DeviceID:         deviceID,
+				Profile:          profile,
+				ComputeInstances: getComputeInstances(profile),
+			})
+			idx = len(profiles) - 1
+		}
+		if p.MaxCount > 0 {
+			profiles[idx].MaxCount = p.MaxCount
+		}
+		if p.Placements != nil {
+			profiles[idx].Placements = getPlacements(p.Placements.Size, p.Placements.Starts...)
+		}
+	}
+
+	return slices.DeleteFunc(profiles, func(pInfo ProfileInfo) bool {
+		return slices.Contains(q.DisableProfiles, pInfo.Name)
+	})
+}
+
+// getComputeInstances returns the Compute Instance profiles splitting up a GPU
+// instance profile, described as discovery describes those of a device.
+func getComputeInstances(gi nvdev.MigProfileInfo) []ComputeInstanceInfo {
+	var cis []ComputeInstanceInfo
+	for c := 1; c < gi.G; c++ {
+		if !isCIProfile(c, gi.G) {
+			continue
+		}
+		ci := gi
+		ci.C = c
+		cis = append(cis, newComputeInstanceInfo(nil, ci))
+	}
+	return cis
+}
+
+// getPlacements returns the placements of the given size at each of the given starts.
+func getPlacements(size uint32, starts ...uint32) []nvml.GpuInstancePlacement {
+	var placements []nvml.GpuInstancePlacement
+	for _, start := range starts {
+		placements = append(placements, nvml.GpuInstancePlacement{Start: start, Size: size})
+	}
+	return placements
+}
+
+// parseGIProfileName parses the name of a GPU instance profile (e.g. "1g.10gb" or
+// "1g.10gb+me") without querying NVML for the profiles a device supports.
+func parseGIProfileName(name string) (nvdev.MigProfileInfo, error) {
+	if err := types.AssertValidMigProfileFormat(name); err != nil {
+		return nvdev.MigProfileInfo{}, err
+	}
+
+	var info nvdev.MigProfileInfo
+	base := name
+	if b, attrs, found := strings.Cut(name, "+"); found {
+		base, info.Attributes = b, strings.Split(attrs, ",")
+	} else if b, attrs, found := strings.Cut(name, "-"); found {
+		base, info.NegAttributes = b, strings.Split(attrs, ",")
+	}
+
+	fields := strings.Split(base, ".")
+	if len(fields) != 2 {
+		return nvdev.MigProfileInfo{}, fmt.Errorf("not a GPU instance profile")
+	}
+	g, err := strconv.Atoi(strings.TrimSuffix(fields[0], "g"))
+	if err != nil {
+		return nvdev.MigProfileInfo{}, fmt.Errorf("invalid GPU slice count: %v", err)
+	}
+	gb, err := strconv.Atoi(strings.TrimSuffix(fields[1], "gb"))
+	if err != nil {
+		return nvdev.MigProfileInfo{}, fmt.Errorf("invalid memory size: %v", err)
+	}
+	info.C, info.G, info.GB = g, g, gb
+
+	return info, nil
+}
+
+// parseDriverVersion splits a driver version (e.g. "550.54.15") into its numeric components.
+func parseDriverVersion(version string) ([]int, error) {
+	var components []int
+	for _, s := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("expected dot-separated numbers")
+		}
+		components = append(components, n)
+	}
+	return components, nil
+}
+
+// matchingQuirks returns the quirks applying to a device with the given device ID
+// and driver version, and whether any of them replaces the discovered profiles.
+func matchingQuirks(quirks []Quirk, deviceID types.DeviceID, driverVersion string) ([]Quirk, bool) {
+	var matching []Quirk
+	replaceProfiles := false
+	for _, q := range quirks {
+		if !q.appliesTo(deviceID, driverVersion) {
+			continue
+		}
+		matching = append(matching, q)
+		replaceProfiles = replaceProfiles || q.ReplaceProfiles
+	}
+	return matching, replaceProfiles
+}
+
+// applyQuirks applies quirks to the profiles discovered on a device, logging each one applied.
+func applyQuirks(quirks []Quirk, profiles []ProfileInfo, index int, deviceID types.DeviceID) []ProfileInfo {
+	for _, q := range quirks {
+		log.Infof("Applying quirk '%s' to device %d (DeviceID: %s): %s", q.Name, index, deviceID.String(), q.Description)
+		profiles = q.apply(profiles, deviceID)
+	}
+	return profiles
+}
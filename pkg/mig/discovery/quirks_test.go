@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/mig-parted/pkg/sim/simtest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestParseQuirks(t *testing.T) {
+	spec, err := ParseQuirks([]byte(`
+version: v1
+quirks:
+- name: fix-counts
+  device-id: "0x20B010DE"
+  min-driver-version: "535"
+  max-driver-version: "550.54"
+  profiles:
+  - name: 1g.5gb
+    max-count: 6
+    placements: {size: 1, starts: [0, 1, 2, 3, 4, 5]}
+  disable-profiles: [1g.5gb+me]
+`))
+	require.Nil(t, err, "Unexpected failure from ParseQuirks")
+	require.Equal(t, []Quirk{{
+		Name:             "fix-counts",
+		DeviceID:         "0x20B010DE",
+		MinDriverVersion: "535",
+		MaxDriverVersion: "550.54",
+		Profiles: []ProfileQuirk{{
+			Name:       "1g.5gb",
+			MaxCount:   6,
+			Placements: &PlacementsQuirk{Size: 1, Starts: []uint32{0, 1, 2, 3, 4, 5}},
+		}},
+		DisableProfiles: []string{"1g.5gb+me"},
+	}}, spec.Quirks)
+
+	testCases := []struct {
+		description   string
+		quirks        string
+		expectedError string
+	}{
+		{"Unknown Version", "version: v2\nquirks: []", "unknown version"},
+		{"Unknown Field", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', profile: []}]", "unknown field"},
+		{"Missing Name", "version: v1\nquirks: [{device-id: '0x20B010DE', disable-profiles: [1g.5gb]}]", "missing name"},
+		{"Duplicate Name", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', disable-profiles: [1g.5gb]}, {name: a, device-id: '0x20B010DE', disable-profiles: [1g.5gb]}]", "duplicate name 'a'"},
+		{"Invalid Device ID", "version: v1\nquirks: [{name: a, device-id: 'A100', disable-profiles: [1g.5gb]}]", "invalid device-id 'A100'"},
+		{"Invalid Driver Version", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', min-driver-version: 'r550', disable-profiles: [1g.5gb]}]", "invalid driver version 'r550'"},
+		{"Nothing To Do", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE'}]", "no profiles or disable-profiles specified"},
+		{"Invalid Profile", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', profiles: [{name: 1x.5gb}]}]", "profiles[0]: invalid name '1x.5gb'"},
+		{"Compute Instance Profile", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', profiles: [{name: 1c.2g.10gb}]}]", "invalid name '1c.2g.10gb': not a GPU instance profile"},
+		{"Duplicate Profile", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', profiles: [{name: 1g.5gb}, {name: 1g.5gb}]}]", "profiles[1]: duplicate name '1g.5gb'"},
+		{"Negative Max Count", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', profiles: [{name: 1g.5gb, max-count: -1}]}]", "invalid max-count '-1'"},
+		{"Replace Without Max Count", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', replace-profiles: true, profiles: [{name: 1g.5gb}]}]", "missing max-count with replace-profiles"},
+		{"Invalid Placements", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', profiles: [{name: 1g.5gb, placements: {size: 1}}]}]", "invalid placements"},
+		{"Invalid Disabled Profile", "version: v1\nquirks: [{name: a, device-id: '0x20B010DE', disable-profiles: [5gb]}]", "disable-profiles[0]: invalid name '5gb'"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseQuirks([]byte(tc.quirks))
+			require.NotNil(t, err, "Unexpected success from ParseQuirks")
+			require.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestEmbeddedQuirks(t *testing.T) {
+	spec, err := ParseQuirks(embeddedQuirksYaml)
+	require.Nil(t, err, "Unexpected failure parsing the embedded quirks")
+	require.NotEmpty(t, spec.Quirks)
+}
+
+func TestQuirkAppliesTo(t *testing.T) {
+	deviceID := types.NewDeviceID(0x20B0, 0x10DE)
+	quirk := Quirk{DeviceID: "0x20B010DE", MinDriverVersion: "535.104", MaxDriverVersion: "550"}
+
+	testCases := []struct {
+		deviceID      types.DeviceID
+		driverVersion string
+		expected      bool
+	}{
+		{deviceID, "535.104.05", true},
+		{deviceID, "550.54.15", true},
+		{deviceID, "535.86.10", false},
+		{deviceID, "560.35.03", false},
+		{deviceID, "", false},
+		{types.NewDeviceID(0x20B2, 0x10DE), "550.54.15", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.deviceID.String()+"/"+tc.driverVersion, func(t *testing.T) {
+			require.Equal(t, tc.expected, quirk.appliesTo(tc.deviceID, tc.driverVersion))
+		})
+	}
+
+	unbounded := Quirk{DeviceID: "0x20B010DE"}
+	require.True(t, unbounded.appliesTo(deviceID, ""), "quirks without driver versions should apply to any driver")
+}
+
+func TestQuirkApply(t *testing.T) {
+	deviceID := types.NewDeviceID(0x20B0, 0x10DE)
+	discovered := []ProfileInfo{
+		{Name: "1g.5gb", MaxCount: 7, DeviceID: deviceID, Profile: nvdev.MigProfileInfo{C: 1, G: 1, GB: 5}},
+		{Name: "1g.5gb+me", MaxCount: 1, DeviceID: deviceID, Profile: nvdev.MigProfileInfo{C: 1, G: 1, GB: 5, Attributes: []string{"me"}}},
+		{Name: "7g.40gb", MaxCount: 1, DeviceID: deviceID, Profile: nvdev.MigProfileInfo{C: 7, G: 7, GB: 40}},
+	}
+	quirk := Quirk{
+		Name:     "a100-quirk",
+		DeviceID: "0x20B010DE",
+		Profiles: []ProfileQuirk{
+			{Name: "1g.5gb", MaxCount: 6},
+			{Name: "7g.40gb", Placements: &PlacementsQuirk{Size: 8, Starts: []uint32{0}}},
+			{Name: "3g.20gb", MaxCount: 2, Placements: &PlacementsQuirk{Size: 4, Starts: []uint32{0, 4}}},
+		},
+		DisableProfiles: []string{"1g.5gb+me"},
+	}
+
+	profiles := quirk.apply(discovered, deviceID)
+
+	counts := make(map[string]int)
+	for _, p := range profiles {
+		counts[p.Name] = p.MaxCount
+		require.Equal(t, deviceID, p.DeviceID)
+	}
+	require.Equal(t, map[string]int{"1g.5gb": 6, "7g.40gb": 1, "3g.20gb": 2}, counts)
+	require.Equal(t, getPlacements(8, 0), profiles[1].Placements)
+
+	added := profiles[2]
+	require.Equal(t, "3g.20gb", added.Profile.String())
+	require.Equal(t, getPlacements(4, 0, 4), added.Placements)
+	cis := make(map[string]int)
+	for _, ci := range added.ComputeInstances {
+		cis[ci.Name] = ci.MaxCount
+	}
+	require.Equal(t, map[string]int{"1c.3g.20gb": 3, "2c.3g.20gb": 1}, cis)
+}
+
+func TestQuirkComputeInstancesMatchDiscovery(t *testing.T) {
+	for _, model := range []string{"a100-sxm4-40gb", "h100-sxm5-80gb"} {
+		t.Run(model, func(t *testing.T) {
+			nvmllib := simtest.NewNvml(t, simtest.Models(model)...)
+
+			d := &discoverer{
+				nvmllib:   nvmllib,
+				deviceLib: nvdev.New(nvmllib, nvdev.WithVerifySymbols(false)),
+			}
+			result, err := d.discoverProfiles()
+			require.NoError(t, err)
+
+			// A GPU instance profile added by a quirk is split up by the same
+			// Compute Instance profiles as when it is discovered.
+			for _, p := range result[0] {
+				profile, err := parseGIProfileName(p.Name)
+				require.NoError(t, err)
+
+				discovered := make(map[string]int)
+				for _, ci := range p.ComputeInstances {
+					discovered[ci.Name] = ci.MaxCount
+				}
+				quirked := make(map[string]int)
+				for _, ci := range getComputeInstances(profile) {
+					quirked[ci.Name] = ci.MaxCount
+				}
+				require.Equal(t, discovered, quirked, "profile %s", p.Name)
+			}
+		})
+	}
+}
+
+func TestLoadQuirksFile(t *testing.T) {
+	t.Cleanup(func() { operatorQuirks = nil })
+
+	file := filepath.Join(t.TempDir(), "quirks.yaml")
+	err := os.WriteFile(file, []byte(`
+version: v1
+quirks:
+- name: a30-profiles
+  description: Only use full GPU instances on the A30
+  device-id: "0x20B710DE"
+  replace-profiles: true
+  profiles:
+  - name: 4g.24gb
+    max-count: 1
+- name: a100-no-media-extensions
+  device-id: "0x20B010DE"
+  disable-profiles: [1g.5gb+me]
+`), 0600)
+	require.Nil(t, err)
+
+	require.Nil(t, LoadQuirksFile(file), "Unexpected failure from LoadQuirksFile")
+	quirks, err := Quirks()
+	require.Nil(t, err)
+
+	var names []string
+	for _, q := range quirks {
+		names = append(names, q.Name)
+	}
+	require.Equal(t, []string{"a30-profiles", "a100-no-media-extensions"}, names)
+
+	// The operator quirks are applied when discovering the profiles of a node.
+	nvmllib := simtest.NewNvml(t, simtest.Models("A100-SXM4-40GB", "A30-PCIE-24GB")...)
+
+	result, err := DiscoverMIGProfilesFrom(nvmllib)
+	require.Nil(t, err, "Unexpected failure from DiscoverMIGProfilesFrom")
+	for _, p := range result[0] {
+		require.NotEqual(t, "1g.5gb+me", p.Name)
+	}
+	require.Len(t, result[1], 1)
+	require.Equal(t, "4g.24gb", result[1][0].Name)
+
+	err = LoadQuirksFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NotNil(t, err, "Unexpected success loading a missing quirks file")
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// SnapshotVersion indicates the version of the 'Snapshot' struct used to save discovered profiles.
+const SnapshotVersion = "v1"
+
+// Snapshot holds the MIG profiles discovered on the devices of a node, so that
+// MIG configs can be generated from them without access to the devices.
+type Snapshot struct {
+	Version string           `json:"version"`
+	Devices []DeviceSnapshot `json:"devices"`
+}
+
+// DeviceSnapshot holds the MIG profiles discovered on a device. Its device ID
+// is only known (and required) if any profiles were discovered on it.
+type DeviceSnapshot struct {
+	Index    int               `json:"index"`
+	DeviceID string            `json:"device-id,omitempty"`
+	Profiles []ProfileSnapshot `json:"profiles"`
+}
+
+// ProfileSnapshot holds a discovered GPU instance profile.
+type ProfileSnapshot struct {
+	MigProfileSnapshot
+	Placements       []PlacementSnapshot       `json:"placements,omitempty"`
+	ComputeInstances []ComputeInstanceSnapshot `json:"compute-instances,omitempty"`
+}
+
+// ComputeInstanceSnapshot holds a Compute Instance profile splitting up a GPU instance profile.
+type ComputeInstanceSnapshot struct {
+	MigProfileSnapshot
+}
+
+// MigProfileSnapshot holds the name, max count and details of a MIG profile.
+type MigProfileSnapshot struct {
+	Name           string   `json:"name"`
+	MaxCount       int      `json:"max-count"`
+	ComputeSlices  int      `json:"compute-slices"`
+	GPUSlices      int      `json:"gpu-slices"`
+	MemoryGB       int      `json:"memory-gb"`
+	Attributes     []string `json:"attributes,omitempty"`
+	NegAttributes  []string `json:"neg-attributes,omitempty"`
+	GIProfileID    int      `json:"gi-profile-id"`
+	CIProfileID    int      `json:"ci-profile-id"`
+	CIEngProfileID int      `json:"ci-eng-profile-id"`
+}
+
+// PlacementSnapshot holds a possible placement of a GPU instance.
+type PlacementSnapshot struct {
+	Start uint32 `json:"start"`
+	Size  uint32 `json:"size"`
+}
+
+// NewSnapshot creates a snapshot of the profiles discovered on each device.
+func NewSnapshot(deviceProfiles DeviceProfiles) *Snapshot {
+	snapshot := &Snapshot{Version: SnapshotVersion}
+	for _, index := range slices.Sorted(maps.Keys(deviceProfiles)) {
+		profiles := deviceProfiles[index]
+		device := DeviceSnapshot{Index: index}
+		if len(profiles) > 0 {
+			device.DeviceID = profiles[0].DeviceID.String()
+		}
+		for _, p := range profiles {
+			profile := ProfileSnapshot{MigProfileSnapshot: newMigProfileSnapshot(p.Name, p.MaxCount, p.Profile)}
+			for _, placement := range p.Placements {
+				profile.Placements = append(profile.Placements, PlacementSnapshot{Start: placement.Start, Size: placement.Size})
+			}
+			for _, ci := range p.ComputeInstances {
+				profile.ComputeInstances = append(profile.ComputeInstances, ComputeInstanceSnapshot{
+					MigProfileSnapshot: newMigProfileSnapshot(ci.Name, ci.MaxCount, ci.Profile),
+				})
+			}
+			device.Profiles = append(device.Profiles, profile)
+		}
+		snapshot.Devices = append(snapshot.Devices, device)
+	}
+	return snapshot
+}
+
+func newMigProfileSnapshot(name string, maxCount int, profile nvdev.MigProfile) MigProfileSnapshot {
+	info := profile.GetInfo()
+	return MigProfileSnapshot{
+		Name:           name,
+		MaxCount:       maxCount,
+		ComputeSlices:  info.C,
+		GPUSlices:      info.G,
+		MemoryGB:       info.GB,
+		Attributes:     info.Attributes,
+		NegAttributes:  info.NegAttributes,
+		GIProfileID:    info.GIProfileID,
+		CIProfileID:    info.CIProfileID,
+		CIEngProfileID: info.CIEngProfileID,
+	}
+}
+
+func (p *MigProfileSnapshot) profile() nvdev.MigProfileInfo {
+	return nvdev.MigProfileInfo{
+		C:              p.ComputeSlices,
+		G:              p.GPUSlices,
+		GB:             p.MemoryGB,
+		Attributes:     p.Attributes,
+		NegAttributes:  p.NegAttributes,
+		GIProfileID:    p.GIProfileID,
+		CIProfileID:    p.CIProfileID,
+		CIEngProfileID: p.CIEngProfileID,
+	}
+}
+
+// ParseSnapshotFile reads and validates a snapshot from a JSON (or YAML) file.
+func ParseSnapshotFile(file string) (*Snapshot, error) {
+	snapshotJSON, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read error: %v", err)
+	}
+	return ParseSnapshot(snapshotJSON)
+}
+
+// ParseSnapshot parses and validates a snapshot from JSON (or YAML).
+func ParseSnapshot(snapshotJSON []byte) (*Snapshot, error) {
+	var snapshot Snapshot
+	err := yaml.UnmarshalStrict(snapshotJSON, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+
+	err = snapshot.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// Validate checks that the profiles of a snapshot can be used to generate MIG configs.
+func (s *Snapshot) Validate() error {
+	if s.Version != SnapshotVersion {
+		return fmt.Errorf("unknown version: %v", s.Version)
+	}
+	if len(s.Devices) == 0 {
+		return fmt.Errorf("no devices specified")
+	}
+
+	indices := make(map[int]bool)
+	for i, d := range s.Devices {
+		if d.Index < 0 || indices[d.Index] {
+			return fmt.Errorf("devices[%d]: invalid or duplicate index '%v'", i, d.Index)
+		}
+		indices[d.Index] = true
+		if d.DeviceID == "" {
+			if len(d.Profiles) > 0 {
+				return fmt.Errorf("devices[%d]: missing device-id", i)
+			}
+		} else if _, err := types.NewDeviceIDFromString(d.DeviceID); err != nil {
+			return fmt.Errorf("devices[%d]: invalid device-id '%v': %v", i, d.DeviceID, err)
+		}
+		for j, p := range d.Profiles {
+			if err := p.validate(); err != nil {
+				return fmt.Errorf("devices[%d]: profiles[%d]: %v", i, j, err)
+			}
+			for k, ci := range p.ComputeInstances {
+				if err := ci.validate(); err != nil {
+					return fmt.Errorf("devices[%d]: profiles[%d]: compute-instances[%d]: %v", i, j, k, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *MigProfileSnapshot) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if p.MaxCount < 0 {
+		return fmt.Errorf("invalid max-count '%v' for '%v': must not be negative", p.MaxCount, p.Name)
+	}
+	if p.GPUSlices <= 0 || p.ComputeSlices <= 0 || p.MemoryGB <= 0 {
+		return fmt.Errorf("invalid slices or memory for '%v': must be positive", p.Name)
+	}
+	return nil
+}
+
+// DeviceProfiles returns the profiles of each device of a snapshot, as discovered.
+func (s *Snapshot) DeviceProfiles() (DeviceProfiles, error) {
+	result := make(DeviceProfiles)
+	for _, d := range s.Devices {
+		if len(d.Profiles) == 0 {
+			continue
+		}
+		deviceID, err := types.NewDeviceIDFromString(d.DeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid device-id '%v' for device %d: %w", d.DeviceID, d.Index, err)
+		}
+
+		var profiles []ProfileInfo
+		for _, p := range d.Profiles {
+			profile := ProfileInfo{
+				Name:     p.Name,
+				MaxCount: p.MaxCount,
+				DeviceID: deviceID,
+				Profile:  p.profile(),
+			}
+			for _, placement := range p.Placements {
+				profile.Placements = append(profile.Placements, nvml.GpuInstancePlacement{Start: placement.Start, Size: placement.Size})
+			}
+			for _, ci := range p.ComputeInstances {
+				profile.ComputeInstances = append(profile.ComputeInstances, ComputeInstanceInfo{
+					Name:     ci.Name,
+					MaxCount: ci.MaxCount,
+					Profile:  ci.profile(),
+				})
+			}
+			profiles = append(profiles, profile)
+		}
+		result[d.Index] = profiles
+	}
+
+	if len(result) == 0 {
+		return nil, ErrNoProfilesDiscovered
+	}
+
+	return result, nil
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package discovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+	"github.com/NVIDIA/mig-parted/pkg/sim/simtest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	nvmllib := simtest.NewNvml(t, sim.GPUSpec{Model: "A100-SXM4-40GB", Count: 2}, sim.GPUSpec{Model: "A30-PCIE-24GB"})
+
+	deviceProfiles, err := DiscoverMIGProfilesFrom(nvmllib)
+	require.Nil(t, err, "Unexpected failure from DiscoverMIGProfilesFrom")
+
+	snapshot := NewSnapshot(deviceProfiles)
+	require.Len(t, snapshot.Devices, 3)
+	require.Equal(t, "0x20B710DE", snapshot.Devices[2].DeviceID)
+
+	marshalers := map[string]func(any) ([]byte, error){
+		"JSON": func(v any) ([]byte, error) { return json.MarshalIndent(v, "", "  ") },
+		"YAML": func(v any) ([]byte, error) { return yaml.Marshal(v) },
+	}
+	for name, marshal := range marshalers {
+		t.Run(name, func(t *testing.T) {
+			data, err := marshal(snapshot)
+			require.Nil(t, err)
+
+			file := filepath.Join(t.TempDir(), "profiles")
+			require.Nil(t, os.WriteFile(file, data, 0600))
+
+			parsed, err := ParseSnapshotFile(file)
+			require.Nil(t, err, "Unexpected failure from ParseSnapshotFile")
+			require.Equal(t, snapshot, parsed)
+
+			replayed, err := parsed.DeviceProfiles()
+			require.Nil(t, err, "Unexpected failure from DeviceProfiles")
+			require.Len(t, replayed, len(deviceProfiles))
+			for i, profiles := range deviceProfiles {
+				require.Len(t, replayed[i], len(profiles))
+				for j, p := range profiles {
+					r := replayed[i][j]
+					require.Equal(t, p.Name, r.Name)
+					require.Equal(t, p.MaxCount, r.MaxCount)
+					require.Equal(t, p.DeviceID, r.DeviceID)
+					require.Equal(t, p.Profile.GetInfo(), r.Profile.GetInfo())
+					require.Equal(t, p.Placements, r.Placements)
+					require.Len(t, r.ComputeInstances, len(p.ComputeInstances))
+					for k, ci := range p.ComputeInstances {
+						require.Equal(t, ci.Name, r.ComputeInstances[k].Name)
+						require.Equal(t, ci.MaxCount, r.ComputeInstances[k].MaxCount)
+						require.Equal(t, ci.Profile.GetInfo(), r.ComputeInstances[k].Profile.GetInfo())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotRoundTripWithoutProfiles(t *testing.T) {
+	// A device without profiles and profiles that cannot be created (e.g.
+	// after a quirk) make it into a snapshot, and are read back from it.
+	deviceProfiles := DeviceProfiles{
+		0: {{
+			Name:     "2g.10gb",
+			MaxCount: 0,
+			DeviceID: types.NewDeviceID(0x20B0, 0x10DE),
+			Profile:  nvdev.MigProfileInfo{C: 2, G: 2, GB: 10},
+			ComputeInstances: []ComputeInstanceInfo{{
+				Name:     "1c.2g.10gb",
+				MaxCount: 0,
+				Profile:  nvdev.MigProfileInfo{C: 1, G: 2, GB: 10},
+			}},
+		}},
+		1: nil,
+	}
+
+	snapshot := NewSnapshot(deviceProfiles)
+	require.Nil(t, snapshot.Validate(), "Unexpected failure validating a new snapshot")
+
+	data, err := json.Marshal(snapshot)
+	require.Nil(t, err)
+	parsed, err := ParseSnapshot(data)
+	require.Nil(t, err, "Unexpected failure from ParseSnapshot")
+	require.Equal(t, snapshot, parsed)
+
+	// Devices without profiles are left out, as they are by discovery.
+	replayed, err := parsed.DeviceProfiles()
+	require.Nil(t, err, "Unexpected failure from DeviceProfiles")
+	require.Equal(t, DeviceProfiles{0: deviceProfiles[0]}, replayed)
+}
+
+func TestParseSnapshot(t *testing.T) {
+	testCases := []struct {
+		description   string
+		snapshot      string
+		expectedError string
+	}{
+		{"Unknown Version", `{"version": "v2", "devices": []}`, "unknown version"},
+		{"No Devices", `{"version": "v1", "devices": []}`, "no devices specified"},
+		{"Unknown Field", `{"version": "v1", "devices": [{"index": 0, "device-id": "0x20B010DE", "gpus": []}]}`, "unknown field"},
+		{"Duplicate Index", `{"version": "v1", "devices": [{"index": 0, "device-id": "0x20B010DE"}, {"index": 0, "device-id": "0x20B010DE"}]}`, "devices[1]: invalid or duplicate index '0'"},
+		{"Invalid Device ID", `{"version": "v1", "devices": [{"index": 0, "device-id": "A100"}]}`, "invalid device-id 'A100'"},
+		{"Missing Profile Name", `{"version": "v1", "devices": [{"index": 0, "device-id": "0x20B010DE", "profiles": [{"max-count": 7}]}]}`, "profiles[0]: missing name"},
+		{"Missing Device ID", `{"version": "v1", "devices": [{"index": 0, "profiles": [{"name": "1g.5gb", "max-count": 7}]}]}`, "devices[0]: missing device-id"},
+		{"Invalid Max Count", `{"version": "v1", "devices": [{"index": 0, "device-id": "0x20B010DE", "profiles": [{"name": "1g.5gb", "max-count": -1}]}]}`, "invalid max-count '-1'"},
+		{"Invalid Slices", `{"version": "v1", "devices": [{"index": 0, "device-id": "0x20B010DE", "profiles": [{"name": "1g.5gb", "max-count": 7}]}]}`, "invalid slices or memory"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseSnapshot([]byte(tc.snapshot))
+			require.NotNil(t, err, "Unexpected success from ParseSnapshot")
+			require.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+
+	snapshot, err := ParseSnapshot([]byte(`{"version": "v1", "devices": [{"index": 0, "device-id": "0x20B010DE"}]}`))
+	require.Nil(t, err)
+	_, err = snapshot.DeviceProfiles()
+	require.ErrorIs(t, err, ErrNoProfilesDiscovered)
+}
@@ -17,7 +17,9 @@
 package mode
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -42,23 +44,70 @@ const (
 
 	WaitForBootTimeout       = 5 * time.Second
 	WaitForBootSleepInterval = 100 * time.Millisecond
+
+	maxObservedBootValues = 8
 )
 
+var errBootTimeout = errors.New("timeout waiting for GPU to boot")
+
 var migCapablePmcIDs = []uint32{
 	0x170000a1, // GA100 Chip Set
 }
 
 type pciMigModeManager struct {
-	nvpci nvpci.Interface
+	nvpci            nvpci.Interface
+	bootTimeout      time.Duration
+	bootPollInterval time.Duration
+	bootResetRetries int
 }
 
 var _ Manager = (*pciMigModeManager)(nil)
 
-func NewPciMigModeManager() Manager {
-	return &pciMigModeManager{nvpci.New()}
+// PciOption defines a function for passing options to the NewPciMigModeManager() call.
+type PciOption func(*pciMigModeManager)
+
+// WithBootTimeout sets how long to wait for a GPU to finish booting before accessing its MIG mode registers.
+// It defaults to WaitForBootTimeout.
+func WithBootTimeout(timeout time.Duration) PciOption {
+	return func(m *pciMigModeManager) {
+		m.bootTimeout = timeout
+	}
 }
 
-func (m *pciMigModeManager) openBar0(gpu int) (mmio.Mmio, error) {
+// WithBootPollInterval sets how often to check whether a GPU has finished booting.
+// It defaults to WaitForBootSleepInterval.
+func WithBootPollInterval(interval time.Duration) PciOption {
+	return func(m *pciMigModeManager) {
+		m.bootPollInterval = interval
+	}
+}
+
+// WithBootResetRetries sets how many times a GPU that does not finish booting in time is
+// reset (by writing to its sysfs 'reset' file) before waiting for it to boot again.
+// It defaults to 0, i.e. no resets are triggered.
+func WithBootResetRetries(retries int) PciOption {
+	return func(m *pciMigModeManager) {
+		m.bootResetRetries = retries
+	}
+}
+
+func NewPciMigModeManager(opts ...PciOption) Manager {
+	return newPciMigModeManager(nvpci.New(), opts...)
+}
+
+func newPciMigModeManager(nvpci nvpci.Interface, opts ...PciOption) *pciMigModeManager {
+	m := &pciMigModeManager{
+		nvpci:            nvpci,
+		bootTimeout:      WaitForBootTimeout,
+		bootPollInterval: WaitForBootSleepInterval,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *pciMigModeManager) getGPU(gpu int) (*nvpci.NvidiaPCIDevice, error) {
 	gpus, err := m.nvpci.GetGPUs()
 	if err != nil {
 		return nil, fmt.Errorf("error getting list of GPUs: %v", err)
@@ -68,7 +117,15 @@ func (m *pciMigModeManager) openBar0(gpu int) (mmio.Mmio, error) {
 		return nil, fmt.Errorf("GPU index out of range: %v", gpu)
 	}
 
-	device := gpus[gpu]
+	return gpus[gpu], nil
+}
+
+func (m *pciMigModeManager) openBar0(gpu int) (mmio.Mmio, error) {
+	device, err := m.getGPU(gpu)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(device.Resources) < 1 {
 		return nil, fmt.Errorf("missing bar0 MMIO resource")
 	}
@@ -82,16 +139,11 @@ func (m *pciMigModeManager) openBar0(gpu int) (mmio.Mmio, error) {
 }
 
 func (m *pciMigModeManager) openBar0ReadOnly(gpu int) (mmio.Mmio, error) {
-	gpus, err := m.nvpci.GetGPUs()
+	device, err := m.getGPU(gpu)
 	if err != nil {
-		return nil, fmt.Errorf("error getting list of GPUs: %v", err)
-	}
-
-	if gpu >= len(gpus) {
-		return nil, fmt.Errorf("GPU index out of range: %v", gpu)
+		return nil, err
 	}
 
-	device := gpus[gpu]
 	if len(device.Resources) < 1 {
 		return nil, fmt.Errorf("missing bar0 MMIO resource")
 	}
@@ -111,44 +163,75 @@ func (m *pciMigModeManager) tryCloseBar0(bar0 mmio.Mmio) {
 	}
 }
 
+// waitForBoot polls the boot-complete register of a GPU until it reports that the GPU has booted.
+// On timeout, the error returned wraps errBootTimeout and lists the values observed in the register.
 func (m *pciMigModeManager) waitForBoot(bar0 mmio.Mmio) error {
-	const iterations = int(WaitForBootTimeout / WaitForBootSleepInterval)
-	attempts := 0
+	var observed []string
+	deadline := time.Now().Add(m.bootTimeout)
 	for {
 		reg := bar0.Read32(BootCompleteReg)
 		if reg == BootCompleteValue {
 			return nil
 		}
-		attempts++
-		if attempts == iterations {
+		value := fmt.Sprintf("0x%08x", reg)
+		if len(observed) < maxObservedBootValues && (len(observed) == 0 || observed[len(observed)-1] != value) {
+			observed = append(observed, value)
+		}
+		if !time.Now().Before(deadline) {
 			break
 		}
-		time.Sleep(WaitForBootSleepInterval)
+		time.Sleep(m.bootPollInterval)
 	}
-	return fmt.Errorf("timeout after %v seconds", WaitForBootTimeout)
+	return fmt.Errorf("%w: register 0x%x did not read 0x%08x after %v (observed %v)", errBootTimeout, BootCompleteReg, BootCompleteValue, m.bootTimeout, strings.Join(observed, ", "))
 }
 
-func (m *pciMigModeManager) openBar0AndWaitForBoot(gpu int) (_ mmio.Mmio, rerr error) {
-	bar0, err := m.openBar0(gpu)
+// resetGPU triggers a reset of a GPU through its sysfs 'reset' file.
+func (m *pciMigModeManager) resetGPU(gpu int) error {
+	device, err := m.getGPU(gpu)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer func() {
-		if rerr != nil {
-			m.tryCloseBar0(bar0)
+	return device.Reset()
+}
+
+// openBar0AndWaitForBoot opens bar0 of a GPU and waits for it to boot.
+func (m *pciMigModeManager) openBar0AndWaitForBoot(gpu int) (mmio.Mmio, error) {
+	return m.openAndWaitForBootWithRetries(gpu, m.openBar0)
+}
+
+// openBar0ReadOnlyAndWaitForBoot opens bar0 of a GPU read-only and waits for it to boot.
+func (m *pciMigModeManager) openBar0ReadOnlyAndWaitForBoot(gpu int) (mmio.Mmio, error) {
+	return m.openAndWaitForBootWithRetries(gpu, m.openBar0ReadOnly)
+}
+
+// openAndWaitForBootWithRetries resets a GPU and waits for it to boot again, up to
+// bootResetRetries times, if it does not boot in time.
+func (m *pciMigModeManager) openAndWaitForBootWithRetries(gpu int, open func(int) (mmio.Mmio, error)) (mmio.Mmio, error) {
+	for attempt := 0; ; attempt++ {
+		bar0, err := m.openAndWaitForBoot(gpu, open)
+		if err == nil {
+			return bar0, nil
+		}
+		if !errors.Is(err, errBootTimeout) {
+			return nil, err
+		}
+		if attempt >= m.bootResetRetries {
+			if attempt > 0 {
+				return nil, fmt.Errorf("error waiting for GPU to boot after %d reset(s): %w", attempt, err)
+			}
+			return nil, fmt.Errorf("error waiting for GPU to boot: %w", err)
 		}
-	}()
 
-	err = m.waitForBoot(bar0)
-	if err != nil {
-		return nil, fmt.Errorf("error waiting for GPU to boot: %v", err)
+		log.Warnf("GPU %v did not boot in time, resetting it (retry %d of %d): %v", gpu, attempt+1, m.bootResetRetries, err)
+		err = m.resetGPU(gpu)
+		if err != nil {
+			return nil, fmt.Errorf("error resetting GPU after waiting for it to boot: %v", err)
+		}
 	}
-
-	return bar0, nil
 }
 
-func (m *pciMigModeManager) openBar0ReadOnlyAndWaitForBoot(gpu int) (_ mmio.Mmio, rerr error) {
-	bar0, err := m.openBar0ReadOnly(gpu)
+func (m *pciMigModeManager) openAndWaitForBoot(gpu int, open func(int) (mmio.Mmio, error)) (_ mmio.Mmio, rerr error) {
+	bar0, err := open(gpu)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +243,7 @@ func (m *pciMigModeManager) openBar0ReadOnlyAndWaitForBoot(gpu int) (_ mmio.Mmio
 
 	err = m.waitForBoot(bar0)
 	if err != nil {
-		return nil, fmt.Errorf("error waiting for GPU to boot: %v", err)
+		return nil, err
 	}
 
 	return bar0, nil
@@ -18,7 +18,10 @@ package mode
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -30,7 +33,7 @@ type mockPciMigModeManager struct {
 	driverBusy bool
 }
 
-func NewMockPciA100Device() (*mockPciMigModeManager, error) {
+func NewMockPciA100Device(opts ...PciOption) (*mockPciMigModeManager, error) {
 	nvpci, err := nvpci.NewMockNvpci()
 	if err != nil {
 		return nil, fmt.Errorf("error creating Mock A100 PCI device: %v", err)
@@ -42,7 +45,7 @@ func NewMockPciA100Device() (*mockPciMigModeManager, error) {
 	}
 
 	mock := &mockPciMigModeManager{
-		&pciMigModeManager{nvpci},
+		newPciMigModeManager(nvpci, opts...),
 		false,
 	}
 
@@ -159,3 +162,50 @@ func TestPciEnableDisableMig(t *testing.T) {
 	require.Nil(t, err, "Unexpected failure from IsMigModeChangePending")
 	require.True(t, pending)
 }
+
+func TestPciWaitForBootTimeout(t *testing.T) {
+	manager, err := NewMockPciA100Device()
+	require.Nil(t, err, "Error creating MockPciA100Device")
+	defer manager.Cleanup()
+
+	manager.bootTimeout = 20 * time.Millisecond
+	manager.bootPollInterval = time.Millisecond
+
+	err = manager.SetBooted(0, false)
+	require.Nil(t, err, "Unexpected failure from SetBooted")
+
+	start := time.Now()
+	_, err = manager.GetMigMode(0)
+	require.ErrorIs(t, err, errBootTimeout)
+	require.ErrorContains(t, err, fmt.Sprintf("register 0x%x did not read 0x%08x after 20ms (observed 0x00000000)", BootCompleteReg, BootCompleteValue))
+	require.Less(t, time.Since(start), WaitForBootTimeout)
+
+	err = manager.SetBooted(0, true)
+	require.Nil(t, err, "Unexpected failure from SetBooted")
+
+	_, err = manager.GetMigMode(0)
+	require.Nil(t, err, "Unexpected failure from GetMigMode")
+}
+
+func TestPciWaitForBootResetRetries(t *testing.T) {
+	manager, err := NewMockPciA100Device(
+		WithBootTimeout(10*time.Millisecond),
+		WithBootPollInterval(time.Millisecond),
+		WithBootResetRetries(2),
+	)
+	require.Nil(t, err, "Error creating MockPciA100Device")
+	defer manager.Cleanup()
+
+	err = manager.SetBooted(0, false)
+	require.Nil(t, err, "Unexpected failure from SetBooted")
+
+	err = manager.pciMigModeManager.SetMigMode(0, Enabled)
+	require.ErrorIs(t, err, errBootTimeout)
+	require.ErrorContains(t, err, "error waiting for GPU to boot after 2 reset(s)")
+
+	device, err := manager.getGPU(0)
+	require.Nil(t, err, "Unexpected failure from getGPU")
+	reset, err := os.ReadFile(filepath.Join(device.Path, "reset"))
+	require.Nil(t, err, "Expected GPU to have been reset")
+	require.Equal(t, "1", string(reset))
+}
@@ -0,0 +1,434 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package packer solves for the MIG configuration of a node that provides the
+// MIG devices needed by its workloads, given as counts of instances with a
+// minimum amount of memory or compute.
+package packer
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	log "github.com/sirupsen/logrus"
+
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// DefaultConfigName is the name of the MIG config generated when packing requirements.
+const DefaultConfigName = "packed"
+
+// maxStates bounds the number of partial solutions considered, which grows with
+// the product of the counts of all requirements.
+const maxStates = 1 << 20
+
+// Result is the outcome of packing requirements onto the GPUs of a node.
+type Result struct {
+	// Spec holds a single MIG config providing the packed MIG devices.
+	Spec *migspec.Spec
+	// Assignments lists the MIG devices of each GPU used for each requirement.
+	Assignments []Assignment
+	// Unmet explains the requirements that could not be (fully) met.
+	Unmet []Unmet
+	// UsedSlices is the number of memory slices occupied by the packed MIG devices.
+	UsedSlices int
+	// WastedSlices is the number of memory slices beyond those of the smallest
+	// profile meeting each requirement on each GPU.
+	WastedSlices int
+}
+
+// Assignment records a number of MIG devices of a GPU used for a requirement.
+type Assignment struct {
+	Device      int
+	Requirement string
+	Profile     string
+	Count       int
+}
+
+// Unmet explains why some instances of a requirement could not be packed.
+type Unmet struct {
+	Requirement string
+	Missing     int
+	Reason      string
+}
+
+// cost orders solutions serving the same instances, preferring less waste.
+type cost struct {
+	wasted int
+	used   int
+}
+
+func (c cost) add(o cost) cost {
+	return cost{c.wasted + o.wasted, c.used + o.used}
+}
+
+func (c cost) compare(o cost) int {
+	return cmp.Or(cmp.Compare(c.wasted, o.wasted), cmp.Compare(c.used, o.used))
+}
+
+// option is a way of using a single GPU: the number of instances of each
+// profile (indexed by requirement, then profile) and the instances served.
+type option struct {
+	served []int
+	counts [][]int
+	cost   cost
+}
+
+// deviceType holds the profiles of a type of device that may be used for the requirements.
+type deviceType struct {
+	profiles []discovery.ProfileInfo
+	// eligible lists the profiles (by index) that meet each requirement.
+	eligible [][]int
+	options  []*option
+}
+
+// candidate is an instance of a profile at one of its possible placements, used for a requirement.
+type candidate struct {
+	requirement int
+	profile     int
+	placement   nvml.GpuInstancePlacement
+}
+
+// state is a partial solution after deciding the options of a prefix of the devices.
+type state struct {
+	served []int
+	cost   cost
+	prev   *state
+	option *option
+}
+
+// Pack solves for a MIG config that provides the instances required across the
+// devices (and their profiles) given, returning the config as part of a 'Result'
+// that also explains any requirements that cannot be met. Only profiles without
+// attributes (e.g. +me, +gfx) are used.
+func Pack(deviceProfiles discovery.DeviceProfiles, requirements *Requirements, configName string) (*Result, error) {
+	err := requirements.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid requirements: %w", err)
+	}
+
+	reqs := requirements.Requirements
+	demand := make([]int, len(reqs))
+	states := 1
+	for r, req := range reqs {
+		demand[r] = req.Count
+		states *= req.Count + 1
+		if states > maxStates {
+			return nil, fmt.Errorf("too many combinations of requirements to pack: reduce the number of requirements or their counts")
+		}
+	}
+
+	var devices []int
+	for _, i := range slices.Sorted(maps.Keys(deviceProfiles)) {
+		if len(deviceProfiles[i]) > 0 {
+			devices = append(devices, i)
+		}
+	}
+	deviceTypes := make(map[string]*deviceType)
+	for _, i := range devices {
+		deviceID := deviceProfiles[i][0].DeviceID.String()
+		if _, exists := deviceTypes[deviceID]; exists {
+			continue
+		}
+		dt, err := newDeviceType(deviceID, deviceProfiles[i], reqs, demand)
+		if err != nil {
+			return nil, err
+		}
+		deviceTypes[deviceID] = dt
+	}
+
+	// Decide the option used by each device in turn, keeping the cheapest
+	// partial solution for each number of instances served so far. Among equally
+	// cheap solutions, those that serve more instances on earlier devices win.
+	current := map[string]*state{countsKey(make([]int, len(reqs))): {served: make([]int, len(reqs))}}
+	for _, i := range devices {
+		dt := deviceTypes[deviceProfiles[i][0].DeviceID.String()]
+		next := make(map[string]*state)
+		for _, key := range slices.Backward(slices.Sorted(maps.Keys(current))) {
+			s := current[key]
+			for _, o := range dt.options {
+				served, ok := addServed(s.served, o.served, demand)
+				if !ok {
+					continue
+				}
+				candidate := &state{served: served, cost: s.cost.add(o.cost), prev: s, option: o}
+				key := countsKey(served)
+				if best, exists := next[key]; !exists || candidate.cost.compare(best.cost) < 0 {
+					next[key] = candidate
+				}
+			}
+		}
+		current = next
+	}
+
+	var best *state
+	for _, s := range current {
+		if best == nil || compareStates(s, best) < 0 {
+			best = s
+		}
+	}
+
+	// Walk back through the decisions to recover the option used by each device.
+	chosen := make(map[int]*option)
+	s := best
+	for j := len(devices) - 1; j >= 0; j-- {
+		chosen[devices[j]] = s.option
+		s = s.prev
+	}
+
+	result := &Result{
+		Spec:         buildSpec(devices, deviceProfiles, chosen, configName),
+		UsedSlices:   best.cost.used,
+		WastedSlices: best.cost.wasted,
+	}
+	for _, i := range devices {
+		profiles := deviceProfiles[i]
+		for r, counts := range chosen[i].counts {
+			for p, count := range counts {
+				if count > 0 {
+					result.Assignments = append(result.Assignments, Assignment{
+						Device:      i,
+						Requirement: reqs[r].Name,
+						Profile:     profiles[p].Name,
+						Count:       count,
+					})
+				}
+			}
+		}
+	}
+	for r, req := range reqs {
+		if best.served[r] < req.Count {
+			result.Unmet = append(result.Unmet, Unmet{
+				Requirement: req.Name,
+				Missing:     req.Count - best.served[r],
+				Reason:      explainUnmet(r, &req, best.served[r], devices, deviceProfiles, deviceTypes),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// newDeviceType works out every way of using a device of the given type for the requirements.
+func newDeviceType(deviceID string, profiles []discovery.ProfileInfo, reqs []Requirement, demand []int) (*deviceType, error) {
+	dt := &deviceType{
+		profiles: profiles,
+		eligible: make([][]int, len(reqs)),
+	}
+
+	// The smallest number of memory slices needed for each requirement, which
+	// makes up the waste of using any larger profile for it.
+	minSlices := make([]uint32, len(reqs))
+	var candidates []candidate
+	for p, pInfo := range profiles {
+		info := pInfo.Profile.GetInfo()
+		if len(info.Attributes) > 0 || len(info.NegAttributes) > 0 {
+			continue
+		}
+		if len(pInfo.Placements) == 0 {
+			log.Warnf("Profile %s on device %s has no known placements, skipping it when packing", pInfo.Name, deviceID)
+			continue
+		}
+		if err := pInfo.CheckPlacementMasks(); err != nil {
+			return nil, fmt.Errorf("device %s: %w", deviceID, err)
+		}
+		for r := range reqs {
+			if !reqs[r].satisfiedBy(info.G, info.GB) {
+				continue
+			}
+			dt.eligible[r] = append(dt.eligible[r], p)
+			size := pInfo.Placements[0].Size
+			if minSlices[r] == 0 || size < minSlices[r] {
+				minSlices[r] = size
+			}
+			for _, placement := range pInfo.Placements {
+				candidates = append(candidates, candidate{r, p, placement})
+			}
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(
+			cmp.Compare(a.placement.Start, b.placement.Start),
+			cmp.Compare(a.placement.Size, b.placement.Size),
+		)
+	})
+
+	// Visit every set of non-overlapping candidates, keeping the cheapest for
+	// each number of instances served. Instances of the same profile are
+	// interchangeable, so they are only assigned to requirements in order.
+	best := make(map[string]*option)
+	served := make([]int, len(reqs))
+	counts := make([][]int, len(reqs))
+	for r := range counts {
+		counts[r] = make([]int, len(profiles))
+	}
+	profileCounts := make([]int, len(profiles))
+	lastRequirement := make([]int, len(profiles))
+	var current cost
+	var visit func(next int, used uint64)
+	visit = func(next int, used uint64) {
+		key := countsKey(served)
+		if o, exists := best[key]; !exists || current.compare(o.cost) < 0 {
+			o := &option{served: slices.Clone(served), cost: current}
+			for _, c := range counts {
+				o.counts = append(o.counts, slices.Clone(c))
+			}
+			best[key] = o
+		}
+		for i := next; i < len(candidates); i++ {
+			c := candidates[i]
+			mask := discovery.PlacementMask(c.placement)
+			if used&mask != 0 ||
+				served[c.requirement] >= demand[c.requirement] ||
+				profileCounts[c.profile] >= profiles[c.profile].MaxCount ||
+				c.requirement < lastRequirement[c.profile] {
+				continue
+			}
+			previousRequirement := lastRequirement[c.profile]
+			previousCost := current
+			served[c.requirement]++
+			counts[c.requirement][c.profile]++
+			profileCounts[c.profile]++
+			lastRequirement[c.profile] = c.requirement
+			current = current.add(cost{int(c.placement.Size - minSlices[c.requirement]), int(c.placement.Size)})
+			visit(i+1, used|mask)
+			current = previousCost
+			lastRequirement[c.profile] = previousRequirement
+			profileCounts[c.profile]--
+			counts[c.requirement][c.profile]--
+			served[c.requirement]--
+		}
+	}
+	visit(0, 0)
+
+	for _, key := range slices.Sorted(maps.Keys(best)) {
+		dt.options = append(dt.options, best[key])
+	}
+	return dt, nil
+}
+
+// addServed adds the instances served by an option to those served so far,
+// failing if more instances than required would be served.
+func addServed(served, more, demand []int) ([]int, bool) {
+	sum := make([]int, len(served))
+	for r := range served {
+		sum[r] = served[r] + more[r]
+		if sum[r] > demand[r] {
+			return nil, false
+		}
+	}
+	return sum, true
+}
+
+// compareStates orders complete solutions, preferring those serving the most instances.
+func compareStates(a, b *state) int {
+	total := func(s *state) int {
+		sum := 0
+		for _, n := range s.served {
+			sum += n
+		}
+		return sum
+	}
+	return cmp.Or(
+		-cmp.Compare(total(a), total(b)),
+		a.cost.compare(b.cost),
+		cmp.Compare(countsKey(a.served), countsKey(b.served)),
+	)
+}
+
+func countsKey(counts []int) string {
+	return fmt.Sprint(counts)
+}
+
+// explainUnmet describes why only some of the instances of a requirement were served.
+func explainUnmet(r int, req *Requirement, served int, devices []int, deviceProfiles discovery.DeviceProfiles, deviceTypes map[string]*deviceType) string {
+	capacity := 0
+	eligible := false
+	for _, i := range devices {
+		dt := deviceTypes[deviceProfiles[i][0].DeviceID.String()]
+		if len(dt.eligible[r]) > 0 {
+			eligible = true
+		}
+		capacity += dt.capacity(r)
+	}
+
+	switch {
+	case !eligible:
+		return "no MIG profile of any GPU meets the requirement"
+	case capacity < req.Count:
+		return fmt.Sprintf("the GPUs have room for at most %d such instance(s)", capacity)
+	default:
+		return fmt.Sprintf("only %d instance(s) fit alongside the other requirements", served)
+	}
+}
+
+// capacity returns the most instances of a single requirement a device can serve on its own.
+func (dt *deviceType) capacity(r int) int {
+	most := 0
+	for _, o := range dt.options {
+		alone := true
+		for other, n := range o.served {
+			if other != r && n > 0 {
+				alone = false
+			}
+		}
+		if alone {
+			most = max(most, o.served[r])
+		}
+	}
+	return most
+}
+
+// buildSpec creates a MIG config from the option chosen for each device,
+// grouping devices with the same MIG devices and disabling MIG on unused ones.
+func buildSpec(devices []int, deviceProfiles discovery.DeviceProfiles, chosen map[int]*option, configName string) *migspec.Spec {
+	var configSpecs migspec.MigConfigSpecSlice
+	groups := make(map[string]int)
+	for _, i := range devices {
+		migDevices := make(types.MigConfig)
+		for _, counts := range chosen[i].counts {
+			for p, count := range counts {
+				if count > 0 {
+					migDevices[deviceProfiles[i][p].Name] += count
+				}
+			}
+		}
+
+		key := fmt.Sprint(migDevices)
+		if g, exists := groups[key]; exists {
+			configSpecs[g].Devices = append(configSpecs[g].Devices.([]int), i)
+			continue
+		}
+		groups[key] = len(configSpecs)
+		configSpecs = append(configSpecs, migspec.MigConfigSpec{
+			Devices:    []int{i},
+			MigEnabled: len(migDevices) > 0,
+			MigDevices: migDevices,
+		})
+	}
+
+	return &migspec.Spec{
+		Version: migspec.Version,
+		MigConfigs: map[string]migspec.MigConfigSpecSlice{
+			configName: configSpecs,
+		},
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packer
+
+import (
+	"testing"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+
+	migspec "github.com/NVIDIA/mig-parted/api/spec/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/config"
+	"github.com/NVIDIA/mig-parted/pkg/mig/discovery"
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+	"github.com/NVIDIA/mig-parted/pkg/sim/simtest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func mockPlacements(size uint32, starts ...uint32) []nvml.GpuInstancePlacement {
+	var placements []nvml.GpuInstancePlacement
+	for _, start := range starts {
+		placements = append(placements, nvml.GpuInstancePlacement{Start: start, Size: size})
+	}
+	return placements
+}
+
+// a100Profiles returns the profiles of an A100-80GB along with their placements on 8 memory slices.
+func a100Profiles() []discovery.ProfileInfo {
+	deviceID := types.NewDeviceID(0x20B5, 0x10DE)
+	profile := func(name string, maxCount, g, gb int, placements []nvml.GpuInstancePlacement, attrs ...string) discovery.ProfileInfo {
+		return discovery.ProfileInfo{
+			Name:       name,
+			MaxCount:   maxCount,
+			DeviceID:   deviceID,
+			Profile:    nvdev.MigProfileInfo{C: g, G: g, GB: gb, Attributes: attrs},
+			Placements: placements,
+		}
+	}
+	return []discovery.ProfileInfo{
+		profile("1g.10gb", 7, 1, 10, mockPlacements(1, 0, 1, 2, 3, 4, 5, 6)),
+		profile("1g.10gb+me", 1, 1, 10, mockPlacements(1, 0, 1, 2, 3, 4, 5, 6), "me"),
+		profile("1g.20gb", 4, 1, 20, mockPlacements(2, 0, 2, 4, 6)),
+		profile("2g.20gb", 3, 2, 20, mockPlacements(2, 0, 2, 4)),
+		profile("3g.40gb", 2, 3, 40, mockPlacements(4, 0, 4)),
+		profile("4g.40gb", 1, 4, 40, mockPlacements(4, 0)),
+		profile("7g.80gb", 1, 7, 80, mockPlacements(8, 0)),
+	}
+}
+
+func newRequirements(reqs ...Requirement) *Requirements {
+	return &Requirements{Version: Version, Requirements: reqs}
+}
+
+func TestParseRequirements(t *testing.T) {
+	requirements, err := ParseRequirements([]byte(`
+version: v1
+requirements:
+- name: inference
+  count: 12
+  min-memory-gb: 10
+- name: training
+  count: 4
+  min-memory-gb: 40
+  min-compute-slices: 3
+`))
+	require.Nil(t, err, "Unexpected failure from ParseRequirements")
+	require.Equal(t, newRequirements(
+		Requirement{Name: "inference", Count: 12, MinMemoryGB: 10},
+		Requirement{Name: "training", Count: 4, MinMemoryGB: 40, MinComputeSlices: 3},
+	), requirements)
+	require.Equal(t, "training (4x >= 40GB >= 3 compute slices)", requirements.Requirements[1].String())
+
+	testCases := []struct {
+		description   string
+		requirements  string
+		expectedError string
+	}{
+		{"Unknown Version", "version: v2\nrequirements: [{name: a, count: 1}]", "unknown version"},
+		{"No Requirements", "version: v1\nrequirements: []", "no requirements specified"},
+		{"Unknown Field", "version: v1\nrequirements: [{name: a, count: 1, min-memory: 10}]", "unknown field"},
+		{"Missing Name", "version: v1\nrequirements: [{count: 1}]", "missing name"},
+		{"Duplicate Name", "version: v1\nrequirements: [{name: a, count: 1}, {name: a, count: 2}]", "duplicate name 'a'"},
+		{"Invalid Count", "version: v1\nrequirements: [{name: a}]", "invalid count '0'"},
+		{"Negative Memory", "version: v1\nrequirements: [{name: a, count: 1, min-memory-gb: -1}]", "invalid min-memory-gb '-1'"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseRequirements([]byte(tc.requirements))
+			require.NotNil(t, err, "Unexpected success from ParseRequirements")
+			require.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestPack(t *testing.T) {
+	deviceProfiles := discovery.DeviceProfiles{0: a100Profiles(), 1: a100Profiles()}
+
+	result, err := Pack(deviceProfiles, newRequirements(
+		Requirement{Name: "inference", Count: 8, MinMemoryGB: 10},
+		Requirement{Name: "training", Count: 2, MinMemoryGB: 40},
+	), DefaultConfigName)
+	require.Nil(t, err, "Unexpected failure from Pack")
+
+	require.Empty(t, result.Unmet)
+	require.Equal(t, 16, result.UsedSlices)
+	require.Equal(t, 0, result.WastedSlices)
+	require.Equal(t, &migspec.Spec{
+		Version: migspec.Version,
+		MigConfigs: map[string]migspec.MigConfigSpecSlice{
+			DefaultConfigName: {{
+				Devices:    []int{0, 1},
+				MigEnabled: true,
+				MigDevices: types.MigConfig{"1g.10gb": 4, "3g.40gb": 1},
+			}},
+		},
+	}, result.Spec)
+	require.Equal(t, []Assignment{
+		{Device: 0, Requirement: "inference", Profile: "1g.10gb", Count: 4},
+		{Device: 0, Requirement: "training", Profile: "3g.40gb", Count: 1},
+		{Device: 1, Requirement: "inference", Profile: "1g.10gb", Count: 4},
+		{Device: 1, Requirement: "training", Profile: "3g.40gb", Count: 1},
+	}, result.Assignments)
+}
+
+func TestPackWaste(t *testing.T) {
+	profiles := a100Profiles()
+	profiles[0].MaxCount = 1
+
+	// With a single 1g.10gb instance per GPU, the second instance needs a 1g.20gb.
+	result, err := Pack(discovery.DeviceProfiles{0: profiles}, newRequirements(
+		Requirement{Name: "small", Count: 2, MinMemoryGB: 10},
+	), "custom")
+	require.Nil(t, err, "Unexpected failure from Pack")
+	require.Empty(t, result.Unmet)
+	require.Equal(t, 3, result.UsedSlices)
+	require.Equal(t, 1, result.WastedSlices)
+	require.Equal(t, types.MigConfig{"1g.10gb": 1, "1g.20gb": 1}, result.Spec.MigConfigs["custom"][0].MigDevices)
+}
+
+func TestPackUnmet(t *testing.T) {
+	deviceProfiles := discovery.DeviceProfiles{0: a100Profiles(), 1: a100Profiles(), 2: a100Profiles()}
+
+	result, err := Pack(deviceProfiles, newRequirements(
+		Requirement{Name: "huge", Count: 1, MinMemoryGB: 100},
+		Requirement{Name: "training", Count: 6, MinMemoryGB: 40},
+		Requirement{Name: "inference", Count: 10, MinMemoryGB: 10},
+	), DefaultConfigName)
+	require.Nil(t, err, "Unexpected failure from Pack")
+	require.Equal(t, []Unmet{
+		{Requirement: "huge", Missing: 1, Reason: "no MIG profile of any GPU meets the requirement"},
+		{Requirement: "training", Missing: 3, Reason: "only 3 instance(s) fit alongside the other requirements"},
+	}, result.Unmet)
+
+	result, err = Pack(deviceProfiles, newRequirements(
+		Requirement{Name: "inference", Count: 30, MinMemoryGB: 10},
+	), DefaultConfigName)
+	require.Nil(t, err, "Unexpected failure from Pack")
+	require.Equal(t, []Unmet{
+		{Requirement: "inference", Missing: 9, Reason: "the GPUs have room for at most 21 such instance(s)"},
+	}, result.Unmet)
+}
+
+func TestPackUnusedDevices(t *testing.T) {
+	deviceProfiles := discovery.DeviceProfiles{0: a100Profiles(), 1: a100Profiles()}
+
+	result, err := Pack(deviceProfiles, newRequirements(
+		Requirement{Name: "training", Count: 1, MinComputeSlices: 7},
+	), DefaultConfigName)
+	require.Nil(t, err, "Unexpected failure from Pack")
+	require.Equal(t, migspec.MigConfigSpecSlice{
+		{Devices: []int{0}, MigEnabled: true, MigDevices: types.MigConfig{"7g.80gb": 1}},
+		{Devices: []int{1}, MigEnabled: false, MigDevices: types.MigConfig{}},
+	}, result.Spec.MigConfigs[DefaultConfigName])
+}
+
+func TestPackSimulatedNode(t *testing.T) {
+	nvmllib := simtest.NewNvml(t, sim.GPUSpec{Model: "H100-SXM5-80GB", Count: 2}, sim.GPUSpec{Model: "A30-PCIE-24GB"})
+
+	deviceProfiles, err := discovery.DiscoverMIGProfilesFrom(nvmllib)
+	require.Nil(t, err, "Unexpected failure from DiscoverMIGProfilesFrom")
+
+	result, err := Pack(deviceProfiles, newRequirements(
+		Requirement{Name: "inference", Count: 10, MinMemoryGB: 10},
+		Requirement{Name: "training", Count: 2, MinMemoryGB: 40},
+	), DefaultConfigName)
+	require.Nil(t, err, "Unexpected failure from Pack")
+	require.Empty(t, result.Unmet)
+	require.Equal(t, 0, result.WastedSlices)
+	require.Equal(t, types.MigConfig{"2g.12gb": 2}, result.Spec.MigConfigs[DefaultConfigName][1].MigDevices)
+
+	// The packed config can be applied to the GPUs of the node.
+	manager := config.NewNvmlMigConfigManager(nvmllib)
+	for _, configSpec := range result.Spec.MigConfigs[DefaultConfigName] {
+		for _, i := range configSpec.Devices.([]int) {
+			if !configSpec.MigEnabled {
+				continue
+			}
+			device, ret := nvmllib.DeviceGetHandleByIndex(i)
+			require.Equal(t, nvml.SUCCESS, ret)
+			ret, _ = device.SetMigMode(nvml.DEVICE_MIG_ENABLE)
+			require.Equal(t, nvml.SUCCESS, ret)
+
+			err := manager.SetMigConfig(i, configSpec.MigDevices)
+			require.Nil(t, err, "Unexpected failure from SetMigConfig with %v", configSpec.MigDevices)
+			exported, err := manager.GetMigConfig(i)
+			require.Nil(t, err)
+			require.Equal(t, configSpec.MigDevices, exported)
+		}
+	}
+}
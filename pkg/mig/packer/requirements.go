@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packer
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Version indicates the version of the 'Requirements' struct used to describe workload requirements.
+const Version = "v1"
+
+// Requirements describes the MIG devices needed by the workloads of a node.
+type Requirements struct {
+	Version      string        `json:"version"`
+	Requirements []Requirement `json:"requirements"`
+}
+
+// Requirement describes a number of identical MIG devices needed by a workload.
+type Requirement struct {
+	Name             string `json:"name"`
+	Count            int    `json:"count"`
+	MinMemoryGB      int    `json:"min-memory-gb,omitempty"`
+	MinComputeSlices int    `json:"min-compute-slices,omitempty"`
+}
+
+// ParseRequirementsFile reads and validates workload requirements from a YAML (or JSON) file.
+func ParseRequirementsFile(file string) (*Requirements, error) {
+	requirementsYaml, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read error: %v", err)
+	}
+	return ParseRequirements(requirementsYaml)
+}
+
+// ParseRequirements parses and validates workload requirements from YAML (or JSON).
+func ParseRequirements(requirementsYaml []byte) (*Requirements, error) {
+	var requirements Requirements
+	err := yaml.UnmarshalStrict(requirementsYaml, &requirements)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+
+	err = requirements.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &requirements, nil
+}
+
+// Validate checks that 'Requirements' can be packed onto a node.
+func (r *Requirements) Validate() error {
+	if r.Version != Version {
+		return fmt.Errorf("unknown version: %v", r.Version)
+	}
+	if len(r.Requirements) == 0 {
+		return fmt.Errorf("no requirements specified")
+	}
+
+	names := make(map[string]bool)
+	for i, req := range r.Requirements {
+		if req.Name == "" {
+			return fmt.Errorf("requirements[%d]: missing name", i)
+		}
+		if names[req.Name] {
+			return fmt.Errorf("requirements[%d]: duplicate name '%v'", i, req.Name)
+		}
+		names[req.Name] = true
+		if req.Count <= 0 {
+			return fmt.Errorf("requirements[%d]: invalid count '%v': must be positive", i, req.Count)
+		}
+		if req.MinMemoryGB < 0 {
+			return fmt.Errorf("requirements[%d]: invalid min-memory-gb '%v': must not be negative", i, req.MinMemoryGB)
+		}
+		if req.MinComputeSlices < 0 {
+			return fmt.Errorf("requirements[%d]: invalid min-compute-slices '%v': must not be negative", i, req.MinComputeSlices)
+		}
+	}
+
+	return nil
+}
+
+// String describes a requirement (e.g. "inference (12x >= 10GB)").
+func (r *Requirement) String() string {
+	var limits []string
+	if r.MinMemoryGB > 0 {
+		limits = append(limits, fmt.Sprintf(">= %dGB", r.MinMemoryGB))
+	}
+	if r.MinComputeSlices > 0 {
+		limits = append(limits, fmt.Sprintf(">= %d compute slices", r.MinComputeSlices))
+	}
+	description := fmt.Sprintf("%dx", r.Count)
+	for _, limit := range limits {
+		description += " " + limit
+	}
+	return fmt.Sprintf("%v (%v)", r.Name, description)
+}
+
+// satisfiedBy checks whether an instance of a profile with the given compute slices and memory meets the requirement.
+func (r *Requirement) satisfiedBy(computeSlices, memoryGB int) bool {
+	return computeSlices >= r.MinComputeSlices && memoryGB >= r.MinMemoryGB
+}
@@ -79,6 +79,10 @@ type Options struct {
 	DriverLibraryPath          string
 	NvidiaSMIPath              string
 	NvidiaCDIHookPath          string
+	// Simulated is set when the GPUs of the node are simulated (see the --backend flag of nvidia-mig-parted).
+	// Steps that act on the host rather than on the GPUs (rebooting, reloading systemd, creating device nodes
+	// and CDI specs) are skipped.
+	Simulated bool
 }
 
 // Reconfigure handles the MIG reconfiguration process
@@ -109,18 +113,24 @@ func New(ctx context.Context, clientset *kubernetes.Clientset, migPartedBinary [
 		_ = os.Setenv("DBUS_SYSTEM_BUS_ADDRESS", hostSystemBusAddress)
 	}
 
+	r := &Reconfigure{
+		ctx:             ctx,
+		clientset:       clientset,
+		migPartedBinary: migPartedBinary,
+		opts:            opts,
+	}
+
+	if opts.Simulated {
+		return r, nil
+	}
+
 	systemdManager, err := systemd.NewManager(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize systemd manager: %w", err)
 	}
+	r.systemdManager = systemdManager
 
-	return &Reconfigure{
-		ctx:             ctx,
-		clientset:       clientset,
-		migPartedBinary: migPartedBinary,
-		opts:            opts,
-		systemdManager:  systemdManager,
-	}, nil
+	return r, nil
 }
 
 // Run executes the complete MIG reconfiguration process
@@ -182,7 +192,9 @@ func (r *Reconfigure) Run() error {
 	}
 
 	if err := r.applyMigModeChange(); err != nil {
-		if r.opts.WithReboot {
+		if r.opts.WithReboot && r.opts.Simulated {
+			log.Info("Not rebooting a node with simulated GPUs")
+		} else if r.opts.WithReboot {
 			log.Infof("Changing the '%s' node label to 'rebooting'\n", migConfigStateLabel)
 
 			if err := r.setNodeLabel(migConfigStateLabel, "rebooting"); err != nil {
@@ -208,7 +220,9 @@ func (r *Reconfigure) Run() error {
 		return fmt.Errorf("failed to apply MIG configuration: %w", err)
 	}
 
-	if r.opts.CDIEnabled {
+	if r.opts.CDIEnabled && r.opts.Simulated {
+		log.Info("Skipping nvidia-smi and CDI spec generation for simulated GPUs")
+	} else if r.opts.CDIEnabled {
 		log.Info("Running nvidia-smi")
 		if err := r.runNvidiaSMI(); err != nil {
 			_ = r.setState(migStateFailed)
@@ -332,6 +346,9 @@ Environment="MIG_PARTED_SELECTED_CONFIG=%s"
 		return fmt.Errorf("failed to write config to state file: %w", err)
 	}
 
+	if r.systemdManager == nil {
+		return nil
+	}
 	return r.systemdManager.ReloadDaemon()
 }
 
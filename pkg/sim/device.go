@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim
+
+import (
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/server"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// setDeviceMockFuncs layers the behaviour of real hardware that the mock server lacks
+// on top of a mock device: GPU and compute instances only fit where their placement is
+// free and up to the instance count of their profile, MIG mode cannot be disabled while
+// GPU instances exist, and every successful change is persisted to the state file.
+func (n *Node) setDeviceMockFuncs(d *server.Device, subsystemID types.DeviceID) {
+	d.GetPciInfoFunc = func() (nvml.PciInfo, nvml.Return) {
+		p := nvml.PciInfo{
+			Domain:         0,
+			Bus:            uint32(d.Index),
+			PciDeviceId:    d.Config.PciDeviceId,
+			PciSubSystemId: uint32(subsystemID.Device)<<16 | uint32(subsystemID.Vendor),
+		}
+		for i, c := range d.PciBusID {
+			p.BusId[i] = int8(c)
+		}
+		return p, nvml.SUCCESS
+	}
+
+	d.GetComputeRunningProcessesFunc = func() ([]nvml.ProcessInfo, nvml.Return) {
+		return nil, nvml.SUCCESS
+	}
+
+	d.GetGraphicsRunningProcessesFunc = func() ([]nvml.ProcessInfo, nvml.Return) {
+		return nil, nvml.SUCCESS
+	}
+
+	setMigMode := d.SetMigModeFunc
+	d.SetMigModeFunc = func(mode int) (nvml.Return, nvml.Return) {
+		if mode == nvml.DEVICE_MIG_DISABLE && len(getGpuInstances(d)) > 0 {
+			return nvml.ERROR_IN_USE, nvml.ERROR_IN_USE
+		}
+		ret, activationStatus := setMigMode(mode)
+		if ret != nvml.SUCCESS {
+			return ret, activationStatus
+		}
+		return n.saveAfter(ret), activationStatus
+	}
+
+	createGpuInstanceWithPlacement := d.CreateGpuInstanceWithPlacementFunc
+	d.CreateGpuInstanceWithPlacementFunc = func(info *nvml.GpuInstanceProfileInfo, placement *nvml.GpuInstancePlacement) (nvml.GpuInstance, nvml.Return) {
+		if ret := assertMigEnabled(d); ret != nvml.SUCCESS {
+			return nil, ret
+		}
+		if !isPossiblePlacement(d, info, *placement) {
+			return nil, nvml.ERROR_INVALID_ARGUMENT
+		}
+		if !isFreePlacement(d, *placement) || !hasInstancesLeft(d, info) {
+			return nil, nvml.ERROR_INSUFFICIENT_RESOURCES
+		}
+		gi, ret := createGpuInstanceWithPlacement(info, placement)
+		if ret != nvml.SUCCESS {
+			return nil, ret
+		}
+		n.setGpuInstanceMockFuncs(gi.(*server.GpuInstance))
+		return gi, n.saveAfter(ret)
+	}
+
+	d.CreateGpuInstanceFunc = func(info *nvml.GpuInstanceProfileInfo) (nvml.GpuInstance, nvml.Return) {
+		if ret := assertMigEnabled(d); ret != nvml.SUCCESS {
+			return nil, ret
+		}
+		for _, placement := range d.Config.MIGProfiles.GpuInstancePlacements[int(info.Id)] {
+			if isFreePlacement(d, placement) {
+				return d.CreateGpuInstanceWithPlacement(info, &placement)
+			}
+		}
+		return nil, nvml.ERROR_INSUFFICIENT_RESOURCES
+	}
+}
+
+// setGpuInstanceMockFuncs is the counterpart of setDeviceMockFuncs for GPU instances and their compute instances.
+func (n *Node) setGpuInstanceMockFuncs(gi *server.GpuInstance) {
+	createComputeInstance := gi.CreateComputeInstanceFunc
+	gi.CreateComputeInstanceFunc = func(info *nvml.ComputeInstanceProfileInfo) (nvml.ComputeInstance, nvml.Return) {
+		giProfile := gi.MIGProfiles.GpuInstanceProfiles[int(gi.Info.ProfileId)]
+		if usedComputeInstanceSlices(gi)+info.SliceCount > giProfile.SliceCount {
+			return nil, nvml.ERROR_INSUFFICIENT_RESOURCES
+		}
+		ci, ret := createComputeInstance(info)
+		if ret != nvml.SUCCESS {
+			return nil, ret
+		}
+
+		destroy := ci.(*server.ComputeInstance).DestroyFunc
+		ci.(*server.ComputeInstance).DestroyFunc = func() nvml.Return {
+			ret := destroy()
+			if ret != nvml.SUCCESS {
+				return ret
+			}
+			return n.saveAfter(ret)
+		}
+
+		return ci, n.saveAfter(ret)
+	}
+
+	destroy := gi.DestroyFunc
+	gi.DestroyFunc = func() nvml.Return {
+		gi.RLock()
+		inUse := len(gi.ComputeInstances) > 0
+		gi.RUnlock()
+		if inUse {
+			return nvml.ERROR_IN_USE
+		}
+		ret := destroy()
+		if ret != nvml.SUCCESS {
+			return ret
+		}
+		return n.saveAfter(ret)
+	}
+}
+
+// saveAfter persists the state of the node after a successful change, turning any error into an NVML error.
+func (n *Node) saveAfter(ret nvml.Return) nvml.Return {
+	err := n.save()
+	if err != nil {
+		log.Warnf("Error saving simulated node state: %v", err)
+		return nvml.ERROR_UNKNOWN
+	}
+	return ret
+}
+
+func assertMigEnabled(d *server.Device) nvml.Return {
+	d.RLock()
+	defer d.RUnlock()
+	if d.MigMode != nvml.DEVICE_MIG_ENABLE {
+		return nvml.ERROR_NOT_SUPPORTED
+	}
+	return nvml.SUCCESS
+}
+
+func getGpuInstances(d *server.Device) []*server.GpuInstance {
+	d.RLock()
+	defer d.RUnlock()
+	var gis []*server.GpuInstance
+	for gi := range d.GpuInstances {
+		gis = append(gis, gi)
+	}
+	return gis
+}
+
+func isPossiblePlacement(d *server.Device, info *nvml.GpuInstanceProfileInfo, placement nvml.GpuInstancePlacement) bool {
+	for _, p := range d.Config.MIGProfiles.GpuInstancePlacements[int(info.Id)] {
+		if p == placement {
+			return true
+		}
+	}
+	return false
+}
+
+func isFreePlacement(d *server.Device, placement nvml.GpuInstancePlacement) bool {
+	for _, gi := range getGpuInstances(d) {
+		used := gi.Info.Placement
+		if placement.Start < used.Start+used.Size && used.Start < placement.Start+placement.Size {
+			return false
+		}
+	}
+	return true
+}
+
+func hasInstancesLeft(d *server.Device, info *nvml.GpuInstanceProfileInfo) bool {
+	var count uint32
+	for _, gi := range getGpuInstances(d) {
+		if gi.Info.ProfileId == info.Id {
+			count++
+		}
+	}
+	return count < d.Config.MIGProfiles.GpuInstanceProfiles[int(info.Id)].InstanceCount
+}
+
+func usedComputeInstanceSlices(gi *server.GpuInstance) uint32 {
+	gi.RLock()
+	defer gi.RUnlock()
+	var used uint32
+	for ci := range gi.ComputeInstances {
+		used += gi.MIGProfiles.ComputeInstanceProfiles[int(gi.Info.ProfileId)][int(ci.Info.ProfileId)].SliceCount
+	}
+	return used
+}
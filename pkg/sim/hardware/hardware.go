@@ -0,0 +1,349 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package hardware describes the MIG geometry of each MIG-capable GPU model
+// and turns these descriptions into configurations for the go-nvml mock server.
+//
+// Each model is described declaratively in models/<name>.yaml: its PCI device
+// ID, whose product name, architecture and memory are those of the known device
+// (see 'types.LookupDeviceInfo'), and, for every GPU instance profile, the NVML profile ID, how many
+// instances fit on the GPU at once, how much memory each instance gets and
+// where instances can be placed. Compute instance profiles are derived from
+// the slice count of their GPU instance profile. Multiprocessor and engine
+// counts are indicative only, as mig-parted never consults them.
+package hardware
+
+import (
+	"embed"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/gpus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/internal/suggest"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+//go:embed models/*.yaml
+var modelFiles embed.FS
+
+// gpuInstanceProfileSlices maps each NVML GPU instance profile ID to its slice count.
+var gpuInstanceProfileSlices = map[int]uint32{
+	nvml.GPU_INSTANCE_PROFILE_1_SLICE:        1,
+	nvml.GPU_INSTANCE_PROFILE_2_SLICE:        2,
+	nvml.GPU_INSTANCE_PROFILE_3_SLICE:        3,
+	nvml.GPU_INSTANCE_PROFILE_4_SLICE:        4,
+	nvml.GPU_INSTANCE_PROFILE_7_SLICE:        7,
+	nvml.GPU_INSTANCE_PROFILE_8_SLICE:        8,
+	nvml.GPU_INSTANCE_PROFILE_6_SLICE:        6,
+	nvml.GPU_INSTANCE_PROFILE_1_SLICE_REV1:   1,
+	nvml.GPU_INSTANCE_PROFILE_2_SLICE_REV1:   2,
+	nvml.GPU_INSTANCE_PROFILE_1_SLICE_REV2:   1,
+	nvml.GPU_INSTANCE_PROFILE_1_SLICE_GFX:    1,
+	nvml.GPU_INSTANCE_PROFILE_2_SLICE_GFX:    2,
+	nvml.GPU_INSTANCE_PROFILE_4_SLICE_GFX:    4,
+	nvml.GPU_INSTANCE_PROFILE_1_SLICE_NO_ME:  1,
+	nvml.GPU_INSTANCE_PROFILE_2_SLICE_NO_ME:  2,
+	nvml.GPU_INSTANCE_PROFILE_1_SLICE_ALL_ME: 1,
+	nvml.GPU_INSTANCE_PROFILE_2_SLICE_ALL_ME: 2,
+}
+
+// computeInstanceProfileIDs maps a slice count to its NVML compute instance profile ID.
+var computeInstanceProfileIDs = map[uint32]int{
+	1: nvml.COMPUTE_INSTANCE_PROFILE_1_SLICE,
+	2: nvml.COMPUTE_INSTANCE_PROFILE_2_SLICE,
+	3: nvml.COMPUTE_INSTANCE_PROFILE_3_SLICE,
+	4: nvml.COMPUTE_INSTANCE_PROFILE_4_SLICE,
+	6: nvml.COMPUTE_INSTANCE_PROFILE_6_SLICE,
+	7: nvml.COMPUTE_INSTANCE_PROFILE_7_SLICE,
+	8: nvml.COMPUTE_INSTANCE_PROFILE_8_SLICE,
+}
+
+// architectures maps each architecture of a GPU model to its NVML architecture.
+var architectures = map[string]nvml.DeviceArchitecture{
+	"Ampere":    nvml.DEVICE_ARCH_AMPERE,
+	"Hopper":    nvml.DEVICE_ARCH_HOPPER,
+	"Blackwell": nvml.DEVICE_ARCH_BLACKWELL,
+}
+
+// Model describes the MIG geometry of a GPU model. Its product name,
+// architecture and memory are those of the known device with its PCI device ID.
+type Model struct {
+	Name                string               `json:"name"`
+	DeviceID            string               `json:"device-id"`
+	ComputeCapability   string               `json:"compute-capability"`
+	GpuInstanceProfiles []GpuInstanceProfile `json:"gpu-instance-profiles"`
+
+	ProductName  string `json:"-"`
+	Architecture string `json:"-"`
+	MemoryMB     uint64 `json:"-"`
+}
+
+// GpuInstanceProfile describes a GPU instance profile supported by a GPU model.
+type GpuInstanceProfile struct {
+	Name            string     `json:"name"`
+	ID              int        `json:"id"`
+	Count           int        `json:"count"`
+	MemoryMB        uint64     `json:"memory-mb"`
+	Multiprocessors int        `json:"multiprocessors"`
+	CopyEngines     int        `json:"copy-engines"`
+	Decoders        int        `json:"decoders"`
+	Encoders        int        `json:"encoders"`
+	Jpegs           int        `json:"jpegs"`
+	Ofas            int        `json:"ofas"`
+	Placements      Placements `json:"placements"`
+}
+
+// Placements lists the memory slices at which instances of a GPU instance profile can start.
+// All instances of a profile span the same number of memory slices.
+type Placements struct {
+	Size   uint32   `json:"size"`
+	Starts []uint32 `json:"starts"`
+}
+
+var loadModels = sync.OnceValues(func() (map[string]*Model, error) {
+	files, err := modelFiles.ReadDir("models")
+	if err != nil {
+		return nil, err
+	}
+
+	models := make(map[string]*Model)
+	for _, f := range files {
+		data, err := modelFiles.ReadFile(path.Join("models", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m, err := parseModel(data)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %v: %v", f.Name(), err)
+		}
+		if _, exists := models[m.Name]; exists {
+			return nil, fmt.Errorf("error parsing %v: duplicate model '%v'", f.Name(), m.Name)
+		}
+		models[m.Name] = m
+	}
+
+	return models, nil
+})
+
+// Names returns the names of all GPU models with a description.
+func Names() []string {
+	models, _ := loadModels()
+	return slices.Sorted(maps.Keys(models))
+}
+
+// Get returns the description of a GPU model, matching its name case-insensitively.
+func Get(name string) (*Model, error) {
+	models, err := loadModels()
+	if err != nil {
+		return nil, fmt.Errorf("error loading GPU models: %v", err)
+	}
+	for n, m := range models {
+		if strings.EqualFold(n, name) {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown GPU model '%v'%v", name, suggest.DidYouMean(name, Names()))
+}
+
+func parseModel(data []byte) (*Model, error) {
+	var m Model
+	err := yaml.UnmarshalStrict(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+
+	if id, err := m.deviceID(); err == nil {
+		if device, found := types.LookupDeviceInfo(types.NewDeviceIDFromPacked(id)); found {
+			m.ProductName = device.Name
+			m.Architecture = device.Architecture
+			m.MemoryMB = device.MemoryMB
+		}
+	}
+
+	err = m.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Validate checks that a 'Model' describes a GPU that can be mocked.
+func (m *Model) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("no name specified")
+	}
+	id, err := m.deviceID()
+	if err != nil {
+		return fmt.Errorf("invalid device-id '%v': %v", m.DeviceID, err)
+	}
+	if _, found := types.LookupDeviceInfo(types.NewDeviceIDFromPacked(id)); !found {
+		return fmt.Errorf("unknown device-id '%v': not a known MIG capable GPU", m.DeviceID)
+	}
+	if _, ok := architectures[m.Architecture]; !ok {
+		return fmt.Errorf("unsupported architecture '%v'", m.Architecture)
+	}
+	if _, _, err := m.cudaComputeCapability(); err != nil {
+		return fmt.Errorf("invalid compute-capability '%v': %v", m.ComputeCapability, err)
+	}
+	if len(m.GpuInstanceProfiles) == 0 {
+		return fmt.Errorf("no gpu-instance-profiles specified")
+	}
+
+	ids := make(map[int]bool)
+	for i, p := range m.GpuInstanceProfiles {
+		if _, ok := gpuInstanceProfileSlices[p.ID]; !ok {
+			return fmt.Errorf("gpu-instance-profiles[%d]: unknown id '%v'", i, p.ID)
+		}
+		if ids[p.ID] {
+			return fmt.Errorf("gpu-instance-profiles[%d]: duplicate id '%v'", i, p.ID)
+		}
+		ids[p.ID] = true
+		if err := types.AssertValidMigProfileFormat(p.Name); err != nil {
+			return fmt.Errorf("gpu-instance-profiles[%d]: invalid name '%v': %v", i, p.Name, err)
+		}
+		if p.Count <= 0 {
+			return fmt.Errorf("gpu-instance-profiles[%d]: invalid count '%v': must be positive", i, p.Count)
+		}
+		if p.MemoryMB == 0 || p.MemoryMB > m.MemoryMB {
+			return fmt.Errorf("gpu-instance-profiles[%d]: invalid memory-mb '%v': must be between 1 and %v", i, p.MemoryMB, m.MemoryMB)
+		}
+		if p.Placements.Size == 0 {
+			return fmt.Errorf("gpu-instance-profiles[%d]: invalid placements: size must be positive", i)
+		}
+		if len(p.Placements.Starts) < p.Count {
+			return fmt.Errorf("gpu-instance-profiles[%d]: invalid placements: %d starts for a count of %d", i, len(p.Placements.Starts), p.Count)
+		}
+	}
+
+	return nil
+}
+
+// Config returns the configuration of a go-nvml mock GPU with the MIG geometry of the model.
+func (m *Model) Config() gpus.Config {
+	id, _ := m.deviceID()
+	cudaMajor, cudaMinor, _ := m.cudaComputeCapability()
+
+	migProfiles := gpus.MIGProfileConfig{
+		GpuInstanceProfiles:       make(map[int]nvml.GpuInstanceProfileInfo),
+		ComputeInstanceProfiles:   make(map[int]map[int]nvml.ComputeInstanceProfileInfo),
+		GpuInstancePlacements:     make(map[int][]nvml.GpuInstancePlacement),
+		ComputeInstancePlacements: make(map[int]map[int][]nvml.ComputeInstancePlacement),
+	}
+	for _, p := range m.GpuInstanceProfiles {
+		migProfiles.GpuInstanceProfiles[p.ID] = p.info()
+		migProfiles.ComputeInstanceProfiles[p.ID] = make(map[int]nvml.ComputeInstanceProfileInfo)
+		migProfiles.ComputeInstancePlacements[p.ID] = make(map[int][]nvml.ComputeInstancePlacement)
+		for _, start := range p.Placements.Starts {
+			placement := nvml.GpuInstancePlacement{Start: start, Size: p.Placements.Size}
+			migProfiles.GpuInstancePlacements[p.ID] = append(migProfiles.GpuInstancePlacements[p.ID], placement)
+		}
+		for _, ci := range p.computeInstanceProfiles() {
+			migProfiles.ComputeInstanceProfiles[p.ID][int(ci.Id)] = ci
+			for i := range ci.InstanceCount {
+				placement := nvml.ComputeInstancePlacement{Start: i * ci.SliceCount, Size: ci.SliceCount}
+				migProfiles.ComputeInstancePlacements[p.ID][int(ci.Id)] = append(migProfiles.ComputeInstancePlacements[p.ID][int(ci.Id)], placement)
+			}
+		}
+	}
+
+	return gpus.Config{
+		Name:         m.ProductName,
+		Architecture: architectures[m.Architecture],
+		Brand:        nvml.BRAND_NVIDIA,
+		MemoryMB:     m.MemoryMB,
+		CudaMajor:    cudaMajor,
+		CudaMinor:    cudaMinor,
+		PciDeviceId:  id,
+		MIGProfiles:  migProfiles,
+	}
+}
+
+func (m *Model) deviceID() (uint32, error) {
+	id, err := strconv.ParseUint(m.DeviceID, 0, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(id), nil
+}
+
+func (m *Model) cudaComputeCapability() (int, int, error) {
+	major, minor, found := strings.Cut(m.ComputeCapability, ".")
+	if !found {
+		return 0, 0, fmt.Errorf("expected <major>.<minor>")
+	}
+	ma, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, 0, err
+	}
+	mi, err := strconv.Atoi(minor)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ma, mi, nil
+}
+
+func (p *GpuInstanceProfile) slices() uint32 {
+	return gpuInstanceProfileSlices[p.ID]
+}
+
+func (p *GpuInstanceProfile) info() nvml.GpuInstanceProfileInfo {
+	return nvml.GpuInstanceProfileInfo{
+		Id:                  uint32(p.ID),
+		SliceCount:          p.slices(),
+		InstanceCount:       uint32(p.Count),
+		MultiprocessorCount: uint32(p.Multiprocessors),
+		CopyEngineCount:     uint32(p.CopyEngines),
+		DecoderCount:        uint32(p.Decoders),
+		EncoderCount:        uint32(p.Encoders),
+		JpegCount:           uint32(p.Jpegs),
+		OfaCount:            uint32(p.Ofas),
+		MemorySizeMB:        p.MemoryMB,
+	}
+}
+
+// computeInstanceProfiles returns the compute instance profiles available in an
+// instance of the GPU instance profile. As in go-nvlib, a compute instance spans
+// either the whole GPU instance or at most half of its slices rounded up.
+func (p *GpuInstanceProfile) computeInstanceProfiles() []nvml.ComputeInstanceProfileInfo {
+	g := p.slices()
+	var profiles []nvml.ComputeInstanceProfileInfo
+	for _, c := range slices.Sorted(maps.Keys(computeInstanceProfileIDs)) {
+		if c > g || (c < g && c*2 > g+1) {
+			continue
+		}
+		profiles = append(profiles, nvml.ComputeInstanceProfileInfo{
+			Id:                    uint32(computeInstanceProfileIDs[c]),
+			SliceCount:            c,
+			InstanceCount:         g / c,
+			MultiprocessorCount:   uint32(p.Multiprocessors) * c / g,
+			SharedCopyEngineCount: uint32(p.CopyEngines),
+			SharedDecoderCount:    uint32(p.Decoders),
+			SharedEncoderCount:    uint32(p.Encoders),
+			SharedJpegCount:       uint32(p.Jpegs),
+			SharedOfaCount:        uint32(p.Ofas),
+		})
+	}
+	return profiles
+}
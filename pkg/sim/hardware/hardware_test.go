@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package hardware
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+func TestModels(t *testing.T) {
+	_, err := loadModels()
+	require.Nil(t, err, "Unexpected failure loading the embedded GPU models")
+	require.NotEmpty(t, Names())
+
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			model, err := Get(name)
+			require.Nil(t, err)
+			config := model.Config()
+
+			for _, p := range model.GpuInstanceProfiles {
+				// The name of each profile must match the one NVML clients derive from its ID and memory.
+				ciProfileID := computeInstanceProfileIDs[p.slices()]
+				mp, err := types.NewMigProfile(p.ID, ciProfileID, nvml.COMPUTE_INSTANCE_ENGINE_PROFILE_SHARED, p.MemoryMB, model.MemoryMB*1024*1024)
+				require.Nil(t, err)
+				require.Equal(t, p.Name, mp.String(), "Profile name does not match its ID and memory")
+
+				// It must be possible to place 'count' instances of each profile side by side.
+				var placed []nvml.GpuInstancePlacement
+				for _, placement := range config.MIGProfiles.GpuInstancePlacements[p.ID] {
+					if !overlapsAny(placement, placed) {
+						placed = append(placed, placement)
+					}
+				}
+				require.GreaterOrEqual(t, len(placed), p.Count, "Profile %v does not have room for %d instances", p.Name, p.Count)
+
+				// Every GPU instance can be used in full by a single compute instance.
+				ci, exists := config.MIGProfiles.ComputeInstanceProfiles[p.ID][ciProfileID]
+				require.True(t, exists)
+				require.Equal(t, p.slices(), ci.SliceCount)
+			}
+		})
+	}
+}
+
+func TestGetModel(t *testing.T) {
+	model, err := Get("a30-pcie-24gb")
+	require.Nil(t, err)
+	require.Equal(t, "A30-PCIE-24GB", model.Name)
+	require.Equal(t, uint32(0x20B710DE), model.Config().PciDeviceId)
+
+	_, err = Get("A100-SXM4-40G")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "unknown GPU model 'A100-SXM4-40G' (did you mean 'A100-SXM4-40GB'?)")
+}
+
+func TestModelDevice(t *testing.T) {
+	model, err := Get("h100-sxm5-80gb")
+	require.Nil(t, err)
+	require.Equal(t, "NVIDIA H100 80GB HBM3", model.ProductName)
+	require.Equal(t, "Hopper", model.Architecture)
+	require.Equal(t, uint64(81920), model.MemoryMB)
+	require.Equal(t, nvml.DeviceArchitecture(nvml.DEVICE_ARCH_HOPPER), model.Config().Architecture)
+}
+
+func TestComputeInstanceProfiles(t *testing.T) {
+	testCases := []struct {
+		id             int
+		expectedSlices []uint32
+	}{
+		{nvml.GPU_INSTANCE_PROFILE_1_SLICE, []uint32{1}},
+		{nvml.GPU_INSTANCE_PROFILE_2_SLICE, []uint32{1, 2}},
+		{nvml.GPU_INSTANCE_PROFILE_3_SLICE, []uint32{1, 2, 3}},
+		{nvml.GPU_INSTANCE_PROFILE_4_SLICE, []uint32{1, 2, 4}},
+		{nvml.GPU_INSTANCE_PROFILE_7_SLICE, []uint32{1, 2, 3, 4, 7}},
+	}
+	for _, tc := range testCases {
+		p := GpuInstanceProfile{ID: tc.id}
+		var slices []uint32
+		for _, ci := range p.computeInstanceProfiles() {
+			slices = append(slices, ci.SliceCount)
+		}
+		require.Equal(t, tc.expectedSlices, slices, "Unexpected compute instance profiles for GPU instance profile %d", tc.id)
+	}
+}
+
+func TestInvalidModel(t *testing.T) {
+	const header = "name: test\ndevice-id: \"0x20B010DE\"\ncompute-capability: \"8.0\"\n"
+	const profile = "- {name: 1g.5gb, id: 0, count: 7, memory-mb: 4864, placements: {size: 1, starts: [0, 1, 2, 3, 4, 5, 6]}}\n"
+
+	testCases := []struct {
+		description   string
+		model         string
+		expectedError string
+	}{
+		{"Unknown Field", header + "gpu-instance-profiles:\n" + profile + "foo: bar\n", "unknown field"},
+		{"Invalid Device ID", "name: test\ndevice-id: foo\n", "invalid device-id 'foo'"},
+		{"Unknown Device ID", "name: test\ndevice-id: \"0x1DB610DE\"\n", "unknown device-id '0x1DB610DE'"},
+		{"Device Fields", "name: test\ndevice-id: \"0x20B010DE\"\narchitecture: ampere\n", "unknown field"},
+		{"No Profiles", header, "no gpu-instance-profiles specified"},
+		{"Unknown Profile ID", header + "gpu-instance-profiles:\n- {name: 1g.5gb, id: 99, count: 1}\n", "unknown id '99'"},
+		{"Duplicate Profile ID", header + "gpu-instance-profiles:\n" + profile + profile, "duplicate id '0'"},
+		{"Invalid Profile Name", header + "gpu-instance-profiles:\n- {name: 1g, id: 0, count: 1}\n", "invalid name '1g'"},
+		{"Too Few Placements", header + "gpu-instance-profiles:\n- {name: 1g.5gb, id: 0, count: 7, memory-mb: 4864, placements: {size: 1, starts: [0]}}\n", "1 starts for a count of 7"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := parseModel([]byte(tc.model))
+			require.NotNil(t, err, "Unexpected success from parseModel")
+			require.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func overlapsAny(placement nvml.GpuInstancePlacement, placed []nvml.GpuInstancePlacement) bool {
+	for _, p := range placed {
+		if placement.Start < p.Start+p.Size && p.Start < placement.Start+placement.Size {
+			return true
+		}
+	}
+	return false
+}
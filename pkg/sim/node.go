@@ -0,0 +1,288 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/gpus"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/server"
+
+	checkpoint "github.com/NVIDIA/mig-parted/api/checkpoint/v1"
+	"github.com/NVIDIA/mig-parted/pkg/mig/config"
+	"github.com/NVIDIA/mig-parted/pkg/mig/state"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// StateFileSuffix is appended to the path of a node spec to get the default path of its state file.
+const StateFileSuffix = ".state"
+
+// Node is a simulated node whose GPUs are backed by the go-nvml mock server.
+//
+// The MIG state of all GPUs is written to a state file (in the same format as
+// 'nvidia-mig-parted checkpoint') after every change, and read back the next
+// time the node is loaded. The initial MIG state from the spec only applies
+// when no state file exists yet.
+type Node struct {
+	spec      *NodeSpec
+	stateFile string
+	server    *server.Server
+	pci       *simNvpci
+	restoring bool
+}
+
+// Load creates a simulated node from the spec in the file provided.
+//
+// Parsing MIG profile names requires an NVML library, so Load also registers the
+// simulated node with types.SetNvmlLib.
+func Load(file string) (*Node, error) {
+	spec, err := ParseNodeSpecFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing node spec: %v", err)
+	}
+
+	stateFile := file + StateFileSuffix
+	if spec.StateFile != "" {
+		stateFile = spec.StateFile
+		if !filepath.IsAbs(stateFile) {
+			stateFile = filepath.Join(filepath.Dir(file), stateFile)
+		}
+	}
+
+	n, err := New(spec, stateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// New creates a simulated node from the spec provided, persisting its state to 'stateFile'.
+// If 'stateFile' is empty, the state of the node is not persisted.
+func New(spec *NodeSpec, stateFile string) (*Node, error) {
+	err := spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	driverVersion := spec.DriverVersion
+	if driverVersion == "" {
+		driverVersion = DefaultDriverVersion
+	}
+	nvmlVersion := spec.NvmlVersion
+	if nvmlVersion == "" {
+		nvmlVersion = DefaultNvmlVersion
+	}
+	cudaDriverVersion := spec.CudaDriverVersion
+	if cudaDriverVersion == 0 {
+		cudaDriverVersion = DefaultCudaDriverVersion
+	}
+
+	var configs []gpus.Config
+	for _, g := range spec.GPUs {
+		model, _ := getModel(g.Model)
+		for range g.count() {
+			configs = append(configs, model)
+		}
+	}
+
+	n := &Node{
+		spec:      spec,
+		stateFile: stateFile,
+		server:    server.NewServerWithGPUs(driverVersion, nvmlVersion, cudaDriverVersion, configs...),
+		pci:       &simNvpci{},
+	}
+
+	index := 0
+	for _, g := range spec.GPUs {
+		model, _ := getModel(g.Model)
+		subsystemID := g.subsystemID(model)
+		for i := range g.count() {
+			d := n.server.Devices[index].(*server.Device)
+			d.UUID = fmt.Sprintf("GPU-00000000-0000-0000-0000-%012x", index)
+			if len(g.UUIDs) > 0 {
+				d.UUID = g.UUIDs[i]
+			}
+			n.setDeviceMockFuncs(d, subsystemID)
+			n.pci.addDevice(d, subsystemID)
+			index++
+		}
+	}
+
+	types.SetNvmlLib(n.server)
+
+	err = n.restoreOrInitialize()
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// Nvml returns the NVML library of the simulated node.
+func (n *Node) Nvml() nvml.Interface {
+	return n.server
+}
+
+// Nvpci returns the view of the simulated node's GPUs on the PCI bus.
+func (n *Node) Nvpci() nvpci.Interface {
+	return n.pci
+}
+
+// StateFile returns the path of the file the state of the node is persisted to.
+func (n *Node) StateFile() string {
+	return n.stateFile
+}
+
+func (n *Node) restoreOrInitialize() error {
+	migState, err := n.readState()
+	if err != nil {
+		return err
+	}
+
+	n.restoring = true
+	if migState != nil {
+		err = n.restore(migState)
+	} else {
+		err = n.initialize()
+	}
+	n.restoring = false
+	if err != nil {
+		return err
+	}
+
+	if migState == nil {
+		return n.save()
+	}
+	return nil
+}
+
+// initialize applies the initial MIG state from the spec.
+func (n *Node) initialize() error {
+	configManager := config.NewNvmlMigConfigManager(n.server)
+
+	index := 0
+	for _, g := range n.spec.GPUs {
+		for range g.count() {
+			if g.MigEnabled {
+				_, ret := n.server.Devices[index].SetMigMode(nvml.DEVICE_MIG_ENABLE)
+				if ret != nvml.SUCCESS {
+					return fmt.Errorf("error enabling MIG mode on GPU %d: %v", index, ret)
+				}
+			}
+			if len(g.MigDevices) > 0 {
+				err := configManager.SetMigConfig(index, g.MigDevices)
+				if err != nil {
+					return fmt.Errorf("error setting initial MIG devices on GPU %d: %v", index, err)
+				}
+			}
+			index++
+		}
+	}
+
+	return nil
+}
+
+// restore applies the MIG state read from the state file.
+func (n *Node) restore(migState *types.MigState) error {
+	if len(migState.Devices) != len(n.server.Devices) {
+		return fmt.Errorf("state file %v holds %d GPUs but the node spec has %d (remove it to start over)", n.stateFile, len(migState.Devices), len(n.server.Devices))
+	}
+	for _, d := range migState.Devices {
+		if _, ret := n.server.DeviceGetHandleByUUID(d.UUID); ret != nvml.SUCCESS {
+			return fmt.Errorf("state file %v holds unknown GPU %v (remove it to start over)", n.stateFile, d.UUID)
+		}
+	}
+
+	manager := state.NewMigStateManager(n.server)
+	err := manager.RestoreMode(migState)
+	if err != nil {
+		return fmt.Errorf("error restoring MIG mode from %v: %v", n.stateFile, err)
+	}
+	err = manager.RestoreConfig(migState)
+	if err != nil {
+		return fmt.Errorf("error restoring MIG config from %v: %v", n.stateFile, err)
+	}
+
+	return nil
+}
+
+// readState reads the MIG state from the state file, returning nil if there is none.
+func (n *Node) readState() (*types.MigState, error) {
+	if n.stateFile == "" {
+		return nil, nil
+	}
+
+	stateJSON, err := os.ReadFile(n.stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading state file: %v", err)
+	}
+
+	var s checkpoint.State
+	err = json.Unmarshal(stateJSON, &s)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling state file %v: %v", n.stateFile, err)
+	}
+	if s.Version != checkpoint.Version {
+		return nil, fmt.Errorf("unknown version in state file %v: %v", n.stateFile, s.Version)
+	}
+
+	return &s.MigState, nil
+}
+
+// save writes the current MIG state of all GPUs to the state file.
+func (n *Node) save() error {
+	if n.restoring || n.stateFile == "" {
+		return nil
+	}
+
+	migState, err := state.NewMigStateManager(n.server).Fetch()
+	if err != nil {
+		return fmt.Errorf("error fetching MIG state: %v", err)
+	}
+
+	s := checkpoint.State{
+		Version:  checkpoint.Version,
+		MigState: *migState,
+	}
+
+	stateJSON, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling MIG state to json: %v", err)
+	}
+
+	tmp := n.stateFile + ".tmp"
+	err = os.WriteFile(tmp, stateJSON, 0600)
+	if err != nil {
+		return fmt.Errorf("error writing state file: %v", err)
+	}
+	err = os.Rename(tmp, n.stateFile)
+	if err != nil {
+		return fmt.Errorf("error writing state file: %v", err)
+	}
+
+	return nil
+}
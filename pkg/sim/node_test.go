@@ -0,0 +1,212 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/mig-parted/pkg/mig/config"
+	"github.com/NVIDIA/mig-parted/pkg/sim/hardware"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+const testNodeSpec = `
+version: v1
+gpus:
+- model: A100-SXM4-40GB
+  count: 2
+  uuids: [GPU-a, GPU-b]
+  mig-enabled: true
+  mig-devices:
+    1g.5gb: 3
+    2g.10gb: 2
+- model: a30-pcie-24gb
+`
+
+func writeNodeSpec(t *testing.T, spec string) string {
+	file := filepath.Join(t.TempDir(), "node.yaml")
+	err := os.WriteFile(file, []byte(spec), 0600)
+	require.Nil(t, err)
+	return file
+}
+
+func TestLoadNode(t *testing.T) {
+	file := writeNodeSpec(t, testNodeSpec)
+
+	node, err := Load(file)
+	require.Nil(t, err, "Unexpected failure from Load")
+	require.Equal(t, file+StateFileSuffix, node.StateFile())
+
+	count, ret := node.Nvml().DeviceGetCount()
+	require.Equal(t, nvml.SUCCESS, ret)
+	require.Equal(t, 3, count)
+
+	device, ret := node.Nvml().DeviceGetHandleByIndex(1)
+	require.Equal(t, nvml.SUCCESS, ret)
+	uuid, ret := device.GetUUID()
+	require.Equal(t, nvml.SUCCESS, ret)
+	require.Equal(t, "GPU-b", uuid)
+
+	gpus, err := node.Nvpci().GetGPUs()
+	require.Nil(t, err)
+	require.Len(t, gpus, 3)
+	require.Equal(t, "0x20B710DE:0x20B710DE", types.NewDeviceIDWithSubsystem(gpus[2].Device, gpus[2].Vendor, gpus[2].SubsystemDevice, gpus[2].SubsystemVendor).String())
+
+	manager := config.NewNvmlMigConfigManager(node.Nvml())
+	migConfig, err := manager.GetMigConfig(0)
+	require.Nil(t, err, "Unexpected failure from GetMigConfig")
+	require.Equal(t, types.MigConfig{"1g.5gb": 3, "2g.10gb": 2}, migConfig)
+
+	_, err = manager.GetMigConfig(2)
+	require.NotNil(t, err, "Unexpected success from GetMigConfig with MIG mode disabled")
+}
+
+func TestPersistNode(t *testing.T) {
+	file := writeNodeSpec(t, testNodeSpec)
+
+	node, err := Load(file)
+	require.Nil(t, err, "Unexpected failure from Load")
+
+	manager := config.NewNvmlMigConfigManager(node.Nvml())
+	err = manager.SetMigConfig(1, types.MigConfig{"3g.20gb": 2})
+	require.Nil(t, err, "Unexpected failure from SetMigConfig")
+
+	device, ret := node.Nvml().DeviceGetHandleByIndex(2)
+	require.Equal(t, nvml.SUCCESS, ret)
+	ret, _ = device.SetMigMode(nvml.DEVICE_MIG_ENABLE)
+	require.Equal(t, nvml.SUCCESS, ret)
+
+	// Reloading the node restores the state left behind rather than the initial state from the spec.
+	node, err = Load(file)
+	require.Nil(t, err, "Unexpected failure from Load")
+
+	manager = config.NewNvmlMigConfigManager(node.Nvml())
+	migConfig, err := manager.GetMigConfig(0)
+	require.Nil(t, err)
+	require.Equal(t, types.MigConfig{"1g.5gb": 3, "2g.10gb": 2}, migConfig)
+	migConfig, err = manager.GetMigConfig(1)
+	require.Nil(t, err)
+	require.Equal(t, types.MigConfig{"3g.20gb": 2}, migConfig)
+	migConfig, err = manager.GetMigConfig(2)
+	require.Nil(t, err)
+	require.Empty(t, migConfig)
+
+	// A state file that no longer matches the spec is rejected.
+	err = os.WriteFile(file, []byte("version: v1\ngpus:\n- model: A100-SXM4-40GB\n"), 0600)
+	require.Nil(t, err)
+	_, err = Load(file)
+	require.NotNil(t, err, "Unexpected success from Load with a stale state file")
+}
+
+func TestNodeResourceLimits(t *testing.T) {
+	node, err := New(&NodeSpec{
+		Version: Version,
+		GPUs:    []GPUSpec{{Model: "A100-SXM4-40GB", MigEnabled: true}},
+	}, "")
+	require.Nil(t, err, "Unexpected failure from New")
+
+	device, ret := node.Nvml().DeviceGetHandleByIndex(0)
+	require.Equal(t, nvml.SUCCESS, ret)
+
+	info, ret := device.GetGpuInstanceProfileInfo(nvml.GPU_INSTANCE_PROFILE_3_SLICE)
+	require.Equal(t, nvml.SUCCESS, ret)
+
+	var gis []nvml.GpuInstance
+	for range 2 {
+		gi, ret := device.CreateGpuInstance(&info)
+		require.Equal(t, nvml.SUCCESS, ret)
+		gis = append(gis, gi)
+	}
+	_, ret = device.CreateGpuInstance(&info)
+	require.Equal(t, nvml.ERROR_INSUFFICIENT_RESOURCES, ret)
+
+	placement := nvml.GpuInstancePlacement{Start: 1, Size: 4}
+	_, ret = device.CreateGpuInstanceWithPlacement(&info, &placement)
+	require.Equal(t, nvml.ERROR_INVALID_ARGUMENT, ret)
+
+	ciInfo, ret := gis[0].GetComputeInstanceProfileInfo(nvml.COMPUTE_INSTANCE_PROFILE_2_SLICE, nvml.COMPUTE_INSTANCE_ENGINE_PROFILE_SHARED)
+	require.Equal(t, nvml.SUCCESS, ret)
+	_, ret = gis[0].CreateComputeInstance(&ciInfo)
+	require.Equal(t, nvml.SUCCESS, ret)
+	_, ret = gis[0].CreateComputeInstance(&ciInfo)
+	require.Equal(t, nvml.ERROR_INSUFFICIENT_RESOURCES, ret)
+
+	ret = gis[0].Destroy()
+	require.Equal(t, nvml.ERROR_IN_USE, ret)
+
+	ret, _ = device.SetMigMode(nvml.DEVICE_MIG_DISABLE)
+	require.Equal(t, nvml.ERROR_IN_USE, ret)
+}
+
+func TestModels(t *testing.T) {
+	for _, name := range Models() {
+		t.Run(name, func(t *testing.T) {
+			node, err := New(&NodeSpec{
+				Version: Version,
+				GPUs:    []GPUSpec{{Model: name, MigEnabled: true}},
+			}, "")
+			require.Nil(t, err, "Unexpected failure from New")
+
+			model, err := hardware.Get(name)
+			require.Nil(t, err)
+
+			// Every GPU instance profile can be applied and exported at its full count, but no more.
+			manager := config.NewNvmlMigConfigManager(node.Nvml())
+			for _, p := range model.GpuInstanceProfiles {
+				migConfig := types.MigConfig{p.Name: p.Count}
+				err = manager.SetMigConfig(0, migConfig)
+				require.Nil(t, err, "Unexpected failure from SetMigConfig with %v", migConfig)
+
+				exported, err := manager.GetMigConfig(0)
+				require.Nil(t, err, "Unexpected failure from GetMigConfig")
+				require.Equal(t, migConfig, exported)
+
+				err = manager.SetMigConfig(0, types.MigConfig{p.Name: p.Count + 1})
+				require.NotNil(t, err, "Unexpected success from SetMigConfig with %d %v devices", p.Count+1, p.Name)
+			}
+		})
+	}
+}
+
+func TestInvalidNodeSpec(t *testing.T) {
+	testCases := []struct {
+		description   string
+		spec          string
+		expectedError string
+	}{
+		{"Unknown Version", "version: v2\ngpus: [{model: A100-SXM4-40GB}]", "unknown version"},
+		{"No GPUs", "version: v1\ngpus: []", "no GPUs specified"},
+		{"Unknown Field", "version: v1\ngpus: [{model: A100-SXM4-40GB, mig-enable: true}]", "unknown field"},
+		{"Unknown Model", "version: v1\ngpus: [{model: A100-SXM4-40G}]", "unknown GPU model 'A100-SXM4-40G' (did you mean 'A100-SXM4-40GB'?)"},
+		{"UUID Count", "version: v1\ngpus: [{model: A30-PCIE-24GB, count: 2, uuids: [GPU-a]}]", "1 uuids specified for 2 GPUs"},
+		{"Duplicate UUID", "version: v1\ngpus: [{model: A30-PCIE-24GB, count: 2, uuids: [GPU-a, GPU-a]}]", "duplicate uuid 'GPU-a'"},
+		{"MIG Devices Without MIG", "version: v1\ngpus: [{model: A30-PCIE-24GB, mig-devices: {1g.6gb: 4}}]", "mig-devices specified with mig-enabled false"},
+		{"Invalid Subsystem ID", "version: v1\ngpus: [{model: A30-PCIE-24GB, subsystem-id: foo}]", "invalid subsystem-id 'foo'"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			_, err := ParseNodeSpecFile(writeNodeSpec(t, tc.spec))
+			require.NotNil(t, err, "Unexpected success from ParseNodeSpecFile")
+			require.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
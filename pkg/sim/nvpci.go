@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/server"
+
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// simNvpci lists the GPUs of a simulated node as if they were found on the PCI bus.
+// None of the devices have a sysfs path, so they cannot be reset or have their
+// config space or BARs accessed.
+type simNvpci struct {
+	gpus []*nvpci.NvidiaPCIDevice
+}
+
+var _ nvpci.Interface = (*simNvpci)(nil)
+
+func (p *simNvpci) addDevice(d *server.Device, subsystem types.DeviceID) {
+	id := types.NewDeviceIDFromPacked(d.Config.PciDeviceId)
+
+	p.gpus = append(p.gpus, &nvpci.NvidiaPCIDevice{
+		Address:         d.PciBusID,
+		Vendor:          id.Vendor,
+		Class:           nvpci.PCI3dControllerClass,
+		ClassName:       "3D controller",
+		Device:          id.Device,
+		SubsystemVendor: subsystem.Vendor,
+		SubsystemDevice: subsystem.Device,
+		DeviceName:      d.Config.Name,
+		Driver:          "nvidia",
+		IommuGroup:      -1,
+		NumaNode:        -1,
+	})
+}
+
+func (p *simNvpci) GetAllDevices() ([]*nvpci.NvidiaPCIDevice, error) {
+	return p.gpus, nil
+}
+
+func (p *simNvpci) Get3DControllers() ([]*nvpci.NvidiaPCIDevice, error) {
+	return p.gpus, nil
+}
+
+func (p *simNvpci) GetVGAControllers() ([]*nvpci.NvidiaPCIDevice, error) {
+	return nil, nil
+}
+
+func (p *simNvpci) GetNVSwitches() ([]*nvpci.NvidiaPCIDevice, error) {
+	return nil, nil
+}
+
+func (p *simNvpci) GetGPUs() ([]*nvpci.NvidiaPCIDevice, error) {
+	return p.gpus, nil
+}
+
+func (p *simNvpci) GetGPUByIndex(i int) (*nvpci.NvidiaPCIDevice, error) {
+	if i < 0 || i >= len(p.gpus) {
+		return nil, fmt.Errorf("invalid index '%d'", i)
+	}
+	return p.gpus[i], nil
+}
+
+func (p *simNvpci) GetGPUByPciBusID(address string) (*nvpci.NvidiaPCIDevice, error) {
+	for _, gpu := range p.gpus {
+		if gpu.Address == address {
+			return gpu, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find GPU with PCI bus ID '%v'", address)
+}
+
+func (p *simNvpci) GetNvidiaDeviceByPciBusID(address string) (*nvpci.NvidiaPCIDevice, error) {
+	return p.GetGPUByPciBusID(address)
+}
+
+func (p *simNvpci) GetNetworkControllers() ([]*nvpci.NvidiaPCIDevice, error) {
+	return nil, nil
+}
+
+func (p *simNvpci) GetPciBridges() ([]*nvpci.NvidiaPCIDevice, error) {
+	return nil, nil
+}
+
+func (p *simNvpci) GetDPUs() ([]*nvpci.NvidiaPCIDevice, error) {
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package simtest provides the NVML library of simulated nodes to tests, so
+// that code taking an nvml.Interface can be exercised on any GPU model.
+package simtest
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	"github.com/NVIDIA/mig-parted/pkg/sim"
+)
+
+// NewNvml returns the NVML library of a simulated node with the GPUs provided,
+// whose state lives for the duration of the test only.
+func NewNvml(t testing.TB, gpus ...sim.GPUSpec) nvml.Interface {
+	t.Helper()
+
+	node, err := sim.New(&sim.NodeSpec{Version: sim.Version, GPUs: gpus}, "")
+	if err != nil {
+		t.Fatalf("error creating simulated node: %v", err)
+	}
+	return node.Nvml()
+}
+
+// Models returns the spec of one GPU of each of the models provided.
+func Models(models ...string) []sim.GPUSpec {
+	var gpus []sim.GPUSpec
+	for _, model := range models {
+		gpus = append(gpus, sim.GPUSpec{Model: model})
+	}
+	return gpus
+}
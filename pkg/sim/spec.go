@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock/gpus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/mig-parted/pkg/sim/hardware"
+	"github.com/NVIDIA/mig-parted/pkg/types"
+)
+
+// Version indicates the version of the 'NodeSpec' struct used to describe a simulated node.
+const Version = "v1"
+
+// Defaults for the versions reported by a simulated node.
+const (
+	DefaultDriverVersion     = "550.54.15"
+	DefaultNvmlVersion       = "12.550.54.15"
+	DefaultCudaDriverVersion = 12040
+)
+
+// NodeSpec describes the GPUs of a simulated node and their initial MIG state.
+type NodeSpec struct {
+	Version           string    `json:"version"`
+	DriverVersion     string    `json:"driver-version,omitempty"`
+	NvmlVersion       string    `json:"nvml-version,omitempty"`
+	CudaDriverVersion int       `json:"cuda-driver-version,omitempty"`
+	StateFile         string    `json:"state-file,omitempty"`
+	GPUs              []GPUSpec `json:"gpus"`
+}
+
+// GPUSpec describes a group of identical GPUs on a simulated node.
+type GPUSpec struct {
+	Model       string          `json:"model"`
+	Count       int             `json:"count,omitempty"`
+	UUIDs       []string        `json:"uuids,omitempty"`
+	SubsystemID string          `json:"subsystem-id,omitempty"`
+	MigEnabled  bool            `json:"mig-enabled,omitempty"`
+	MigDevices  types.MigConfig `json:"mig-devices,omitempty"`
+}
+
+// Models returns the names of all GPU models that can be simulated.
+func Models() []string {
+	return hardware.Names()
+}
+
+// ParseNodeSpecFile reads and validates the description of a simulated node from a YAML file.
+func ParseNodeSpecFile(file string) (*NodeSpec, error) {
+	specYaml, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read error: %v", err)
+	}
+
+	var spec NodeSpec
+	err = yaml.UnmarshalStrict(specYaml, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// Validate checks that a 'NodeSpec' describes a node that can be simulated.
+func (s *NodeSpec) Validate() error {
+	if s.Version != Version {
+		return fmt.Errorf("unknown version: %v", s.Version)
+	}
+	if len(s.GPUs) == 0 {
+		return fmt.Errorf("no GPUs specified")
+	}
+
+	uuids := make(map[string]bool)
+	for i, g := range s.GPUs {
+		if _, err := getModel(g.Model); err != nil {
+			return fmt.Errorf("gpus[%d]: %v", i, err)
+		}
+		if g.Count < 0 {
+			return fmt.Errorf("gpus[%d]: invalid count '%v': must not be negative", i, g.Count)
+		}
+		if len(g.UUIDs) > 0 && len(g.UUIDs) != g.count() {
+			return fmt.Errorf("gpus[%d]: %d uuids specified for %d GPUs", i, len(g.UUIDs), g.count())
+		}
+		for _, uuid := range g.UUIDs {
+			if uuids[uuid] {
+				return fmt.Errorf("gpus[%d]: duplicate uuid '%v'", i, uuid)
+			}
+			uuids[uuid] = true
+		}
+		if g.SubsystemID != "" {
+			if _, err := types.NewDeviceIDFromString(g.SubsystemID); err != nil {
+				return fmt.Errorf("gpus[%d]: invalid subsystem-id '%v': %v", i, g.SubsystemID, err)
+			}
+		}
+		if len(g.MigDevices) > 0 && !g.MigEnabled {
+			return fmt.Errorf("gpus[%d]: mig-devices specified with mig-enabled false", i)
+		}
+		if err := g.MigDevices.AssertValidFormat(); err != nil {
+			return fmt.Errorf("gpus[%d]: invalid mig-devices: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// count returns the number of GPUs in the group, which defaults to 1.
+func (g *GPUSpec) count() int {
+	if g.Count == 0 {
+		return 1
+	}
+	return g.Count
+}
+
+// subsystemID returns the PCI subsystem ID of the GPUs in the group, which defaults to their device ID.
+func (g *GPUSpec) subsystemID(model gpus.Config) types.DeviceID {
+	if g.SubsystemID == "" {
+		return types.NewDeviceIDFromPacked(model.PciDeviceId)
+	}
+	id, _ := types.NewDeviceIDFromString(g.SubsystemID)
+	return id.Primary()
+}
+
+func getModel(name string) (gpus.Config, error) {
+	model, err := hardware.Get(name)
+	if err != nil {
+		return gpus.Config{}, err
+	}
+	return model.Config(), nil
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	log "github.com/sirupsen/logrus"
+)
+
+// CanonicalMigConfig is a 'MigConfig' whose profiles are named as a specific
+// GPU names them, without any profile of count 0. Unlike those of a
+// 'MigConfig', the profiles of two 'CanonicalMigConfig's for the same GPU
+// compare equal if they name the same MIG device (e.g. "1g.5gb" and "1c.1g.5gb").
+type CanonicalMigConfig MigConfig
+
+// NewCanonicalMigConfig names the profiles of a 'MigConfig' as the profiles
+// of a GPU are named. See 'CanonicalMigProfileName'.
+func NewCanonicalMigConfig(config MigConfig, profiles []nvdev.MigProfile) (CanonicalMigConfig, error) {
+	canonical := make(CanonicalMigConfig)
+	for k, v := range config {
+		if v == 0 {
+			continue
+		}
+		name, err := CanonicalMigProfileName(k, profiles)
+		if err != nil {
+			return nil, err
+		}
+		canonical[name] += v
+	}
+	return canonical, nil
+}
+
+// CanonicalGpuInstancesConfig is a 'GpuInstancesConfig' whose GPU instance and
+// compute instance profiles are named as a specific GPU names them. See
+// 'CanonicalMigConfig'.
+type CanonicalGpuInstancesConfig GpuInstancesConfig
+
+// NewCanonicalGpuInstancesConfig names the profiles of a 'GpuInstancesConfig'
+// as the profiles of a GPU are named. See 'CanonicalMigProfileName'.
+func NewCanonicalGpuInstancesConfig(config GpuInstancesConfig, profiles []nvdev.MigProfile) (CanonicalGpuInstancesConfig, error) {
+	canonical := make(CanonicalGpuInstancesConfig, 0, len(config))
+	for _, g := range config {
+		profile, err := CanonicalMigProfileName(g.Profile, profiles)
+		if err != nil {
+			return nil, err
+		}
+		var computeInstances []string
+		for _, ci := range g.ComputeInstances {
+			name, err := CanonicalMigProfileName(ci, profiles)
+			if err != nil {
+				return nil, err
+			}
+			computeInstances = append(computeInstances, name)
+		}
+		// A single compute instance spanning the GPU instance is left unset, as
+		// 'GetComputeInstances' defaults to it.
+		if len(computeInstances) == 1 && computeInstances[0] == profile {
+			computeInstances = nil
+		}
+		canonical = append(canonical, GpuInstanceConfig{
+			Profile:          profile,
+			Placement:        g.Placement,
+			ComputeInstances: computeInstances,
+		})
+	}
+	return canonical, nil
+}
+
+// CanonicalMigProfileName returns the name of the profile of a GPU that a MIG
+// profile name refers to. Names match a profile as 'nvdev.MigProfile.Matches'
+// does (e.g. "1c.1g.5gb" matches "1g.5gb"). A name that matches no profile
+// refers to the profile with the same compute and memory slices and
+// attributes whose memory is closest to it, as the memory of a profile can be
+// rounded differently by different drivers (e.g. "1g.12gb" for "1g.10gb"), as
+// long as their memory only differs by a rounding (see 'maxMemoryRoundingGB').
+func CanonicalMigProfileName(name string, profiles []nvdev.MigProfile) (string, error) {
+	for _, p := range profiles {
+		if p.Matches(name) {
+			return p.String(), nil
+		}
+	}
+
+	gb, withGB, err := splitMigProfileMemory(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid MIG profile %q: %v", name, err)
+	}
+
+	var closest []string
+	closestDistance := -1
+	for _, p := range profiles {
+		info := p.GetInfo()
+		if !p.Matches(withGB(info.GB)) {
+			continue
+		}
+		distance := max(gb, info.GB) - min(gb, info.GB)
+		if distance > maxMemoryRoundingGB(info.GB) {
+			continue
+		}
+		switch {
+		case closestDistance == -1 || distance < closestDistance:
+			closest = []string{p.String()}
+			closestDistance = distance
+		case distance == closestDistance && !slices.Contains(closest, p.String()):
+			closest = append(closest, p.String())
+		}
+	}
+
+	switch len(closest) {
+	case 0:
+		return "", fmt.Errorf("MIG profile %q not found on this GPU", name)
+	case 1:
+		log.Warnf("MIG profile %q not found on this GPU, using %q whose memory is rounded differently", name, closest[0])
+		return closest[0], nil
+	}
+	return "", fmt.Errorf("MIG profile %q is ambiguous on this GPU: it may refer to any of %v", name, closest)
+}
+
+// maxMemoryRoundingGB returns how much the memory of a MIG profile name may
+// differ from the memory of a profile of 'gb' GB to still refer to it: 2GB,
+// or 20% of its memory for larger profiles.
+func maxMemoryRoundingGB(gb int) int {
+	return max(2, gb/5)
+}
+
+// splitMigProfileMemory returns the memory of a MIG profile name in GB, along
+// with a function naming the same profile with a different memory.
+func splitMigProfileMemory(name string) (int, func(int) string, error) {
+	head, attributes := name, ""
+	if i := strings.IndexAny(name, "+-"); i >= 0 {
+		head, attributes = name[:i], name[i:]
+	}
+	i := strings.LastIndex(head, ".")
+	var gb int
+	if n, err := fmt.Sscanf(head[i+1:], "%dgb", &gb); i < 0 || n != 1 || err != nil {
+		return 0, nil, fmt.Errorf("missing memory size")
+	}
+	withGB := func(gb int) string {
+		return fmt.Sprintf("%v.%dgb%v", head[:i], gb, attributes)
+	}
+	return gb, withGB, nil
+}
+
+// Equals checks if two 'CanonicalMigConfig's hold the same count of each profile.
+func (c CanonicalMigConfig) Equals(config CanonicalMigConfig) bool {
+	return MigConfig(c).Equals(MigConfig(config))
+}
+
+// IsSubsetOf checks if the 'CanonicalMigConfig' is a subset of the provided one.
+func (c CanonicalMigConfig) IsSubsetOf(config CanonicalMigConfig) bool {
+	return MigConfig(c).IsSubsetOf(MigConfig(config))
+}
+
+// Matches checks if the GPU instances currently on a GPU are those of the
+// 'CanonicalGpuInstancesConfig'. See 'GpuInstancesConfig.Matches'.
+func (c CanonicalGpuInstancesConfig) Matches(current CanonicalGpuInstancesConfig) bool {
+	return GpuInstancesConfig(c).Matches(GpuInstancesConfig(current))
+}
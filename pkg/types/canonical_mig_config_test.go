@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"testing"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/stretchr/testify/require"
+)
+
+// testProfiles are some of the MIG profiles of an A100 80GB, whose 1g
+// profiles only differ by memory.
+var testProfiles = []nvdev.MigProfile{
+	nvdev.MigProfileInfo{C: 1, G: 1, GB: 10},
+	nvdev.MigProfileInfo{C: 1, G: 1, GB: 10, Attributes: []string{"me"}},
+	nvdev.MigProfileInfo{C: 1, G: 1, GB: 20},
+	nvdev.MigProfileInfo{C: 1, G: 2, GB: 20},
+	nvdev.MigProfileInfo{C: 2, G: 2, GB: 20},
+	nvdev.MigProfileInfo{C: 3, G: 3, GB: 40},
+}
+
+func TestCanonicalMigProfileName(t *testing.T) {
+	testCases := []struct {
+		description   string
+		name          string
+		expectedName  string
+		expectedError string
+	}{
+		{
+			description:  "Same name",
+			name:         "1g.10gb",
+			expectedName: "1g.10gb",
+		},
+		{
+			description:  "Compute slices of a full GPU instance",
+			name:         "1c.1g.10gb",
+			expectedName: "1g.10gb",
+		},
+		{
+			description:  "Compute instance",
+			name:         "1c.2g.20gb",
+			expectedName: "1c.2g.20gb",
+		},
+		{
+			description:  "Attributes",
+			name:         "1g.10gb+me",
+			expectedName: "1g.10gb+me",
+		},
+		{
+			description:  "Memory rounded up",
+			name:         "1g.12gb",
+			expectedName: "1g.10gb",
+		},
+		{
+			description:  "Memory rounded down",
+			name:         "3g.39gb",
+			expectedName: "3g.40gb",
+		},
+		{
+			description:  "Memory rounded with attributes",
+			name:         "1g.12gb+me",
+			expectedName: "1g.10gb+me",
+		},
+		{
+			description:   "Memory between two profiles",
+			name:          "1g.15gb",
+			expectedError: "not found",
+		},
+		{
+			description:   "Memory of another profile",
+			name:          "3g.20gb",
+			expectedError: "not found",
+		},
+		{
+			description:   "Unknown profile",
+			name:          "4g.40gb",
+			expectedError: "not found",
+		},
+		{
+			description:   "Unknown attribute",
+			name:          "2g.20gb+me",
+			expectedError: "not found",
+		},
+		{
+			description:   "Invalid name",
+			name:          "1g",
+			expectedError: "invalid MIG profile",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			name, err := CanonicalMigProfileName(tc.name, testProfiles)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedName, name)
+		})
+	}
+}
+
+func TestCanonicalMigProfileNameAmbiguous(t *testing.T) {
+	profiles := []nvdev.MigProfile{
+		nvdev.MigProfileInfo{C: 1, G: 1, GB: 10},
+		nvdev.MigProfileInfo{C: 1, G: 1, GB: 12},
+	}
+	_, err := CanonicalMigProfileName("1g.11gb", profiles)
+	require.ErrorContains(t, err, "ambiguous")
+}
+
+func TestCanonicalMigConfig(t *testing.T) {
+	testCases := []struct {
+		description string
+		config      MigConfig
+		other       MigConfig
+		equal       bool
+		subset      bool
+	}{
+		{
+			description: "Same names",
+			config:      MigConfig{"1g.10gb": 2, "2g.20gb": 1},
+			other:       MigConfig{"1g.10gb": 2, "2g.20gb": 1},
+			equal:       true,
+			subset:      true,
+		},
+		{
+			description: "Names of the same profiles",
+			config:      MigConfig{"1c.1g.10gb": 2, "2c.2g.20gb": 1},
+			other:       MigConfig{"1g.10gb": 2, "2g.20gb": 1},
+			equal:       true,
+			subset:      true,
+		},
+		{
+			description: "Memory rounded differently",
+			config:      MigConfig{"1g.12gb": 2},
+			other:       MigConfig{"1g.10gb": 2},
+			equal:       true,
+			subset:      true,
+		},
+		{
+			description: "Names of the same profile merged",
+			config:      MigConfig{"1g.10gb": 1, "1c.1g.10gb": 1},
+			other:       MigConfig{"1g.10gb": 2},
+			equal:       true,
+			subset:      true,
+		},
+		{
+			description: "Profiles of count 0",
+			config:      MigConfig{"1g.10gb": 2, "3g.40gb": 0},
+			other:       MigConfig{"1g.10gb": 2},
+			equal:       true,
+			subset:      true,
+		},
+		{
+			description: "Fewer devices",
+			config:      MigConfig{"1c.1g.10gb": 1},
+			other:       MigConfig{"1g.10gb": 2, "2g.20gb": 1},
+			equal:       false,
+			subset:      true,
+		},
+		{
+			description: "Different profiles",
+			config:      MigConfig{"1g.10gb": 2},
+			other:       MigConfig{"1g.20gb": 2},
+			equal:       false,
+			subset:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config, err := NewCanonicalMigConfig(tc.config, testProfiles)
+			require.Nil(t, err)
+			other, err := NewCanonicalMigConfig(tc.other, testProfiles)
+			require.Nil(t, err)
+
+			require.Equal(t, tc.equal, config.Equals(other), "Equals")
+			require.Equal(t, tc.subset, config.IsSubsetOf(other), "IsSubsetOf")
+		})
+	}
+}
+
+func TestCanonicalGpuInstancesConfig(t *testing.T) {
+	placement := 0
+	config := GpuInstancesConfig{
+		{Profile: "3g.39gb", ComputeInstances: []string{"3c.3g.40gb"}},
+		{Profile: "2g.20gb", ComputeInstances: []string{"1c.2g.21gb", "1c.2g.20gb"}},
+		{Profile: "1g.12gb", Placement: &placement},
+	}
+	current := GpuInstancesConfig{
+		{Profile: "1g.10gb", Placement: &placement},
+		{Profile: "2g.20gb", ComputeInstances: []string{"1c.2g.20gb", "1c.2g.20gb"}},
+		{Profile: "3g.40gb"},
+	}
+	require.False(t, config.Matches(current))
+
+	canonical, err := NewCanonicalGpuInstancesConfig(config, testProfiles)
+	require.Nil(t, err)
+	require.Equal(t, CanonicalGpuInstancesConfig{
+		{Profile: "3g.40gb"},
+		{Profile: "2g.20gb", ComputeInstances: []string{"1c.2g.20gb", "1c.2g.20gb"}},
+		{Profile: "1g.10gb", Placement: &placement},
+	}, canonical)
+
+	canonicalCurrent, err := NewCanonicalGpuInstancesConfig(current, testProfiles)
+	require.Nil(t, err)
+	require.True(t, canonical.Matches(canonicalCurrent))
+
+	_, err = NewCanonicalGpuInstancesConfig(GpuInstancesConfig{{Profile: "4g.40gb"}}, testProfiles)
+	require.ErrorContains(t, err, "not found")
+	_, err = NewCanonicalGpuInstancesConfig(GpuInstancesConfig{{Profile: "2g.20gb", ComputeInstances: []string{"1c.2g.30gb"}}}, testProfiles)
+	require.ErrorContains(t, err, "not found")
+}
@@ -136,6 +136,20 @@ func (filter DeviceID) Matches(hardware DeviceID) bool {
 	return true
 }
 
+// DeviceInfo holds the attributes of a GPU that device filters match on.
+// Attributes that could not be resolved are left empty.
+type DeviceInfo struct {
+	ID           DeviceID
+	Name         string
+	Architecture string
+	MemoryMB     uint64
+}
+
+// MemoryGB returns the memory of a 'DeviceInfo' in GB, rounded to the nearest GB.
+func (d DeviceInfo) MemoryGB() uint64 {
+	return (d.MemoryMB + 512) / 1024
+}
+
 func splitRawDeviceID(raw uint64) (uint16, uint16) {
 	// nolint:gosec // raw is parsed as a 32-bit value, so both halves fit in a uint16
 	return uint16(raw >> 16), uint16(raw & 0xFFFF)
@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GpuInstanceConfig declares a GPU instance to create on a GPU, along with the
+// compute instances to create in it. Unlike the profiles of a 'MigConfig', it
+// states which compute instances share a GPU instance.
+type GpuInstanceConfig struct {
+	// Profile is the GPU instance profile (e.g. "3g.20gb").
+	Profile string `json:"profile" yaml:"profile"`
+	// Placement is the first memory slice of the GPU instance, left to the driver if unset.
+	Placement *int `json:"placement,omitempty" yaml:"placement,omitempty"`
+	// ComputeInstances are the compute instance profiles (e.g. "1c.3g.20gb") to
+	// create in the GPU instance. A single compute instance spanning the whole
+	// GPU instance is created if unset.
+	ComputeInstances []string `json:"compute-instances,omitempty" yaml:"compute-instances,flow,omitempty"`
+}
+
+// GpuInstancesConfig holds the GPU instances to create on a GPU.
+type GpuInstancesConfig []GpuInstanceConfig
+
+// GetComputeInstances returns the compute instance profiles of a 'GpuInstanceConfig',
+// which default to a single compute instance spanning the GPU instance.
+func (g GpuInstanceConfig) GetComputeInstances() []string {
+	if len(g.ComputeInstances) == 0 {
+		return []string{g.Profile}
+	}
+	return g.ComputeInstances
+}
+
+// String returns a 'GpuInstanceConfig' as its profile, placement (if any) and
+// compute instances (e.g. "3g.20gb@4[1c.3g.20gb 2c.3g.20gb]").
+func (g GpuInstanceConfig) String() string {
+	s := g.Profile
+	if g.Placement != nil {
+		s += fmt.Sprintf("@%d", *g.Placement)
+	}
+	return s + fmt.Sprint(g.GetComputeInstances())
+}
+
+// AssertValidFormat checks that a 'GpuInstanceConfig' names a GPU instance
+// profile and compute instance profiles that fit in it.
+func (g GpuInstanceConfig) AssertValidFormat() error {
+	err := AssertValidMigProfileFormat(g.Profile)
+	if err != nil {
+		return fmt.Errorf("invalid format for '%v': %v", g.Profile, err)
+	}
+	var giSlices int
+	if _, err := fmt.Sscanf(g.Profile, "%dg.", &giSlices); err != nil {
+		return fmt.Errorf("invalid GPU instance profile '%v': expected a profile such as '3g.20gb'", g.Profile)
+	}
+	if g.Placement != nil && *g.Placement < 0 {
+		return fmt.Errorf("invalid placement for '%v': %v", g.Profile, *g.Placement)
+	}
+
+	used := 0
+	for _, ci := range g.GetComputeInstances() {
+		if ci == g.Profile {
+			used += giSlices
+			continue
+		}
+		var c int
+		prefix, found := strings.CutSuffix(ci, "."+g.Profile)
+		if n, _ := fmt.Sscanf(prefix, "%dc", &c); !found || n != 1 || prefix != fmt.Sprintf("%dc", c) || c < 1 || c >= giSlices {
+			return fmt.Errorf("invalid compute instance profile '%v' for GPU instance '%v'", ci, g.Profile)
+		}
+		used += c
+	}
+	if used > giSlices {
+		return fmt.Errorf("compute instances %v use more than the %d slice(s) of GPU instance '%v'", g.ComputeInstances, giSlices, g.Profile)
+	}
+	return nil
+}
+
+// AssertValidFormat checks to ensure that all of the GPU instances of a 'GpuInstancesConfig' are of a valid format.
+func (c GpuInstancesConfig) AssertValidFormat() error {
+	if len(c) == 0 {
+		return fmt.Errorf("at least one GPU instance is required")
+	}
+	for i, g := range c {
+		err := g.AssertValidFormat()
+		if err != nil {
+			return fmt.Errorf("GPU instance %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// MigConfig returns the number of compute instances of each profile in a
+// 'GpuInstancesConfig', as held in a flat 'MigConfig'.
+func (c GpuInstancesConfig) MigConfig() MigConfig {
+	config := make(MigConfig)
+	for _, g := range c {
+		for _, ci := range g.GetComputeInstances() {
+			config[ci]++
+		}
+	}
+	return config
+}
+
+// Matches checks if the GPU instances currently on a GPU are those of the
+// 'GpuInstancesConfig'. The order of GPU instances and of their compute
+// instances does not matter, nor does the placement of GPU instances
+// declared without one.
+func (c GpuInstancesConfig) Matches(current GpuInstancesConfig) bool {
+	if len(c) != len(current) {
+		return false
+	}
+
+	// Match the GPU instances with a placement first, so that those without
+	// one cannot take the GPU instance they need.
+	var placed, unplaced GpuInstancesConfig
+	for _, g := range c {
+		if g.Placement != nil {
+			placed = append(placed, g)
+		} else {
+			unplaced = append(unplaced, g)
+		}
+	}
+
+	matched := make([]bool, len(current))
+OUTER:
+	for _, g := range append(placed, unplaced...) {
+		for i, cur := range current {
+			if !matched[i] && g.matches(cur) {
+				matched[i] = true
+				continue OUTER
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matches checks if a GPU instance currently on a GPU is the one declared by a 'GpuInstanceConfig'.
+func (g GpuInstanceConfig) matches(current GpuInstanceConfig) bool {
+	if g.Profile != current.Profile {
+		return false
+	}
+	if g.Placement != nil && (current.Placement == nil || *g.Placement != *current.Placement) {
+		return false
+	}
+	return slices.Equal(slices.Sorted(slices.Values(g.GetComputeInstances())), slices.Sorted(slices.Values(current.GetComputeInstances())))
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func placementAt(start int) *int {
+	return &start
+}
+
+func TestGpuInstancesConfigAssertValidFormat(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        GpuInstancesConfig
+		expectedError string
+	}{
+		{
+			description: "Valid",
+			config: GpuInstancesConfig{
+				{Profile: "3g.20gb", Placement: placementAt(4), ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+				{Profile: "1g.5gb+me"},
+			},
+		},
+		{
+			description:   "No GPU instances",
+			config:        GpuInstancesConfig{},
+			expectedError: "at least one GPU instance is required",
+		},
+		{
+			description:   "Compute instance profile as GPU instance profile",
+			config:        GpuInstancesConfig{{Profile: "1c.3g.20gb"}},
+			expectedError: "invalid GPU instance profile '1c.3g.20gb'",
+		},
+		{
+			description:   "Compute instance of another GPU instance",
+			config:        GpuInstancesConfig{{Profile: "3g.20gb", ComputeInstances: []string{"1c.2g.10gb"}}},
+			expectedError: "invalid compute instance profile '1c.2g.10gb'",
+		},
+		{
+			description:   "Too many compute instances",
+			config:        GpuInstancesConfig{{Profile: "3g.20gb", ComputeInstances: []string{"2c.3g.20gb", "2c.3g.20gb"}}},
+			expectedError: "use more than the 3 slice(s)",
+		},
+		{
+			description:   "Negative placement",
+			config:        GpuInstancesConfig{{Profile: "3g.20gb", Placement: placementAt(-1)}},
+			expectedError: "invalid placement",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.config.AssertValidFormat()
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGpuInstancesConfigMigConfig(t *testing.T) {
+	config := GpuInstancesConfig{
+		{Profile: "3g.20gb", ComputeInstances: []string{"1c.3g.20gb", "1c.3g.20gb", "1c.3g.20gb"}},
+		{Profile: "2g.10gb"},
+		{Profile: "1g.5gb"},
+		{Profile: "1g.5gb"},
+	}
+	require.Equal(t, MigConfig{"1c.3g.20gb": 3, "2g.10gb": 1, "1g.5gb": 2}, config.MigConfig())
+}
+
+func TestGpuInstancesConfigMatches(t *testing.T) {
+	current := GpuInstancesConfig{
+		{Profile: "1g.5gb", Placement: placementAt(0)},
+		{Profile: "3g.20gb", Placement: placementAt(4), ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+		{Profile: "1g.5gb", Placement: placementAt(1)},
+	}
+
+	testCases := []struct {
+		description string
+		config      GpuInstancesConfig
+		expected    bool
+	}{
+		{
+			description: "Any order",
+			config: GpuInstancesConfig{
+				{Profile: "3g.20gb", ComputeInstances: []string{"2c.3g.20gb", "1c.3g.20gb"}},
+				{Profile: "1g.5gb"},
+				{Profile: "1g.5gb"},
+			},
+			expected: true,
+		},
+		{
+			description: "Placements",
+			config: GpuInstancesConfig{
+				{Profile: "1g.5gb"},
+				{Profile: "1g.5gb", Placement: placementAt(0)},
+				{Profile: "3g.20gb", Placement: placementAt(4), ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+			},
+			expected: true,
+		},
+		{
+			description: "Other placement",
+			config: GpuInstancesConfig{
+				{Profile: "1g.5gb", Placement: placementAt(2)},
+				{Profile: "1g.5gb"},
+				{Profile: "3g.20gb", ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+			},
+		},
+		{
+			description: "Other compute instances",
+			config: GpuInstancesConfig{
+				{Profile: "1g.5gb"},
+				{Profile: "1g.5gb"},
+				{Profile: "3g.20gb", ComputeInstances: []string{"1c.3g.20gb", "1c.3g.20gb", "1c.3g.20gb"}},
+			},
+		},
+		{
+			description: "Fewer GPU instances",
+			config: GpuInstancesConfig{
+				{Profile: "1g.5gb"},
+				{Profile: "3g.20gb", ComputeInstances: []string{"1c.3g.20gb", "2c.3g.20gb"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.config.Matches(current))
+		})
+	}
+}
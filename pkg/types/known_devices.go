@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"slices"
+	"strings"
+)
+
+// Architectures are the GPU architectures, named as NVML names them (see
+// go-nvlib's 'GetArchitectureAsString').
+var Architectures = []string{
+	"Kepler",
+	"Maxwell",
+	"Pascal",
+	"Volta",
+	"Turing",
+	"Ampere",
+	"Ada Lovelace",
+	"Hopper",
+	"Blackwell",
+	"Rubin",
+}
+
+// knownDevices holds the attributes of the MIG capable GPUs known by their
+// PCI device ID, so that device filters can match them without loading the
+// driver. Names and architectures are those reported by NVML.
+var knownDevices = []DeviceInfo{
+	{ID: NewDeviceID(0x20B0, 0x10DE), Name: "NVIDIA A100-SXM4-40GB", Architecture: "Ampere", MemoryMB: 40960},
+	{ID: NewDeviceID(0x20B2, 0x10DE), Name: "NVIDIA A100-SXM4-80GB", Architecture: "Ampere", MemoryMB: 81920},
+	{ID: NewDeviceID(0x20B5, 0x10DE), Name: "NVIDIA A100 80GB PCIe", Architecture: "Ampere", MemoryMB: 81920},
+	{ID: NewDeviceID(0x20B7, 0x10DE), Name: "NVIDIA A30", Architecture: "Ampere", MemoryMB: 24576},
+	{ID: NewDeviceID(0x20F1, 0x10DE), Name: "NVIDIA A100-PCIE-40GB", Architecture: "Ampere", MemoryMB: 40960},
+	{ID: NewDeviceID(0x2321, 0x10DE), Name: "NVIDIA H100 NVL", Architecture: "Hopper", MemoryMB: 96256},
+	{ID: NewDeviceID(0x2330, 0x10DE), Name: "NVIDIA H100 80GB HBM3", Architecture: "Hopper", MemoryMB: 81920},
+	{ID: NewDeviceID(0x2331, 0x10DE), Name: "NVIDIA H100 PCIe", Architecture: "Hopper", MemoryMB: 81920},
+	{ID: NewDeviceID(0x2335, 0x10DE), Name: "NVIDIA H200", Architecture: "Hopper", MemoryMB: 144384},
+	{ID: NewDeviceID(0x233B, 0x10DE), Name: "NVIDIA H200 NVL", Architecture: "Hopper", MemoryMB: 144384},
+	{ID: NewDeviceID(0x2342, 0x10DE), Name: "NVIDIA GH200 480GB", Architecture: "Hopper", MemoryMB: 98304},
+	{ID: NewDeviceID(0x2348, 0x10DE), Name: "NVIDIA GH200 144G HBM3e", Architecture: "Hopper", MemoryMB: 147456},
+	{ID: NewDeviceID(0x2901, 0x10DE), Name: "NVIDIA B200", Architecture: "Blackwell", MemoryMB: 184320},
+	{ID: NewDeviceID(0x2941, 0x10DE), Name: "NVIDIA GB200", Architecture: "Blackwell", MemoryMB: 190464},
+	{ID: NewDeviceID(0x2BB5, 0x10DE), Name: "NVIDIA RTX PRO 6000 Blackwell Server Edition", Architecture: "Blackwell", MemoryMB: 98304},
+	{ID: NewDeviceID(0x3182, 0x10DE), Name: "NVIDIA B300 SXM6 AC", Architecture: "Blackwell", MemoryMB: 275456},
+	{ID: NewDeviceID(0x31C2, 0x10DE), Name: "NVIDIA GB300", Architecture: "Blackwell", MemoryMB: 284672},
+}
+
+// LookupDeviceInfo returns the attributes of a known GPU from its PCI device
+// ID, ignoring its subsystem ID.
+func LookupDeviceInfo(id DeviceID) (DeviceInfo, bool) {
+	i := slices.IndexFunc(knownDevices, func(d DeviceInfo) bool { return d.ID.Matches(id) })
+	if i < 0 {
+		return DeviceInfo{}, false
+	}
+	return knownDevices[i], true
+}
+
+// LookupArchitecture returns the name of a GPU architecture, matched
+// case-insensitively (e.g. "Hopper" for "hopper").
+func LookupArchitecture(name string) (string, bool) {
+	i := slices.IndexFunc(Architectures, func(a string) bool { return strings.EqualFold(a, name) })
+	if i < 0 {
+		return "", false
+	}
+	return Architectures[i], true
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupDeviceInfo(t *testing.T) {
+	device, found := LookupDeviceInfo(NewDeviceIDWithSubsystem(0x2330, 0x10DE, 0x16C1, 0x10DE))
+	require.True(t, found)
+	require.Equal(t, "NVIDIA H100 80GB HBM3", device.Name)
+	require.Equal(t, "Hopper", device.Architecture)
+	require.Equal(t, uint64(80), device.MemoryGB())
+
+	_, found = LookupDeviceInfo(NewDeviceID(0x1DB6, 0x10DE))
+	require.False(t, found)
+}
+
+func TestKnownDevices(t *testing.T) {
+	ids := make(map[DeviceID]bool)
+	for _, device := range knownDevices {
+		require.False(t, ids[device.ID], "Duplicate device %v", device.ID)
+		ids[device.ID] = true
+		require.Contains(t, Architectures, device.Architecture, "Unknown architecture of device %v", device.ID)
+		require.NotEmpty(t, device.Name, "No name for device %v", device.ID)
+		require.NotZero(t, device.MemoryMB, "No memory for device %v", device.ID)
+	}
+}
+
+func TestLookupArchitecture(t *testing.T) {
+	architecture, found := LookupArchitecture("hopper")
+	require.True(t, found)
+	require.Equal(t, "Hopper", architecture)
+
+	architecture, found = LookupArchitecture("ADA LOVELACE")
+	require.True(t, found)
+	require.Equal(t, "Ada Lovelace", architecture)
+
+	_, found = LookupArchitecture("hoppper")
+	require.False(t, found)
+}
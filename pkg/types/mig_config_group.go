@@ -18,6 +18,8 @@ package types
 
 import (
 	"fmt"
+
+	nvdev "github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 )
 
 // MigConfigGroup provides an interface for intaracting with a logical group of 'MigConfig's.
@@ -29,27 +31,47 @@ type MigConfigGroup interface {
 }
 
 // MigConfigGroupBase is a base struct for constructing a full 'MigConfigGroup'.
-// It holds the slice of 'MigConfig' structs associated with the group.
+// It holds the slice of 'MigConfig' structs associated with the group, along
+// with the 'MigProfile's of the device type the group is for.
 type MigConfigGroupBase struct {
-	Configs []MigConfig
+	Configs     []MigConfig
+	DeviceTypes []*MigProfile
 }
 
 // MigConfigGroups holds the mapping from a specific 'DeviceID' to a 'MigConfigGroup'.
 type MigConfigGroups map[DeviceID]MigConfigGroup
 
+// GetDeviceTypes gets the 'MigProfile's of the device type associated with a 'MigConfigGroup'.
+func (m *MigConfigGroupBase) GetDeviceTypes() []*MigProfile {
+	return m.DeviceTypes
+}
+
 // GetPossibleConfigurations gets all possible configurations associated with a 'MigConfigGroup'.
 func (m *MigConfigGroupBase) GetPossibleConfigurations() []MigConfig {
 	return m.Configs
 }
 
 // AssertValidConfiguration checks to ensure that the supplied 'MigConfig' is both valid and part of the 'MigConfigGroup'.
+// Its profiles may be named as any of the names of the 'MigProfile's of the group.
 func (m *MigConfigGroupBase) AssertValidConfiguration(config MigConfig) error {
 	err := config.AssertValidFormat()
 	if err != nil {
 		return fmt.Errorf("invalid MigConfig: %v", err)
 	}
+	var profiles []nvdev.MigProfile
+	for _, p := range m.DeviceTypes {
+		if p != nil {
+			profiles = append(profiles, p)
+		}
+	}
+	canonical, err := NewCanonicalMigConfig(config, profiles)
+	if err != nil {
+		return fmt.Errorf("invalid MigConfig: %v", err)
+	}
 	for _, c := range m.Configs {
-		if config.IsSubsetOf(c) {
+		// The configs of a group are built from its 'MigProfile's, so they
+		// already name them as the group does.
+		if canonical.IsSubsetOf(CanonicalMigConfig(c)) {
 			return nil
 		}
 	}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MigDeviceCountUnbounded is the 'Max' of a 'MigDeviceCount' with no upper
+// bound other than what fits on the GPU.
+const MigDeviceCountUnbounded = -1
+
+// fillCounts are the special count values asking for as many instances of a
+// profile as fit on the GPU.
+var fillCounts = []string{"*", "fill", "max"}
+
+// MigDeviceCount is the requested count of one MIG profile, resolved against
+// the capacity of each GPU at apply time. It is either an exact count, a
+// range of counts, or a percentage of the most instances of the profile a
+// GPU can hold.
+type MigDeviceCount struct {
+	Min     int
+	Max     int
+	Percent int
+}
+
+// MigDeviceRequests holds a map of strings representing a MigProfile to the
+// requested count of that profile type. Unlike a 'MigConfig', its counts may
+// depend on the GPU they are resolved against.
+type MigDeviceRequests map[string]MigDeviceCount
+
+// IsExact checks whether a 'MigDeviceCount' is a fixed count.
+func (c MigDeviceCount) IsExact() bool {
+	return c.Percent == 0 && c.Min == c.Max
+}
+
+// UnmarshalJSON unmarshals a count, one of "*", "fill" or "max", a percentage
+// such as "50%", or a '{min: n, max: m}' range into a 'MigDeviceCount'.
+func (c *MigDeviceCount) UnmarshalJSON(b []byte) error {
+	var count int
+	if err := json.Unmarshal(b, &count); err == nil {
+		*c = MigDeviceCount{Min: count, Max: count}
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		return c.parse(str)
+	}
+
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("expected a count, a percentage, one of %v, or a range", fillCounts)
+	}
+	result := MigDeviceCount{Max: MigDeviceCountUnbounded}
+	for k, v := range raw {
+		var bound int
+		if err := json.Unmarshal(v, &bound); err != nil {
+			return fmt.Errorf("invalid value for '%v': %v", k, err)
+		}
+		switch k {
+		case "min":
+			result.Min = bound
+		case "max":
+			result.Max = bound
+		default:
+			return fmt.Errorf("unexpected field: %v", k)
+		}
+	}
+	*c = result
+	return nil
+}
+
+func (c *MigDeviceCount) parse(str string) error {
+	str = strings.TrimSpace(str)
+	if slices.Contains(fillCounts, strings.ToLower(str)) {
+		*c = MigDeviceCount{Min: 0, Max: MigDeviceCountUnbounded}
+		return nil
+	}
+	if percent, found := strings.CutSuffix(str, "%"); found {
+		n, err := strconv.Atoi(strings.TrimSpace(percent))
+		if err != nil {
+			return fmt.Errorf("invalid percentage: %v", str)
+		}
+		*c = MigDeviceCount{Percent: n}
+		return nil
+	}
+	n, err := strconv.Atoi(str)
+	if err != nil {
+		return fmt.Errorf("invalid count: %v", str)
+	}
+	*c = MigDeviceCount{Min: n, Max: n}
+	return nil
+}
+
+// MarshalJSON marshals a 'MigDeviceCount' into the form it was declared in.
+func (c MigDeviceCount) MarshalJSON() ([]byte, error) {
+	v, err := c.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// MarshalYAML marshals a 'MigDeviceCount' into the form it was declared in.
+func (c MigDeviceCount) MarshalYAML() (interface{}, error) {
+	switch {
+	case c.Percent != 0:
+		return fmt.Sprintf("%d%%", c.Percent), nil
+	case c.IsExact():
+		return c.Min, nil
+	case c.Min == 0 && c.Max == MigDeviceCountUnbounded:
+		return "fill", nil
+	case c.Max == MigDeviceCountUnbounded:
+		return map[string]int{"min": c.Min}, nil
+	}
+	return map[string]int{"min": c.Min, "max": c.Max}, nil
+}
+
+// String returns a 'MigDeviceCount' in the form it was declared in.
+func (c MigDeviceCount) String() string {
+	v, _ := c.MarshalYAML()
+	if bounds, ok := v.(map[string]int); ok {
+		if upper, exists := bounds["max"]; exists {
+			return fmt.Sprintf("%d-%d", bounds["min"], upper)
+		}
+		return fmt.Sprintf("%d+", bounds["min"])
+	}
+	return fmt.Sprint(v)
+}
+
+// AssertValidFormat checks that a 'MigDeviceCount' is a valid count, range or percentage.
+func (c MigDeviceCount) AssertValidFormat() error {
+	if c.Percent != 0 {
+		if c.Percent < 1 || c.Percent > 100 {
+			return fmt.Errorf("percentage must be between 1%% and 100%%: %v%%", c.Percent)
+		}
+		return nil
+	}
+	if c.Min < 0 {
+		return fmt.Errorf("negative count: %v", c.Min)
+	}
+	if c.Max != MigDeviceCountUnbounded && c.Max < c.Min {
+		return fmt.Errorf("max count %v is less than min count %v", c.Max, c.Min)
+	}
+	return nil
+}
+
+// AssertValidFormat checks to ensure that all of the 'MigProfiles's and counts making up a 'MigDeviceRequests' are of a valid format.
+func (m MigDeviceRequests) AssertValidFormat() error {
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		err := AssertValidMigProfileFormat(k)
+		if err != nil {
+			return fmt.Errorf("invalid format for '%v': %v", k, err)
+		}
+		err = m[k].AssertValidFormat()
+		if err != nil {
+			return fmt.Errorf("invalid count for '%v': %v", k, err)
+		}
+	}
+	for _, v := range m {
+		if !v.IsExact() || v.Min > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("all counts for all MigProfiles are 0")
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigDeviceCount(t *testing.T) {
+	testCases := []struct {
+		count         string
+		expected      MigDeviceCount
+		expectedError bool
+	}{
+		{count: `2`, expected: MigDeviceCount{Min: 2, Max: 2}},
+		{count: `"fill"`, expected: MigDeviceCount{Min: 0, Max: MigDeviceCountUnbounded}},
+		{count: `"*"`, expected: MigDeviceCount{Min: 0, Max: MigDeviceCountUnbounded}},
+		{count: `"MAX"`, expected: MigDeviceCount{Min: 0, Max: MigDeviceCountUnbounded}},
+		{count: `"25%"`, expected: MigDeviceCount{Percent: 25}},
+		{count: `{"min": 1}`, expected: MigDeviceCount{Min: 1, Max: MigDeviceCountUnbounded}},
+		{count: `{"min": 1, "max": 3}`, expected: MigDeviceCount{Min: 1, Max: 3}},
+		{count: `"lots"`, expectedError: true},
+		{count: `"half%"`, expectedError: true},
+		{count: `{"least": 1}`, expectedError: true},
+		{count: `[1, 3]`, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.count, func(t *testing.T) {
+			var count MigDeviceCount
+			err := json.Unmarshal([]byte(tc.count), &count)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, count)
+
+			b, err := json.Marshal(count)
+			require.NoError(t, err)
+			var roundTrip MigDeviceCount
+			require.NoError(t, json.Unmarshal(b, &roundTrip))
+			require.Equal(t, count, roundTrip)
+		})
+	}
+}
+
+func TestMigDeviceRequestsAssertValidFormat(t *testing.T) {
+	testCases := []struct {
+		description   string
+		requests      MigDeviceRequests
+		expectedError string
+	}{
+		{
+			description: "Valid",
+			requests:    MigDeviceRequests{"3g.20gb": {Min: 1, Max: 1}, "1g.5gb": {Max: MigDeviceCountUnbounded}},
+		},
+		{
+			description:   "Invalid profile",
+			requests:      MigDeviceRequests{"bogus": {Max: MigDeviceCountUnbounded}},
+			expectedError: "invalid format for 'bogus'",
+		},
+		{
+			description:   "Invalid range",
+			requests:      MigDeviceRequests{"1g.5gb": {Min: 3, Max: 2}},
+			expectedError: "max count 2 is less than min count 3",
+		},
+		{
+			description:   "Invalid percentage",
+			requests:      MigDeviceRequests{"1g.5gb": {Percent: 150}},
+			expectedError: "percentage must be between",
+		},
+		{
+			description:   "All counts 0",
+			requests:      MigDeviceRequests{"1g.5gb": {Min: 0, Max: 0}},
+			expectedError: "all counts for all MigProfiles are 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.requests.AssertValidFormat()
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
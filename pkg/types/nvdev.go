@@ -85,6 +85,13 @@ func nvdevParseMigProfile(profile string) (nvdev.MigProfile, error) {
 	return mp, nil
 }
 
+// SetNvmlLib sets the NVML library used to parse and construct 'MigProfile's.
+// By default, the NVML library of the system is used.
+func SetNvmlLib(nvmlLib nvml.Interface) {
+	nvmllib = nvmlLib
+	nvdevlib = nil
+}
+
 func SetMockNVdevlib() {
 	mockDevice := &mock.Device{
 		GetNameFunc: func() (string, nvml.Return) {